@@ -0,0 +1,88 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// IssuerReference refers to a cert-manager issuer
+type IssuerReference struct {
+	// Name of the issuer
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// Kind of the issuer, ClusterIssuer or a namespaced Issuer
+	// +kubebuilder:validation:Enum=ClusterIssuer;Issuer
+	// +kubebuilder:default=ClusterIssuer
+	// +optional
+	Kind string `json:"kind,omitempty"`
+}
+
+// GatewayProfileSpec bundles the Gateway settings of the routes referencing the profile. Settings
+// left empty fall back to the defaults of the route's namespace and the operator.
+type GatewayProfileSpec struct {
+	// IPAMZone is the IPAM zone the Gateway gets its address from
+	// +optional
+	IPAMZone string `json:"ipamZone,omitempty"`
+
+	// Issuer is the cert-manager issuer of the certificates of the Gateway's listeners
+	// +optional
+	Issuer *IssuerReference `json:"issuer,omitempty"`
+
+	// GatewayClassName is the GatewayClass of the Gateway
+	// +optional
+	GatewayClassName string `json:"gatewayClassName,omitempty"`
+
+	// IPFamily selects the address families of the Gateway
+	// +kubebuilder:validation:Enum=IPv4;IPv6;DualStack
+	// +optional
+	IPFamily string `json:"ipFamily,omitempty"`
+
+	// TLSOptions are implementation specific TLS options set on the listeners of the routes
+	// +optional
+	TLSOptions map[string]string `json:"tlsOptions,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope=Cluster
+// +kubebuilder:printcolumn:name="Zone",type=string,JSONPath=`.spec.ipamZone`
+// +kubebuilder:printcolumn:name="Issuer",type=string,JSONPath=`.spec.issuer.name`
+// +kubebuilder:printcolumn:name="Class",type=string,JSONPath=`.spec.gatewayClassName`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// GatewayProfile is a named set of Gateway settings routes refer to with the profile annotation,
+// instead of setting the zone, issuer and class annotations on every route
+type GatewayProfile struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec GatewayProfileSpec `json:"spec,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// GatewayProfileList contains a list of GatewayProfile
+type GatewayProfileList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []GatewayProfile `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&GatewayProfile{}, &GatewayProfileList{})
+}
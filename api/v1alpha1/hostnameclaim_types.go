@@ -0,0 +1,67 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// HostnameClaimSpec gives a hostname to the routes of a namespace
+type HostnameClaimSpec struct {
+	// Hostname is the claimed hostname, e.g. app.example.com or *.apps.example.com
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="hostname is immutable"
+	Hostname string `json:"hostname"`
+
+	// Namespace is the namespace whose routes may use the hostname
+	// +kubebuilder:validation:MinLength=1
+	Namespace string `json:"namespace"`
+
+	// AllowedNamespaces are further namespaces whose routes may use the hostname. The operator adds
+	// the namespaces of colliding routes here with the AllowList hostname collision policy
+	// +optional
+	AllowedNamespaces []string `json:"allowedNamespaces,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope=Cluster
+// +kubebuilder:printcolumn:name="Hostname",type=string,JSONPath=`.spec.hostname`
+// +kubebuilder:printcolumn:name="Namespace",type=string,JSONPath=`.spec.namespace`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// HostnameClaim records which namespace owns a hostname, so routes in other namespaces can't serve
+// it from their Gateways. The operator claims the hostnames of routes as they are reconciled, and
+// platform admins create claims up front to reserve hostnames for a namespace.
+type HostnameClaim struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec HostnameClaimSpec `json:"spec,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// HostnameClaimList contains a list of HostnameClaim
+type HostnameClaimList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []HostnameClaim `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&HostnameClaim{}, &HostnameClaimList{})
+}
@@ -0,0 +1,107 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ManagedGatewayConditionSynced tells whether the operator's last sync of the Gateway succeeded
+const ManagedGatewayConditionSynced = "Synced"
+
+// ManagedGatewayRoute is a route contributing listeners to the Gateway
+type ManagedGatewayRoute struct {
+	// Kind of the route, e.g. HTTPRoute
+	Kind string `json:"kind"`
+
+	// Namespace of the route
+	Namespace string `json:"namespace"`
+
+	// Name of the route
+	Name string `json:"name"`
+
+	// Hostnames the route contributes to the Gateway
+	// +optional
+	Hostnames []string `json:"hostnames,omitempty"`
+
+	// Listeners of the Gateway created for the route's hostnames
+	// +optional
+	Listeners []string `json:"listeners,omitempty"`
+}
+
+// ManagedGatewayStatus is the operator's view of a Gateway it manages
+type ManagedGatewayStatus struct {
+	// AddressClaim is the IPAddressClaim the Gateway's address was claimed with
+	// from the IPAM pool of its zone
+	// +optional
+	AddressClaim string `json:"addressClaim,omitempty"`
+
+	// Address is the address the IPAM provider allocated for the AddressClaim
+	// +optional
+	Address string `json:"address,omitempty"`
+
+	// Routes are the routes contributing listeners to the Gateway
+	// +optional
+	Routes []ManagedGatewayRoute `json:"routes,omitempty"`
+
+	// Listeners is the number of listeners the routes contribute to the Gateway
+	// +optional
+	Listeners int32 `json:"listeners,omitempty"`
+
+	// ObservedGeneration is the generation of the Gateway at the last sync
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// LastSyncTime is when the operator last synced the Gateway
+	// +optional
+	LastSyncTime *metav1.Time `json:"lastSyncTime,omitempty"`
+
+	// Conditions of the Gateway's sync
+	// +listType=map
+	// +listMapKey=type
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Listeners",type=integer,JSONPath=`.status.listeners`
+// +kubebuilder:printcolumn:name="Synced",type=string,JSONPath=`.status.conditions[?(@.type=="Synced")].status`
+// +kubebuilder:printcolumn:name="Last Sync",type=date,JSONPath=`.status.lastSyncTime`
+
+// ManagedGateway records which routes contribute which hostnames and listeners to a Gateway managed
+// by the operator, and the outcome of the operator's last sync. It has the name and namespace of
+// the Gateway and is owned by it.
+type ManagedGateway struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Status ManagedGatewayStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ManagedGatewayList contains a list of ManagedGateway
+type ManagedGatewayList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ManagedGateway `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ManagedGateway{}, &ManagedGatewayList{})
+}
@@ -19,23 +19,38 @@ package main
 import (
 	"crypto/tls"
 	"flag"
+	"fmt"
+	"net"
+	"net/netip"
 	"os"
+	"path/filepath"
+	"strings"
+	"time"
 
 	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
 	// to ensure that exec-entrypoint and run can make use of them.
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
 
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
-	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
 	"sigs.k8s.io/controller-runtime/pkg/metrics/filters"
 	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+	gatewayv1alpha3 "sigs.k8s.io/gateway-api/apis/v1alpha3"
+	gatewayv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
 
+	operatorv1alpha1 "github.com/NorskHelsenett/gatewayapi-operator/api/v1alpha1"
 	"github.com/NorskHelsenett/gatewayapi-operator/internal/controller"
 	// +kubebuilder:scaffold:imports
 )
@@ -48,6 +63,10 @@ var (
 func init() {
 	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
 	utilruntime.Must(gatewayv1.Install(scheme))
+	utilruntime.Must(gatewayv1alpha2.Install(scheme))
+	utilruntime.Must(gatewayv1alpha3.Install(scheme))
+	utilruntime.Must(gatewayv1beta1.Install(scheme))
+	utilruntime.Must(operatorv1alpha1.AddToScheme(scheme))
 
 	// +kubebuilder:scaffold:scheme
 }
@@ -58,16 +77,110 @@ func main() {
 	var metricsCertPath, metricsCertName, metricsCertKey string
 	var webhookCertPath, webhookCertName, webhookCertKey string
 	var enableLeaderElection bool
+	var leaderElectionNamespace string
+	var leaseDuration, renewDeadline, retryPeriod time.Duration
 	var probeAddr string
+	var pprofAddr string
 	var secureMetrics bool
 	var enableHTTP2 bool
+	var enableTLSRoutes bool
+	var enableTCPRoutes bool
+	var enableBackendTLSPolicies bool
+	var enableSecurityPolicies bool
+	var enableBackendTrafficPolicies bool
+	var enableSecurityHeaders bool
+	var hstsMaxAge time.Duration
+	var clientIdleTimeout time.Duration
+	var clientProxyProtocol bool
+	var clientTCPKeepaliveIdleTime time.Duration
+	var dnsPreflight bool
+	var accessLogZones string
+	var accessLogFormat string
+	var accessLogOTLPEndpoint string
+	var enableHTTPSRedirect bool
+	var enableHostnameClaims bool
+	var enableIngressAdoption bool
+	var hostnameCollisionPolicy string
+	var enableManagedGateways bool
+	var enableRouteDefaulting bool
+	var protectManagedGateways bool
+	var manageWebhookCerts bool
+	var webhookCertSecret string
+	var webhookService string
+	var operatorUsers string
+	var tcpPortRangeStart, tcpPortRangeEnd int
+	var listenerPortRangeStart, listenerPortRangeEnd int
+	var createReferenceGrants bool
+	var sharedGatewayNamespace string
+	var configMap string
+	var namespaceGatewayTemplate string
+	var tlsSecretTemplate string
+	var tlsSecretNamespaces string
+	var allowedDomains string
+	var namespaceListenerQuota int
+	var allowedZones string
+	var watchNamespaces string
+	var excludeNamespaces string
+	var namespaceSelector string
+	var routeSelector string
+	var enabledNamespaceSelector string
+	var cacheUnmanagedRoutes bool
+	var tlsOptions string
+	var zoneWildcardDomains string
+	var enableGatewaySharding bool
+	var maxListenersPerGateway int
+	var enableListenerSets bool
+	var gatewayDeletionPolicy string
+	var gatewayDeletionGracePeriod time.Duration
+	var gatewayGCInterval time.Duration
+	var resyncPeriod time.Duration
+	var dryRun bool
+	var auditLog bool
+	var logFormat, logLevel, controllerLogLevels string
+	var gatewayClass string
+	var zoneGatewayClasses string
+	var propagateLabels string
+	var propagateAnnotations string
+	var infrastructureLabels string
+	var zoneAddressRanges string
+	var zoneIPPools string
+	var enableZoneMigration bool
+	var enableIssuerRotation bool
+	var gatewayConflictPolicy string
+	var ipFamily string
+	var certificateMode string
+	var certificateStrategy string
+	var http01Listeners string
+	var listenerNaming string
+	var certificateKeyAlgorithm string
+	var certificateDuration time.Duration
+	var certificateRenewBefore time.Duration
+	var certificateExpiryWarning time.Duration
+	var gatewayProgrammedTimeout time.Duration
+	var gatewayUpdateDelay time.Duration
+	var maxConcurrentReconciles int
+	var operatorShards, operatorShard int
+	var shutdownDrainTimeout time.Duration
+	var requeueBaseDelay, requeueMaxDelay time.Duration
+	var kubeAPIQPS float64
+	var kubeAPIBurst int
 	var tlsOpts []func(*tls.Config)
 	flag.StringVar(&metricsAddr, "metrics-bind-address", "0", "The address the metrics endpoint binds to. "+
 		"Use :8443 for HTTPS or :8080 for HTTP, or leave as 0 to disable the metrics service.")
 	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
+	flag.StringVar(&pprofAddr, "pprof-bind-address", "0", "The address the pprof endpoint binds to, e.g. :8082. "+
+		"Leave as 0 to disable the pprof endpoint.")
 	flag.BoolVar(&enableLeaderElection, "leader-elect", false,
 		"Enable leader election for controller manager. "+
 			"Enabling this will ensure there is only one active controller manager.")
+	flag.StringVar(&leaderElectionNamespace, "leader-election-namespace", "",
+		"The namespace of the leader election Lease. Defaults to the namespace the operator runs in.")
+	flag.DurationVar(&leaseDuration, "leader-election-lease-duration", 15*time.Second,
+		"How long a standby replica waits after the last renewal before it takes over leadership.")
+	flag.DurationVar(&renewDeadline, "leader-election-renew-deadline", 10*time.Second,
+		"How long the leader retries renewing its leadership before it gives it up.")
+	flag.DurationVar(&retryPeriod, "leader-election-retry-period", 2*time.Second,
+		"How often replicas try to acquire or renew leadership.")
 	flag.BoolVar(&secureMetrics, "metrics-secure", true,
 		"If set, the metrics endpoint is served securely via HTTPS. Use --metrics-secure=false to use HTTP instead.")
 	flag.StringVar(&webhookCertPath, "webhook-cert-path", "", "The directory that contains the webhook certificate.")
@@ -79,13 +192,421 @@ func main() {
 	flag.StringVar(&metricsCertKey, "metrics-cert-key", "tls.key", "The name of the metrics server key file.")
 	flag.BoolVar(&enableHTTP2, "enable-http2", false,
 		"If set, HTTP/2 will be enabled for the metrics and webhook servers")
-	opts := zap.Options{
-		Development: true,
-	}
-	opts.BindFlags(flag.CommandLine)
+	flag.BoolVar(&enableTLSRoutes, "enable-tlsroute", false,
+		"If set, TLSRoutes are reconciled. Requires the experimental Gateway API TLSRoute CRD.")
+	flag.BoolVar(&enableTCPRoutes, "enable-tcproute", false,
+		"If set, TCPRoutes are reconciled. Requires the experimental Gateway API TCPRoute CRD.")
+	flag.BoolVar(&enableBackendTLSPolicies, "enable-backendtlspolicy", false,
+		"If set, BackendTLSPolicies are created for routes with the backend-tls-ca annotation. "+
+			"Requires the experimental Gateway API BackendTLSPolicy CRD.")
+	flag.BoolVar(&enableSecurityPolicies, "enable-securitypolicy", false,
+		"If set, Envoy Gateway SecurityPolicies are created for routes with the oidc-issuer, jwt-issuer, "+
+			"basic-auth-secret, cors-allow-origins or allowed-cidrs annotations. Requires Envoy Gateway's SecurityPolicy CRD.")
+	flag.BoolVar(&enableBackendTrafficPolicies, "enable-backendtrafficpolicy", false,
+		"If set, Envoy Gateway BackendTrafficPolicies are created for routes with the rate-limit annotation. "+
+			"Requires Envoy Gateway's BackendTrafficPolicy CRD.")
+	flag.BoolVar(&enableSecurityHeaders, "enable-security-headers", true,
+		"If set, HSTS, X-Content-Type-Options, X-Frame-Options and Referrer-Policy headers are added to every response "+
+			"of managed Gateways through Envoy Gateway ClientTrafficPolicies. Skipped without Envoy Gateway's CRDs.")
+	flag.DurationVar(&hstsMaxAge, "hsts-max-age", 365*24*time.Hour,
+		"The max-age of the Strict-Transport-Security header added with --enable-security-headers.")
+	flag.DurationVar(&clientIdleTimeout, "client-idle-timeout", 0,
+		"The idle timeout of client connections to managed Gateways, set through Envoy Gateway ClientTrafficPolicies. "+
+			"Routes override it with the client-idle-timeout annotation. Zero leaves Envoy Gateway's default.")
+	flag.BoolVar(&clientProxyProtocol, "client-proxy-protocol", false,
+		"If set, managed Gateways expect the PROXY protocol from their clients. Routes override it with the "+
+			"proxy-protocol annotation.")
+	flag.DurationVar(&clientTCPKeepaliveIdleTime, "client-tcp-keepalive-idle-time", 0,
+		"If set, TCP keepalive probes client connections to managed Gateways idle for this long. Routes override it "+
+			"with the tcp-keepalive-idle-time annotation.")
+	flag.BoolVar(&dnsPreflight, "dns-preflight", false,
+		"If set, the hostnames of listeners are resolved before they are added to a managed Gateway, warning with an "+
+			"event and a metric when they have no records or point elsewhere than the Gateway's addresses.")
+	flag.StringVar(&accessLogZones, "access-log-zones", "",
+		"Comma separated IPAM zones whose managed Gateways get access logs through Envoy Gateway EnvoyProxies, * for all "+
+			"zones. Routes override it with the access-log annotation.")
+	flag.StringVar(&accessLogFormat, "access-log-format", string(controller.AccessLogFormatJSON),
+		"The format of the access logs of managed Gateways: text or json.")
+	flag.StringVar(&accessLogOTLPEndpoint, "access-log-otlp-endpoint", "",
+		"The host:port of the OpenTelemetry collector access logs of managed Gateways are sent to. If not set, they are "+
+			"written to Envoy's stdout.")
+	flag.BoolVar(&enableHTTPSRedirect, "enable-https-redirect", false,
+		"If set, every HTTPS hostname also gets an HTTP listener on port 80 redirecting to HTTPS.")
+	flag.BoolVar(&enableHostnameClaims, "enable-hostname-claims", false,
+		"If set, the hostnames of routes are claimed for their namespace with HostnameClaims, and routes asking for "+
+			"hostnames claimed by another namespace are handled by --hostname-collision-policy. Requires the HostnameClaim CRD.")
+	flag.BoolVar(&enableIngressAdoption, "enable-ingress-adoption", false,
+		"If set, HTTPRoutes are generated from Ingresses with the gatewayapi-operator.vitistack.io/adopt-ingress=true "+
+			"annotation, so they are served by the operator's Gateways.")
+	flag.StringVar(&hostnameCollisionPolicy, "hostname-collision-policy", string(controller.HostnameCollisionPolicyReject),
+		"What happens to a route asking for a hostname claimed by another namespace, with --enable-hostname-claims: "+
+			"Reject, or AllowList to add the route's namespace to the namespaces allowed to use the hostname.")
+	flag.BoolVar(&enableManagedGateways, "enable-managed-gateways", false,
+		"If set, every managed Gateway gets a ManagedGateway recording the routes contributing to it and the outcome "+
+			"of its last sync. Requires the ManagedGateway CRD.")
+	flag.BoolVar(&enableRouteDefaulting, "enable-route-defaulting", false,
+		"If set, the mutating webhook for routes is served, filling in the IPAM zone and issuer annotations of routes "+
+			"and attaching routes without parentRefs to their namespace's standard Gateway. Requires the webhook "+
+			"configuration and a serving certificate, see --webhook-cert-path.")
+	flag.BoolVar(&protectManagedGateways, "protect-managed-gateways", false,
+		"If set, the validating webhook for Gateways is served, rejecting the deletion of managed Gateways and changes "+
+			"to the listeners the operator applied by anyone but the operator, unless the Gateway has the "+
+			"gatewayapi-operator.vitistack.io/break-glass annotation. Requires the webhook configuration and a serving certificate.")
+	flag.StringVar(&operatorUsers, "operator-users", "",
+		"Comma separated usernames of the operator, which may change managed Gateways when they are protected, "+
+			"e.g. system:serviceaccount:gatewayapi-operator-system:gatewayapi-operator-controller-manager.")
+	flag.BoolVar(&manageWebhookCerts, "manage-webhook-certs", false,
+		"If set, the operator generates and renews the serving certificate of its webhooks itself, keeping it in "+
+			"--webhook-cert-secret and injecting its CA into the webhook configurations, instead of using cert-manager.")
+	flag.StringVar(&webhookCertSecret, "webhook-cert-secret", "gatewayapi-operator-system/gatewayapi-operator-webhook-server-cert",
+		"The namespace/name of the secret the webhook certificates are kept in when --manage-webhook-certs is set.")
+	flag.StringVar(&webhookService, "webhook-service", "gatewayapi-operator-system/gatewayapi-operator-webhook-service",
+		"The namespace/name of the webhook service the certificate is issued for when --manage-webhook-certs is set.")
+	flag.IntVar(&tcpPortRangeStart, "tcproute-port-range-start", 10000,
+		"The first listener port that can be allocated to TCPRoutes.")
+	flag.IntVar(&tcpPortRangeEnd, "tcproute-port-range-end", 10999,
+		"The last listener port that can be allocated to TCPRoutes.")
+	flag.IntVar(&listenerPortRangeStart, "listener-port-range-start", 8000,
+		"The first listener port routes may ask for with the listener-port annotation, besides 80 and 443.")
+	flag.IntVar(&listenerPortRangeEnd, "listener-port-range-end", 8999,
+		"The last listener port routes may ask for with the listener-port annotation.")
+	flag.BoolVar(&createReferenceGrants, "create-reference-grants", false,
+		"If set, ReferenceGrants are created for routes attaching to Gateways in other namespaces. "+
+			"Otherwise a warning event is emitted on the route when no ReferenceGrant allows the attachment.")
+	flag.StringVar(&configMap, "config-map", "",
+		"The namespace/name of a ConfigMap holding operator defaults that can be changed at runtime, e.g. the "+
+			"GatewayClass, cluster issuer, IPAM zone, TLS secret template, listener ports and Gateway deletion policy. "+
+			"Its keys override the flags. Leave empty to disable.")
+	flag.StringVar(&sharedGatewayNamespace, "shared-gateway-namespace", "",
+		"The central namespace for shared Gateways. Listeners on Gateways in this namespace only admit routes "+
+			"from the namespaces requesting them and use certificates from those namespaces. Leave empty to disable.")
+	flag.StringVar(&namespaceGatewayTemplate, "namespace-gateway-template", "",
+		"Enables gateway-per-namespace mode: routes are attached to one Gateway per namespace named from this "+
+			"template, where {namespace} is replaced by the namespace name. Leave empty to disable.")
+	flag.StringVar(&tlsSecretTemplate, "tls-secret-template", "{hostname}-tls",
+		"The name template of the certificate secrets listeners reference, where {hostname} is replaced by the "+
+			"listener hostname, or wildcard for listeners without a hostname.")
+	flag.StringVar(&tlsSecretNamespaces, "tls-secret-namespaces", "",
+		"Comma separated namespaces routes may put their certificate secrets in with the tls-secret-namespace annotation, "+
+			"e.g. a central certificate store.")
+	flag.StringVar(&allowedDomains, "allowed-domains", "",
+		"Comma separated domains route hostnames must be in, e.g. helsenett.no,nhn.no. Routes asking for other "+
+			"hostnames are rejected. Leave empty to allow every hostname.")
+	flag.StringVar(&allowedZones, "allowed-zones", "",
+		"Comma separated IPAM zones the routes of a namespace may use, e.g. hnet-private. Can be overridden per "+
+			"namespace with the gatewayapi-operator.vitistack.io/allowed-zones annotation. Leave empty to allow every zone.")
+	flag.IntVar(&namespaceListenerQuota, "namespace-listener-quota", 0,
+		"How many hostnames the routes of a namespace may add to Gateways in other namespaces. Routes exceeding it "+
+			"are rejected. Can be overridden per namespace with the gatewayapi-operator.vitistack.io/listener-quota "+
+			"annotation. 0 is unlimited.")
+	flag.StringVar(&watchNamespaces, "watch-namespaces", "",
+		"Comma separated namespaces whose routes the operator manages. Only these namespaces, the shared gateway "+
+			"namespace and the TLS secret namespaces are cached. Leave empty to manage all namespaces.")
+	flag.StringVar(&excludeNamespaces, "exclude-namespaces", "",
+		"Comma separated namespaces whose routes the operator ignores.")
+	flag.StringVar(&namespaceSelector, "namespace-selector", "",
+		"A label selector for the namespaces whose routes the operator manages, e.g. tenant=true. "+
+			"Leave empty to manage all namespaces.")
+	flag.StringVar(&routeSelector, "route-selector", "",
+		"A label selector enrolling routes without the enabled annotation, e.g. gateway.vitistack.io/managed=true. "+
+			"Leave empty to only manage routes with the annotation.")
+	flag.StringVar(&enabledNamespaceSelector, "enabled-namespace-selector", "",
+		"A label selector enrolling all routes in the namespaces it matches without the enabled annotation. "+
+			"Leave empty to only manage routes with the annotation.")
+	flag.BoolVar(&cacheUnmanagedRoutes, "cache-unmanaged-routes", false,
+		"If set, routes the operator doesn't manage are cached in full. By default only their metadata is cached.")
+	flag.StringVar(&tlsOptions, "tls-options", "",
+		"Comma separated list of key=value TLS options set on all listeners, as understood by the Gateway "+
+			"implementation, e.g. a minimum TLS version or ALPN protocols.")
+	flag.StringVar(&zoneWildcardDomains, "zone-wildcard-domains", "",
+		"Comma separated list of zone=domain pairs. Hostnames directly under the domain of their route's IPAM zone "+
+			"share a single wildcard listener and certificate, e.g. hnet-private=apps.example.com.")
+	flag.BoolVar(&enableGatewaySharding, "enable-gateway-sharding", false,
+		"If set, routes that don't fit on their Gateway are moved to Gateway shards named {gateway}-2, {gateway}-3 "+
+			"and so on by rewriting their parentRefs.")
+	flag.IntVar(&maxListenersPerGateway, "max-listeners-per-gateway", 64,
+		"The number of listeners a Gateway can hold before routes are moved to a shard. Gateway API allows at most 64.")
+	flag.BoolVar(&enableListenerSets, "enable-listenersets", false,
+		"If set, listeners are written to XListenerSets attached to the Gateway instead of the Gateway itself. "+
+			"Requires the experimental Gateway API CRDs.")
+	flag.StringVar(&gatewayDeletionPolicy, "gateway-deletion-policy", string(controller.GatewayDeletionPolicyDelete),
+		"What happens to a Gateway when no routes reference it anymore: Delete, Orphan or RetainEmpty. "+
+			"Can be overridden per Gateway with the gatewayapi-operator.vitistack.io/deletion-policy annotation.")
+	flag.DurationVar(&gatewayDeletionGracePeriod, "gateway-deletion-grace-period", 0,
+		"How long a Gateway without routes is kept before it is deleted, so recreated routes keep the Gateway "+
+			"and its address. Can be overridden per Gateway with the gatewayapi-operator.vitistack.io/deletion-ttl annotation.")
+	flag.DurationVar(&gatewayGCInterval, "gateway-gc-interval", 5*time.Minute,
+		"How often managed Gateways without routes are looked for, to clean up Gateways left behind.")
+	flag.DurationVar(&resyncPeriod, "resync-period", time.Hour,
+		"How often all routes are reconciled again, re-ensuring every managed Gateway to catch drift and events "+
+			"missed while the operator was down.")
+	flag.BoolVar(&dryRun, "dry-run", false,
+		"If set, the operator only plans its changes to routes, Gateways and ReferenceGrants and reports them in logs "+
+			"and DryRun events, without writing anything.")
+	flag.BoolVar(&auditLog, "audit-log", false,
+		"If set, every create, update and delete of a Gateway by the operator is recorded by the audit logger.")
+	flag.StringVar(&gatewayClass, "gateway-class", "eg",
+		"The GatewayClass of created Gateways, unless the route or its IPAM zone selects another one.")
+	flag.StringVar(&zoneGatewayClasses, "zone-gateway-classes", "",
+		"Comma separated list of zone=class pairs selecting the GatewayClass for routes in an IPAM zone, "+
+			"e.g. hnet-private=eg,hnet-public=eg-public.")
+	flag.StringVar(&propagateLabels, "propagate-labels", "",
+		"Comma separated list of label keys copied from routes to their Gateway and its infrastructure, e.g. team,cost-center.")
+	flag.StringVar(&propagateAnnotations, "propagate-annotations", "",
+		"Comma separated list of annotation keys copied from routes to their Gateway and its infrastructure.")
+	flag.StringVar(&infrastructureLabels, "infrastructure-labels", "",
+		"Comma separated list of key=value labels set on the infrastructure of managed Gateways, "+
+			"e.g. app.kubernetes.io/part-of=ingress,monitoring=enabled.")
+	flag.StringVar(&zoneAddressRanges, "zone-address-ranges", "",
+		"Comma separated list of zone=CIDR pairs with the address ranges static Gateway addresses must be in, "+
+			"a zone may be listed more than once, e.g. hnet-private=10.10.0.0/16,hnet-private=10.20.0.0/16.")
+	flag.StringVar(&zoneIPPools, "zone-ip-pools", "",
+		"Comma separated list of zone=[group/]Kind/name pairs with the IPAM pools the addresses of the zone's Gateways "+
+			"are claimed from with IPAddressClaims, e.g. hnet-public=GlobalInClusterIPPool/public. The group defaults to "+
+			"ipam.cluster.x-k8s.io.")
+	flag.BoolVar(&enableZoneMigration, "enable-zone-migration", false,
+		"If set, a managed Gateway moves to another IPAM zone once all its routes ask for it, instead of the routes "+
+			"being rejected with a zone mismatch.")
+	flag.BoolVar(&enableIssuerRotation, "enable-issuer-rotation", false,
+		"If set, a managed Gateway switches to another issuer once all its routes ask for it and its certificates are "+
+			"reissued, instead of the routes being rejected with an issuer mismatch.")
+	flag.StringVar(&gatewayConflictPolicy, "gateway-conflict-policy", string(controller.GatewayConflictPolicyReject),
+		"What happens to a route asking for another issuer or IPAM zone than its Gateway has, when the Gateway can't "+
+			"rotate or migrate to it: Reject, FirstWins or Split.")
+	flag.StringVar(&ipFamily, "ip-family", string(controller.IPFamilyIPv4),
+		"The IP family of created Gateways unless the route selects another one: IPv4, IPv6 or DualStack.")
+	flag.StringVar(&certificateMode, "certificate-mode", string(controller.CertificateModeAnnotation),
+		"How certificates for TLS listeners are requested: annotation sets the cluster issuer annotation on the Gateway "+
+			"for cert-manager, certificate makes the operator create a cert-manager Certificate per listener hostname.")
+	flag.StringVar(&certificateStrategy, "certificate-strategy", string(controller.CertificateStrategyPerHostname),
+		"How many certificates the listeners of a Gateway use: per-hostname gives every hostname its own certificate, "+
+			"per-gateway makes all listeners share one certificate with all hostnames as SANs.")
+	flag.StringVar(&http01Listeners, "acme-http01-listeners", string(controller.HTTP01ListenersDisabled),
+		"When Gateways get port 80 listeners for cert-manager's ACME HTTP-01 solver routes, for hostnames whose issuer "+
+			"uses HTTP-01: disabled, always, or issuance to only add them while a Challenge is pending.")
+	flag.StringVar(&listenerNaming, "listener-naming", string(controller.ListenerNamingHostname),
+		"How listener section names are derived from hostnames: hostname uses the hostname as is, sanitized keeps "+
+			"names within 63 valid characters, shortening longer names with a hash suffix.")
+	flag.StringVar(&certificateKeyAlgorithm, "certificate-key-algorithm", "",
+		"The private key algorithm of Certificates created in certificate mode: RSA, ECDSA or Ed25519. "+
+			"Defaults to the cert-manager default.")
+	flag.DurationVar(&certificateDuration, "certificate-duration", 0,
+		"The requested lifetime of Certificates created in certificate mode. Defaults to the cert-manager default.")
+	flag.DurationVar(&certificateRenewBefore, "certificate-renew-before", 0,
+		"How long before expiry Certificates created in certificate mode are renewed. Defaults to the cert-manager default.")
+	flag.DurationVar(&certificateExpiryWarning, "certificate-expiry-warning", 14*24*time.Hour,
+		"How long before expiry the certificate of a Gateway listener is reported with a warning event.")
+	flag.DurationVar(&gatewayProgrammedTimeout, "gateway-programmed-timeout", 5*time.Minute,
+		"How long routes wait for their Gateway to be programmed before they are reported as failed. "+
+			"0 doesn't wait for Gateways to be programmed.")
+	flag.DurationVar(&gatewayUpdateDelay, "gateway-update-delay", 2*time.Second,
+		"How long route changes are collected before the listeners of their Gateways are updated, so a burst of "+
+			"changes updates each Gateway once. 0 updates Gateways right away.")
+	flag.IntVar(&maxConcurrentReconciles, "max-concurrent-reconciles", 1,
+		"The number of routes, and of Gateways, each controller reconciles in parallel. Reconciles changing the same "+
+			"Gateway are serialized.")
+	flag.IntVar(&operatorShards, "operator-shards", 1,
+		"The number of operator instances sharing the Gateways of the cluster. Each Gateway, and the routes referencing "+
+			"it first, is handled by the instance its namespace and name hash to.")
+	flag.IntVar(&operatorShard, "operator-shard", 0,
+		"The index of this operator instance, from 0 to --operator-shards minus 1.")
+	flag.DurationVar(&shutdownDrainTimeout, "shutdown-drain-timeout", 5*time.Second,
+		"How long reconciles in flight may keep running to finish their Gateway updates when the operator shuts down. "+
+			"Keep it below the pod's terminationGracePeriodSeconds.")
+	flag.DurationVar(&requeueBaseDelay, "requeue-base-delay", 5*time.Millisecond,
+		"The delay before a failed reconcile is retried the first time. It doubles with every failure of the same object.")
+	flag.DurationVar(&requeueMaxDelay, "requeue-max-delay", 1000*time.Second,
+		"The longest delay before a failed reconcile is retried.")
+	flag.Float64Var(&kubeAPIQPS, "kube-api-qps", 20,
+		"The number of requests per second the operator sends to the Kubernetes API on average.")
+	flag.IntVar(&kubeAPIBurst, "kube-api-burst", 30,
+		"The number of requests the operator may send to the Kubernetes API in a burst above --kube-api-qps.")
+	flag.StringVar(&logFormat, "log-format", string(controller.LogFormatConsole),
+		"The format of the operator's logs: console or json.")
+	flag.StringVar(&logLevel, "log-level", "info",
+		"The level of the operator's logs: debug, info, error or a verbosity, higher numbers being more verbose.")
+	flag.StringVar(&controllerLogLevels, "controller-log-levels", "",
+		"Comma separated controller=level pairs overriding the log level of single controllers, e.g. gateway=debug. "+
+			"Other loggers, e.g. audit, can be given by name.")
 	flag.Parse()
 
-	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts)))
+	logging, err := controller.NewDynamicLogger(controller.LogFormat(logFormat), logLevel, controllerLogLevels)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "invalid log configuration: %v\n", err)
+		os.Exit(1)
+	}
+	ctrl.SetLogger(logging.Logger())
+
+	zoneClasses, err := parseKeyValuePairs(zoneGatewayClasses)
+	if err != nil {
+		setupLog.Error(err, "invalid zone to GatewayClass mapping", "zone-gateway-classes", zoneGatewayClasses)
+		os.Exit(1)
+	}
+	listenerTLSOptions, err := parseKeyValuePairs(tlsOptions)
+	if err != nil {
+		setupLog.Error(err, "invalid TLS options", "tls-options", tlsOptions)
+		os.Exit(1)
+	}
+	wildcardDomains, err := parseKeyValuePairs(zoneWildcardDomains)
+	if err != nil {
+		setupLog.Error(err, "invalid zone wildcard domains", "zone-wildcard-domains", zoneWildcardDomains)
+		os.Exit(1)
+	}
+	infraLabels, err := parseKeyValuePairs(infrastructureLabels)
+	if err != nil {
+		setupLog.Error(err, "invalid infrastructure labels", "infrastructure-labels", infrastructureLabels)
+		os.Exit(1)
+	}
+	ipPools, err := parseZoneIPPools(zoneIPPools)
+	if err != nil {
+		setupLog.Error(err, "invalid zone IPAM pools", "zone-ip-pools", zoneIPPools)
+		os.Exit(1)
+	}
+	addressRanges, err := parseZoneAddressRanges(zoneAddressRanges)
+	if err != nil {
+		setupLog.Error(err, "invalid zone address ranges", "zone-address-ranges", zoneAddressRanges)
+		os.Exit(1)
+	}
+	if !controller.GatewayDeletionPolicy(gatewayDeletionPolicy).IsValid() {
+		setupLog.Error(nil, "invalid Gateway deletion policy", "gateway-deletion-policy", gatewayDeletionPolicy)
+		os.Exit(1)
+	}
+	if !controller.HostnameCollisionPolicy(hostnameCollisionPolicy).IsValid() {
+		setupLog.Error(nil, "invalid hostname collision policy", "hostname-collision-policy", hostnameCollisionPolicy)
+		os.Exit(1)
+	}
+	if !controller.GatewayConflictPolicy(gatewayConflictPolicy).IsValid() {
+		setupLog.Error(nil, "invalid Gateway conflict policy", "gateway-conflict-policy", gatewayConflictPolicy)
+		os.Exit(1)
+	}
+	if !strings.Contains(tlsSecretTemplate, "{hostname}") {
+		setupLog.Error(nil, "the TLS secret template must contain {hostname}", "tls-secret-template", tlsSecretTemplate)
+		os.Exit(1)
+	}
+	if !controller.CertificateMode(certificateMode).IsValid() {
+		setupLog.Error(nil, "invalid certificate mode", "certificate-mode", certificateMode)
+		os.Exit(1)
+	}
+	if !controller.CertificateStrategy(certificateStrategy).IsValid() {
+		setupLog.Error(nil, "invalid certificate strategy", "certificate-strategy", certificateStrategy)
+		os.Exit(1)
+	}
+	if !controller.HTTP01ListenerMode(http01Listeners).IsValid() {
+		setupLog.Error(nil, "invalid ACME HTTP-01 listener mode", "acme-http01-listeners", http01Listeners)
+		os.Exit(1)
+	}
+	if !controller.ListenerNaming(listenerNaming).IsValid() {
+		setupLog.Error(nil, "invalid listener naming", "listener-naming", listenerNaming)
+		os.Exit(1)
+	}
+	switch certificateKeyAlgorithm {
+	case "", "RSA", "ECDSA", "Ed25519":
+	default:
+		setupLog.Error(nil, "invalid certificate key algorithm", "certificate-key-algorithm", certificateKeyAlgorithm)
+		os.Exit(1)
+	}
+	if !controller.IPFamily(ipFamily).IsValid() {
+		setupLog.Error(nil, "invalid IP family", "ip-family", ipFamily)
+		os.Exit(1)
+	}
+	if enableGatewaySharding && enableListenerSets {
+		setupLog.Error(nil, "gateway sharding and ListenerSets cannot be enabled together")
+		os.Exit(1)
+	}
+	if maxListenersPerGateway < 1 || maxListenersPerGateway > 64 {
+		setupLog.Error(nil, "invalid maximum number of listeners per Gateway", "max-listeners-per-gateway", maxListenersPerGateway)
+		os.Exit(1)
+	}
+	if !(leaseDuration > renewDeadline && renewDeadline > retryPeriod && retryPeriod > 0) {
+		setupLog.Error(nil, "the leader election lease duration must exceed the renew deadline, which must exceed the retry period",
+			"leader-election-lease-duration", leaseDuration, "leader-election-renew-deadline", renewDeadline,
+			"leader-election-retry-period", retryPeriod)
+		os.Exit(1)
+	}
+	if operatorShards < 1 || operatorShard < 0 || operatorShard >= operatorShards {
+		setupLog.Error(nil, "invalid operator shard", "operator-shards", operatorShards, "operator-shard", operatorShard)
+		os.Exit(1)
+	}
+	if hstsMaxAge < 0 {
+		setupLog.Error(nil, "invalid HSTS max age", "hsts-max-age", hstsMaxAge)
+		os.Exit(1)
+	}
+	if !controller.AccessLogFormat(accessLogFormat).IsValid() {
+		setupLog.Error(nil, "invalid access log format, must be text or json", "access-log-format", accessLogFormat)
+		os.Exit(1)
+	}
+	if accessLogOTLPEndpoint != "" {
+		if _, _, err := net.SplitHostPort(accessLogOTLPEndpoint); err != nil {
+			setupLog.Error(err, "invalid access log OpenTelemetry collector", "access-log-otlp-endpoint", accessLogOTLPEndpoint)
+			os.Exit(1)
+		}
+	}
+	if clientIdleTimeout < 0 || clientTCPKeepaliveIdleTime < 0 {
+		setupLog.Error(nil, "client connection timeouts must not be negative", "client-idle-timeout", clientIdleTimeout,
+			"client-tcp-keepalive-idle-time", clientTCPKeepaliveIdleTime)
+		os.Exit(1)
+	}
+	if shutdownDrainTimeout < 0 {
+		setupLog.Error(nil, "invalid shutdown drain timeout", "shutdown-drain-timeout", shutdownDrainTimeout)
+		os.Exit(1)
+	}
+	if maxConcurrentReconciles < 1 {
+		setupLog.Error(nil, "invalid number of concurrent reconciles", "max-concurrent-reconciles", maxConcurrentReconciles)
+		os.Exit(1)
+	}
+	if requeueBaseDelay <= 0 || requeueMaxDelay < requeueBaseDelay {
+		setupLog.Error(nil, "invalid requeue delays", "requeue-base-delay", requeueBaseDelay, "requeue-max-delay", requeueMaxDelay)
+		os.Exit(1)
+	}
+	if kubeAPIQPS <= 0 || kubeAPIBurst < 1 {
+		setupLog.Error(nil, "invalid Kubernetes API rate limit", "kube-api-qps", kubeAPIQPS, "kube-api-burst", kubeAPIBurst)
+		os.Exit(1)
+	}
+	var configMapKey client.ObjectKey
+	if configMap != "" {
+		namespace, name, ok := strings.Cut(configMap, "/")
+		if !ok || namespace == "" || name == "" {
+			setupLog.Error(nil, "the operator ConfigMap must be given as namespace/name", "config-map", configMap)
+			os.Exit(1)
+		}
+		configMapKey = client.ObjectKey{Namespace: namespace, Name: name}
+	}
+	var webhookCertSecretKey, webhookServiceKey client.ObjectKey
+	if manageWebhookCerts {
+		for flagName, value := range map[string]string{"webhook-cert-secret": webhookCertSecret, "webhook-service": webhookService} {
+			if namespace, name, ok := strings.Cut(value, "/"); !ok || namespace == "" || name == "" {
+				setupLog.Error(nil, "the webhook certificate secret and service must be given as namespace/name", flagName, value)
+				os.Exit(1)
+			}
+		}
+		namespace, name, _ := strings.Cut(webhookCertSecret, "/")
+		webhookCertSecretKey = client.ObjectKey{Namespace: namespace, Name: name}
+		namespace, name, _ = strings.Cut(webhookService, "/")
+		webhookServiceKey = client.ObjectKey{Namespace: namespace, Name: name}
+	}
+	selector, err := labels.Parse(namespaceSelector)
+	if err != nil {
+		setupLog.Error(err, "invalid namespace selector", "namespace-selector", namespaceSelector)
+		os.Exit(1)
+	}
+	routeLabelSelector, err := labels.Parse(routeSelector)
+	if err != nil {
+		setupLog.Error(err, "invalid route selector", "route-selector", routeSelector)
+		os.Exit(1)
+	}
+	enabledNamespaceLabelSelector, err := labels.Parse(enabledNamespaceSelector)
+	if err != nil {
+		setupLog.Error(err, "invalid enabled namespace selector", "enabled-namespace-selector", enabledNamespaceSelector)
+		os.Exit(1)
+	}
+	if tcpPortRangeStart < 1 || tcpPortRangeEnd > 65535 || tcpPortRangeStart > tcpPortRangeEnd {
+		setupLog.Error(nil, "invalid TCPRoute port range", "start", tcpPortRangeStart, "end", tcpPortRangeEnd)
+		os.Exit(1)
+	}
+	if listenerPortRangeStart < 1 || listenerPortRangeEnd > 65535 || listenerPortRangeStart > listenerPortRangeEnd {
+		setupLog.Error(nil, "invalid listener port range", "start", listenerPortRangeStart, "end", listenerPortRangeEnd)
+		os.Exit(1)
+	}
 
 	// if the enable-http2 flag is false (the default), http/2 should be disabled
 	// due to its vulnerabilities. More specifically, disabling http/2 will
@@ -117,6 +638,13 @@ func main() {
 		webhookServerOptions.KeyName = webhookCertKey
 	}
 
+	// Self-managed certificates are written to the certificate directory of the webhook server
+	if manageWebhookCerts && len(webhookCertPath) == 0 {
+		webhookServerOptions.CertDir = filepath.Join(os.TempDir(), "k8s-webhook-server", "serving-certs")
+		webhookServerOptions.CertName = webhookCertName
+		webhookServerOptions.KeyName = webhookCertKey
+	}
+
 	webhookServer := webhook.NewServer(webhookServerOptions)
 
 	// Metrics endpoint is enabled in 'config/default/kustomization.yaml'. The Metrics options configure the server.
@@ -154,39 +682,287 @@ func main() {
 		metricsServerOptions.KeyName = metricsCertKey
 	}
 
-	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
-		Scheme:                 scheme,
-		Metrics:                metricsServerOptions,
-		WebhookServer:          webhookServer,
-		HealthProbeBindAddress: probeAddr,
-		LeaderElection:         enableLeaderElection,
-		LeaderElectionID:       "4227eb97.example.com",
-		// LeaderElectionReleaseOnCancel defines if the leader should step down voluntarily
-		// when the Manager ends. This requires the binary to immediately end when the
-		// Manager is stopped, otherwise, this setting is unsafe. Setting this significantly
-		// speeds up voluntary leader transitions as the new leader don't have to wait
-		// LeaseDuration time first.
-		//
-		// In the default scaffold provided, the program ends immediately after
-		// the manager stops, so would be fine to enable this option. However,
-		// if you are doing or is intended to do any operation such as perform cleanups
-		// after the manager stops then its usage might be unsafe.
-		// LeaderElectionReleaseOnCancel: true,
+	// Every resync reconciles all routes again, which enqueues all the Gateways they reference
+	cacheOptions := cache.Options{
+		SyncPeriod: &resyncPeriod,
+	}
+	if configMap != "" {
+		// Only the operator ConfigMap is cached, not every ConfigMap in the cluster
+		cacheOptions.ByObject = map[client.Object]cache.ByObject{
+			&corev1.ConfigMap{}: {
+				Namespaces: map[string]cache.Config{
+					configMapKey.Namespace: {
+						FieldSelector: fields.OneTermEqualSelector("metadata.name", configMapKey.Name),
+					},
+				},
+			},
+		}
+	}
+	if !cacheUnmanagedRoutes {
+		// Only the metadata of routes the operator doesn't manage is kept, their spec is never read
+		if cacheOptions.ByObject == nil {
+			cacheOptions.ByObject = make(map[client.Object]cache.ByObject)
+		}
+		transform := controller.RouteCacheTransform(routeLabelSelector, !enabledNamespaceLabelSelector.Empty())
+		routes := []client.Object{&gatewayv1.HTTPRoute{}, &gatewayv1.GRPCRoute{}}
+		if enableTLSRoutes {
+			routes = append(routes, &gatewayv1alpha2.TLSRoute{})
+		}
+		if enableTCPRoutes {
+			routes = append(routes, &gatewayv1alpha2.TCPRoute{})
+		}
+		for _, route := range routes {
+			cacheOptions.ByObject[route] = cache.ByObject{Transform: transform}
+		}
+	}
+	if watched := parseList(watchNamespaces); len(watched) > 0 {
+		// Gateways in the shared namespace and certificate secrets in the TLS secret namespaces
+		// serve the routes of the watched namespaces, so they are cached as well
+		cacheOptions.DefaultNamespaces = make(map[string]cache.Config)
+		for _, namespace := range append(watched, append(parseList(tlsSecretNamespaces), sharedGatewayNamespace)...) {
+			if namespace != "" {
+				cacheOptions.DefaultNamespaces[namespace] = cache.Config{}
+			}
+		}
+	}
+
+	// Every operator shard elects its own leader
+	leaderElectionID := "4227eb97.example.com"
+	if operatorShards > 1 {
+		leaderElectionID = fmt.Sprintf("shard-%d.%s", operatorShard, leaderElectionID)
+	}
+
+	// The manager waits for the reconciles in flight to drain, and a little longer for the other
+	// runnables to stop
+	gracefulShutdownTimeout := shutdownDrainTimeout + 3*time.Second
+
+	restConfig := ctrl.GetConfigOrDie()
+	restConfig.QPS = float32(kubeAPIQPS)
+	restConfig.Burst = kubeAPIBurst
+	mgr, err := ctrl.NewManager(restConfig, ctrl.Options{
+		Scheme:                  scheme,
+		Metrics:                 metricsServerOptions,
+		WebhookServer:           webhookServer,
+		HealthProbeBindAddress:  probeAddr,
+		PprofBindAddress:        pprofAddr,
+		LeaderElection:          enableLeaderElection,
+		LeaderElectionID:        leaderElectionID,
+		LeaderElectionNamespace: leaderElectionNamespace,
+		LeaseDuration:           &leaseDuration,
+		RenewDeadline:           &renewDeadline,
+		RetryPeriod:             &retryPeriod,
+		Cache:                   cacheOptions,
+		GracefulShutdownTimeout: &gracefulShutdownTimeout,
+		// The leader steps down when the Manager ends, so on rollouts and restarts a standby
+		// takes over right away instead of after the lease duration. This is safe as the
+		// program ends immediately after the manager stops, without cleanups.
+		LeaderElectionReleaseOnCancel: true,
 	})
 	if err != nil {
 		setupLog.Error(err, "unable to start manager")
 		os.Exit(1)
 	}
 
-	if err := (&controller.HTTPRouteReconciler{
+	gatewayManager := &controller.GatewayManager{
 		Client: mgr.GetClient(),
 		Scheme: mgr.GetScheme(),
+
+		EnableTLSRoutes:              enableTLSRoutes,
+		EnableTCPRoutes:              enableTCPRoutes,
+		EnableBackendTLSPolicies:     enableBackendTLSPolicies,
+		EnableSecurityPolicies:       enableSecurityPolicies,
+		EnableBackendTrafficPolicies: enableBackendTrafficPolicies,
+		EnableSecurityHeaders:        enableSecurityHeaders,
+		DNSPreflight:                 dnsPreflight,
+		AccessLogZones:               accessLogZones,
+		AccessLogFormat:              controller.AccessLogFormat(accessLogFormat),
+		AccessLogOTLPEndpoint:        accessLogOTLPEndpoint,
+		ClientIdleTimeout:            clientIdleTimeout,
+		ClientProxyProtocol:          clientProxyProtocol,
+		ClientTCPKeepaliveIdleTime:   clientTCPKeepaliveIdleTime,
+		HSTSMaxAge:                   hstsMaxAge,
+		EnableHTTPSRedirect:          enableHTTPSRedirect,
+		EnableHostnameClaims:         enableHostnameClaims,
+		HostnameCollisionPolicy:      controller.HostnameCollisionPolicy(hostnameCollisionPolicy),
+		EnableManagedGateways:        enableManagedGateways,
+		TCPPortRangeStart:            gatewayv1.PortNumber(tcpPortRangeStart),
+		TCPPortRangeEnd:              gatewayv1.PortNumber(tcpPortRangeEnd),
+		ListenerPortRangeStart:       gatewayv1.PortNumber(listenerPortRangeStart),
+		ListenerPortRangeEnd:         gatewayv1.PortNumber(listenerPortRangeEnd),
+
+		CreateReferenceGrants:  createReferenceGrants,
+		SharedGatewayNamespace: sharedGatewayNamespace,
+
+		NamespaceGatewayTemplate:   namespaceGatewayTemplate,
+		TLSSecretTemplate:          tlsSecretTemplate,
+		TLSSecretNamespaces:        parseList(tlsSecretNamespaces),
+		AllowedDomains:             parseList(allowedDomains),
+		AllowedZones:               parseList(allowedZones),
+		NamespaceListenerQuota:     namespaceListenerQuota,
+		WatchNamespaces:            parseList(watchNamespaces),
+		ExcludeNamespaces:          parseList(excludeNamespaces),
+		NamespaceSelector:          selector,
+		RouteSelector:              routeLabelSelector,
+		EnabledNamespaceSelector:   enabledNamespaceLabelSelector,
+		TLSOptions:                 listenerTLSOptions,
+		ZoneWildcardDomains:        wildcardDomains,
+		EnableGatewaySharding:      enableGatewaySharding,
+		MaxListenersPerGateway:     maxListenersPerGateway,
+		EnableListenerSets:         enableListenerSets,
+		GatewayDeletionPolicy:      controller.GatewayDeletionPolicy(gatewayDeletionPolicy),
+		GatewayDeletionGracePeriod: gatewayDeletionGracePeriod,
+		DryRun:                     dryRun,
+		AuditLog:                   auditLog,
+		LogFormat:                  controller.LogFormat(logFormat),
+		LogLevel:                   logLevel,
+		ControllerLogLevels:        controllerLogLevels,
+		Logging:                    logging,
+		DefaultGatewayClass:        gatewayClass,
+		ZoneGatewayClasses:         zoneClasses,
+		PropagateLabels:            parseList(propagateLabels),
+		PropagateAnnotations:       parseList(propagateAnnotations),
+		InfrastructureLabels:       infraLabels,
+		ZoneAddressRanges:          addressRanges,
+		ZoneIPPools:                ipPools,
+		EnableZoneMigration:        enableZoneMigration,
+		EnableIssuerRotation:       enableIssuerRotation,
+		GatewayConflictPolicy:      controller.GatewayConflictPolicy(gatewayConflictPolicy),
+		DefaultIPFamily:            controller.IPFamily(ipFamily),
+		CertificateMode:            controller.CertificateMode(certificateMode),
+		CertificateStrategy:        controller.CertificateStrategy(certificateStrategy),
+		HTTP01Listeners:            controller.HTTP01ListenerMode(http01Listeners),
+		ListenerNaming:             controller.ListenerNaming(listenerNaming),
+		CertificateKeyAlgorithm:    certificateKeyAlgorithm,
+		CertificateDuration:        certificateDuration,
+		CertificateRenewBefore:     certificateRenewBefore,
+		CertificateExpiryWarning:   certificateExpiryWarning,
+		GatewayProgrammedTimeout:   gatewayProgrammedTimeout,
+		GatewayUpdateDelay:         gatewayUpdateDelay,
+		MaxConcurrentReconciles:    maxConcurrentReconciles,
+		OperatorShards:             operatorShards,
+		OperatorShard:              operatorShard,
+		ShutdownDrainTimeout:       shutdownDrainTimeout,
+		RequeueBaseDelay:           requeueBaseDelay,
+		RequeueMaxDelay:            requeueMaxDelay,
+		APIReader:                  mgr.GetAPIReader(),
+		Recorder:                   mgr.GetEventRecorderFor("gatewayapi-operator"),
+	}
+
+	if err := metrics.Registry.Register(&controller.DomainMetricsCollector{GatewayManager: gatewayManager}); err != nil {
+		setupLog.Error(err, "unable to register domain metrics")
+		os.Exit(1)
+	}
+
+	ctx := ctrl.SetupSignalHandler()
+	if err := gatewayManager.SetupIndexes(ctx, mgr); err != nil {
+		setupLog.Error(err, "unable to set up route indexes")
+		os.Exit(1)
+	}
+	if err := mgr.Add(&controller.GatewayGarbageCollector{
+		GatewayManager: gatewayManager,
+		Interval:       gatewayGCInterval,
+	}); err != nil {
+		setupLog.Error(err, "unable to set up Gateway garbage collection")
+		os.Exit(1)
+	}
+
+	if err := (&controller.HTTPRouteReconciler{
+		GatewayManager: gatewayManager,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "HTTPRoute")
 		os.Exit(1)
 	}
+	if err := (&controller.GatewayReconciler{
+		GatewayManager: gatewayManager,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "Gateway")
+		os.Exit(1)
+	}
+	if err := (&controller.GRPCRouteReconciler{
+		GatewayManager: gatewayManager,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "GRPCRoute")
+		os.Exit(1)
+	}
+	if enableTLSRoutes {
+		if err := (&controller.TLSRouteReconciler{
+			GatewayManager: gatewayManager,
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "TLSRoute")
+			os.Exit(1)
+		}
+	}
+	if enableTCPRoutes {
+		if err := (&controller.TCPRouteReconciler{
+			GatewayManager: gatewayManager,
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "TCPRoute")
+			os.Exit(1)
+		}
+	}
+	if enableIngressAdoption {
+		if err := (&controller.IngressReconciler{
+			GatewayManager: gatewayManager,
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "Ingress")
+			os.Exit(1)
+		}
+	}
+	if configMap != "" {
+		if err := (&controller.OperatorConfigReconciler{
+			GatewayManager: gatewayManager,
+			ConfigMap:      configMapKey,
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "OperatorConfig")
+			os.Exit(1)
+		}
+	}
 	// +kubebuilder:scaffold:builder
 
+	// Reconcile every route and Gateway once the controllers run, for the converged readiness check
+	if err := mgr.Add(&controller.StartupPass{GatewayManager: gatewayManager}); err != nil {
+		setupLog.Error(err, "unable to set up the startup pass")
+		os.Exit(1)
+	}
+
+	if manageWebhookCerts {
+		certManager := &controller.WebhookCertManager{
+			GatewayManager: gatewayManager,
+			Secret:         webhookCertSecretKey,
+			Service:        webhookServiceKey,
+			CertDir:        webhookServerOptions.CertDir,
+			CertName:       webhookCertName,
+			KeyName:        webhookCertKey,
+		}
+		// The webhook server needs its certificate to start
+		if err := certManager.EnsureCertificates(ctx); err != nil {
+			setupLog.Error(err, "unable to set up webhook certificates")
+			os.Exit(1)
+		}
+		if err := mgr.Add(certManager); err != nil {
+			setupLog.Error(err, "unable to set up webhook certificate renewal")
+			os.Exit(1)
+		}
+	}
+	if enableRouteDefaulting {
+		if err := (&controller.RouteDefaulter{
+			GatewayManager: gatewayManager,
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "RouteDefaulter")
+			os.Exit(1)
+		}
+	}
+	if protectManagedGateways {
+		if err := (&controller.GatewayGuard{
+			GatewayManager: gatewayManager,
+			OperatorUsers:  parseList(operatorUsers),
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "GatewayGuard")
+			os.Exit(1)
+		}
+	}
+	// +kubebuilder:scaffold:webhook
+
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
 		setupLog.Error(err, "unable to set up health check")
 		os.Exit(1)
@@ -195,10 +971,80 @@ func main() {
 		setupLog.Error(err, "unable to set up ready check")
 		os.Exit(1)
 	}
+	if err := mgr.AddReadyzCheck("converged", gatewayManager.ConvergedCheck(mgr)); err != nil {
+		setupLog.Error(err, "unable to set up converged check")
+		os.Exit(1)
+	}
 
 	setupLog.Info("starting manager")
-	if err := mgr.Start(ctrl.SetupSignalHandler()); err != nil {
+	if err := mgr.Start(ctx); err != nil {
 		setupLog.Error(err, "problem running manager")
 		os.Exit(1)
 	}
 }
+
+// parseKeyValuePairs parses a comma separated list of key=value pairs
+func parseKeyValuePairs(value string) (map[string]string, error) {
+	pairs := make(map[string]string)
+	if value == "" {
+		return pairs, nil
+	}
+	for _, pair := range strings.Split(value, ",") {
+		key, val, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		if !ok || key == "" || val == "" {
+			return nil, fmt.Errorf("invalid key=value pair %q", pair)
+		}
+		pairs[key] = val
+	}
+	return pairs, nil
+}
+
+// parseZoneAddressRanges parses a comma separated list of zone=CIDR pairs, where a zone may be
+// listed more than once
+func parseZoneAddressRanges(value string) (map[string][]netip.Prefix, error) {
+	ranges := make(map[string][]netip.Prefix)
+	for _, item := range parseList(value) {
+		zone, cidr, ok := strings.Cut(item, "=")
+		if !ok || zone == "" {
+			return nil, fmt.Errorf("invalid zone=CIDR pair %q", item)
+		}
+		prefix, err := netip.ParsePrefix(cidr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid CIDR for zone %q: %w", zone, err)
+		}
+		ranges[zone] = append(ranges[zone], prefix.Masked())
+	}
+	return ranges, nil
+}
+
+// parseZoneIPPools parses a comma separated list of zone=[group/]Kind/name pairs naming the IPAM
+// pool of each zone. The group defaults to ipam.cluster.x-k8s.io.
+func parseZoneIPPools(value string) (map[string]corev1.TypedLocalObjectReference, error) {
+	pools := make(map[string]corev1.TypedLocalObjectReference)
+	pairs, err := parseKeyValuePairs(value)
+	if err != nil {
+		return nil, err
+	}
+	for zone, ref := range pairs {
+		parts := strings.Split(ref, "/")
+		if len(parts) == 2 {
+			parts = append([]string{"ipam.cluster.x-k8s.io"}, parts...)
+		}
+		if len(parts) != 3 || parts[1] == "" || parts[2] == "" {
+			return nil, fmt.Errorf("invalid IPAM pool %q for zone %q, must be [group/]Kind/name", ref, zone)
+		}
+		pools[zone] = corev1.TypedLocalObjectReference{APIGroup: &parts[0], Kind: parts[1], Name: parts[2]}
+	}
+	return pools, nil
+}
+
+// parseList parses a comma separated list, ignoring empty entries
+func parseList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
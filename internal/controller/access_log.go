@@ -0,0 +1,140 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"slices"
+	"strconv"
+	"strings"
+
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// AccessLogFormat is the format of the access logs of managed Gateways
+type AccessLogFormat string
+
+const (
+	// AccessLogFormatText is Envoy Gateway's default text format
+	AccessLogFormatText AccessLogFormat = "text"
+	// AccessLogFormatJSON writes an object with the fields of accessLogJSONFields per request
+	AccessLogFormatJSON AccessLogFormat = "json"
+)
+
+// IsValid reports whether the access log format is known
+func (f AccessLogFormat) IsValid() bool {
+	return f == AccessLogFormatText || f == AccessLogFormatJSON
+}
+
+// accessLogJSONFields are the fields of JSON access logs, as Envoy command operators
+var accessLogJSONFields = map[string]any{
+	"start_time":     "%START_TIME%",
+	"method":         "%REQ(:METHOD)%",
+	"path":           "%REQ(X-ENVOY-ORIGINAL-PATH?:PATH)%",
+	"protocol":       "%PROTOCOL%",
+	"authority":      "%REQ(:AUTHORITY)%",
+	"response_code":  "%RESPONSE_CODE%",
+	"response_flags": "%RESPONSE_FLAGS%",
+	"bytes_received": "%BYTES_RECEIVED%",
+	"bytes_sent":     "%BYTES_SENT%",
+	"duration":       "%DURATION%",
+	"client_address": "%DOWNSTREAM_REMOTE_ADDRESS%",
+	"user_agent":     "%REQ(USER-AGENT)%",
+	"request_id":     "%REQ(X-REQUEST-ID)%",
+	"upstream_host":  "%UPSTREAM_HOST%",
+	"route_name":     "%ROUTE_NAME%",
+}
+
+// accessLogEnabled returns whether the gateway's access logs are enabled: the oldest route owning
+// the gateway with the access-log annotation decides, otherwise the zones of the operator
+// configuration. configured is false for gateways left to Envoy Gateway's defaults.
+func (r *GatewayManager) accessLogEnabled(ctx context.Context, gateway *gatewayv1.Gateway) (enabled, configured bool, err error) {
+	log := logf.FromContext(ctx)
+
+	routes, err := r.gatewayOwners(ctx, gateway.Name, gateway.Namespace)
+	if err != nil {
+		return false, false, err
+	}
+	sortOldestFirst(routes)
+	for _, route := range routes {
+		value, exists := route.GetAnnotations()[AnnotationAccessLog]
+		if !exists {
+			continue
+		}
+		enabled, err := strconv.ParseBool(value)
+		if err != nil {
+			log.Error(err, "Invalid access log annotation, ignoring it", "route", route.GetName(),
+				"namespace", route.GetNamespace(), "value", value)
+			continue
+		}
+		return enabled, true, nil
+	}
+
+	zone := gatewayZone(gateway)
+	zones := strings.Split(r.config().AccessLogZones, ",")
+	for i := range zones {
+		zones[i] = strings.TrimSpace(zones[i])
+	}
+	if slices.Contains(zones, "*") || zone != "" && slices.Contains(zones, zone) {
+		return true, true, nil
+	}
+	return false, false, nil
+}
+
+// accessLogSpec returns the access log settings of the gateway's EnvoyProxy, or nil for gateways
+// left to Envoy Gateway's defaults. Enabled access logs are written in AccessLogFormat to the
+// Envoy's stdout, or to the OpenTelemetry collector at AccessLogOTLPEndpoint, disabled ones are
+// turned off.
+func (r *GatewayManager) accessLogSpec(ctx context.Context, gateway *gatewayv1.Gateway) (map[string]any, error) {
+	enabled, configured, err := r.accessLogEnabled(ctx, gateway)
+	if err != nil || !configured {
+		return nil, err
+	}
+	if !enabled {
+		return map[string]any{"disable": true}, nil
+	}
+
+	sink := map[string]any{
+		"type": "File",
+		"file": map[string]any{"path": "/dev/stdout"},
+	}
+	if r.AccessLogOTLPEndpoint != "" {
+		host, port, err := splitOTLPEndpoint(r.AccessLogOTLPEndpoint)
+		if err != nil {
+			return nil, err
+		}
+		sink = map[string]any{
+			"type": "OpenTelemetry",
+			"openTelemetry": map[string]any{
+				"host": host,
+				"port": port,
+			},
+		}
+	}
+	settings := map[string]any{
+		"sinks": []any{sink},
+	}
+	if r.AccessLogFormat == AccessLogFormatJSON {
+		settings["format"] = map[string]any{
+			"type": "JSON",
+			"json": accessLogJSONFields,
+		}
+	}
+	return map[string]any{
+		"settings": []any{settings},
+	}, nil
+}
+
+// splitOTLPEndpoint splits the host:port of an OpenTelemetry collector
+func splitOTLPEndpoint(endpoint string) (string, int64, error) {
+	host, portValue, err := net.SplitHostPort(endpoint)
+	if err != nil {
+		return "", 0, err
+	}
+	port, err := strconv.ParseInt(portValue, 10, 32)
+	if err != nil || port < 1 || port > 65535 || host == "" {
+		return "", 0, fmt.Errorf("%q is not a host:port", endpoint)
+	}
+	return host, port, nil
+}
@@ -0,0 +1,220 @@
+package controller
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// +kubebuilder:rbac:groups=acme.cert-manager.io,resources=challenges,verbs=get;list;watch
+
+// HTTP01ListenerMode selects when managed Gateways get the port 80 listeners cert-manager's ACME
+// HTTP-01 solver routes attach to
+type HTTP01ListenerMode string
+
+const (
+	// HTTP01ListenersDisabled adds no listeners for HTTP-01 challenges
+	HTTP01ListenersDisabled HTTP01ListenerMode = "disabled"
+
+	// HTTP01ListenersAlways keeps a port 80 listener for every hostname issued through HTTP-01
+	HTTP01ListenersAlways HTTP01ListenerMode = "always"
+
+	// HTTP01ListenersIssuance only adds the port 80 listener of a hostname while cert-manager has
+	// an HTTP-01 Challenge for it
+	HTTP01ListenersIssuance HTTP01ListenerMode = "issuance"
+)
+
+// IsValid reports whether the mode is one of the known HTTP-01 listener modes
+func (m HTTP01ListenerMode) IsValid() bool {
+	switch m {
+	case HTTP01ListenersDisabled, HTTP01ListenersAlways, HTTP01ListenersIssuance:
+		return true
+	}
+	return false
+}
+
+// challengeGVK is the cert-manager ACME Challenge kind, handled as unstructured like Certificates
+var challengeGVK = schema.GroupVersionKind{
+	Group:   "acme.cert-manager.io",
+	Version: "v1",
+	Kind:    "Challenge",
+}
+
+// issuerUsesHTTP01 reports whether a cert-manager issuer has an ACME HTTP-01 solver. Issuers that
+// don't exist, and clusters without the cert-manager CRDs, have none.
+func (r *GatewayManager) issuerUsesHTTP01(ctx context.Context, issuerRef issuerRef, namespace string) (bool, error) {
+	issuer := &unstructured.Unstructured{}
+	issuer.SetGroupVersionKind(certificateGVK.GroupVersion().WithKind(issuerRef.Kind))
+	key := client.ObjectKey{Name: issuerRef.Name}
+	if issuerRef.Kind == issuerKindIssuer {
+		key.Namespace = namespace
+	}
+	if err := r.Get(ctx, key, issuer); err != nil {
+		if meta.IsNoMatchError(err) {
+			return false, nil
+		}
+		return false, client.IgnoreNotFound(err)
+	}
+
+	solvers, _, _ := unstructured.NestedSlice(issuer.Object, "spec", "acme", "solvers")
+	for _, item := range solvers {
+		solver, ok := item.(map[string]any)
+		if ok && solver["http01"] != nil {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// challengedHostnames returns the hostnames cert-manager has an HTTP-01 Challenge for in a namespace
+func (r *GatewayManager) challengedHostnames(ctx context.Context, namespace string) ([]string, error) {
+	var challenges unstructured.UnstructuredList
+	challenges.SetGroupVersionKind(challengeGVK.GroupVersion().WithKind(challengeGVK.Kind + "List"))
+	if err := r.List(ctx, &challenges, client.InNamespace(namespace)); err != nil {
+		if meta.IsNoMatchError(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var hostnames []string
+	for _, challenge := range challenges.Items {
+		challengeType, _, _ := unstructured.NestedString(challenge.Object, "spec", "type")
+		dnsName, _, _ := unstructured.NestedString(challenge.Object, "spec", "dnsName")
+		if challengeType == "HTTP-01" && dnsName != "" {
+			hostnames = append(hostnames, dnsName)
+		}
+	}
+	return hostnames, nil
+}
+
+// http01Listeners returns the port 80 listeners cert-manager's HTTP-01 solver routes attach to,
+// for the hostnames of the TLS listeners whose issuer uses HTTP-01. The solver routes are created
+// in the namespace of the Certificate, which is the namespace of the secret when the operator
+// creates the Certificates and the gateway's namespace otherwise, so the listeners admit routes
+// from there. Wildcard hostnames can't be validated through HTTP-01 and get no listener.
+// It is given the listeners before they wait for their certificates, since issuing them needs the
+// HTTP-01 listeners first.
+func (r *GatewayManager) http01Listeners(
+	ctx context.Context,
+	gatewayName, gatewayNamespace string,
+	defaultIssuer issuerRef,
+	listeners []gatewayv1.Listener,
+) ([]gatewayv1.Listener, error) {
+	if r.HTTP01Listeners == "" || r.HTTP01Listeners == HTTP01ListenersDisabled {
+		return nil, nil
+	}
+	log := logf.FromContext(ctx)
+
+	issuers := map[gatewayv1.SectionName]issuerRef{}
+	if r.CertificateMode == CertificateModeCertificate {
+		var err error
+		if issuers, err = r.listenerIssuers(ctx, gatewayName, gatewayNamespace); err != nil {
+			return nil, err
+		}
+	}
+
+	type issuerKey struct {
+		issuer    issuerRef
+		namespace string
+	}
+	usesHTTP01 := make(map[issuerKey]bool)
+	challenged := make(map[string][]string)
+	http01 := make(map[gatewayv1.SectionName]gatewayv1.Listener)
+	for _, listener := range listeners {
+		if listener.TLS == nil || len(listener.TLS.CertificateRefs) == 0 || listener.Hostname == nil ||
+			strings.HasPrefix(string(*listener.Hostname), "*.") {
+			continue
+		}
+		hostname := string(*listener.Hostname)
+
+		namespace := gatewayNamespace
+		if r.CertificateMode == CertificateModeCertificate && listener.TLS.CertificateRefs[0].Namespace != nil {
+			namespace = string(*listener.TLS.CertificateRefs[0].Namespace)
+		}
+		issuer, exists := issuers[listener.Name]
+		if !exists {
+			issuer = defaultIssuer
+		}
+
+		key := issuerKey{issuer: issuer, namespace: namespace}
+		uses, checked := usesHTTP01[key]
+		if !checked {
+			var err error
+			if uses, err = r.issuerUsesHTTP01(ctx, issuer, namespace); err != nil {
+				return nil, err
+			}
+			usesHTTP01[key] = uses
+		}
+		if !uses {
+			continue
+		}
+
+		if r.HTTP01Listeners == HTTP01ListenersIssuance {
+			hostnames, checked := challenged[namespace]
+			if !checked {
+				var err error
+				if hostnames, err = r.challengedHostnames(ctx, namespace); err != nil {
+					return nil, err
+				}
+				challenged[namespace] = hostnames
+			}
+			if !slices.Contains(hostnames, hostname) {
+				continue
+			}
+		}
+
+		name, hn := listenerHostname(hostname)
+		listenerName := r.sectionName(httpListenerPrefix + name)
+		namespaces := []string{gatewayNamespace}
+		if namespace != gatewayNamespace {
+			namespaces = append(namespaces, namespace)
+		}
+		listener := gatewayv1.Listener{
+			Name:          listenerName,
+			Protocol:      gatewayv1.HTTPProtocolType,
+			Port:          r.config().HTTPPort,
+			Hostname:      hn,
+			AllowedRoutes: namespaceAllowedRoutes(namespaces...),
+		}
+		if existing, exists := http01[listenerName]; exists {
+			listener = mergeAllowedNamespaces(existing, listener)
+		}
+		http01[listenerName] = listener
+		log.V(1).Info("Adding HTTP-01 listener", "listener", listenerName, "gateway", gatewayName, "issuer", issuer.String())
+	}
+
+	result := make([]gatewayv1.Listener, 0, len(http01))
+	for _, name := range slices.Sorted(maps.Keys(http01)) {
+		result = append(result, http01[name])
+	}
+	return result, nil
+}
+
+// gatewaysForChallenge enqueues every managed Gateway when an HTTP-01 Challenge comes or goes, so
+// the listener for its hostname is added and removed during issuance. A Challenge doesn't tell
+// which Gateway it is for, and Challenges are rare, so all managed Gateways check their listeners.
+func (r *GatewayManager) gatewaysForChallenge(ctx context.Context, obj client.Object) []reconcile.Request {
+	var gateways gatewayv1.GatewayList
+	if err := r.List(ctx, &gateways); err != nil {
+		logf.FromContext(ctx).Error(err, "Failed to list Gateways for Challenge", "challenge", obj.GetName(), "namespace", obj.GetNamespace())
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for i := range gateways.Items {
+		if isManagedGateway(&gateways.Items[i]) {
+			requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&gateways.Items[i])})
+		}
+	}
+	return requests
+}
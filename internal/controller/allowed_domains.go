@@ -0,0 +1,134 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// hostnameAllowed reports whether a hostname is one of the allowed domains or below one. Wildcard
+// hostnames are allowed when their domain is. Every hostname is allowed without allowed domains.
+func (r *GatewayManager) hostnameAllowed(hostname string) bool {
+	if len(r.AllowedDomains) == 0 {
+		return true
+	}
+	hostname = strings.TrimPrefix(strings.ToLower(hostname), "*.")
+	for _, domain := range r.AllowedDomains {
+		domain = strings.TrimPrefix(strings.ToLower(domain), "*.")
+		if hostname == domain || strings.HasSuffix(hostname, "."+domain) {
+			return true
+		}
+	}
+	return false
+}
+
+// disallowedHostnames returns the hostnames of a route outside the allowed domains
+func (r *GatewayManager) disallowedHostnames(route routeInfo) []string {
+	var disallowed []string
+	for _, hostname := range route.Hostnames {
+		if hostname != "" && !r.hostnameAllowed(string(hostname)) {
+			disallowed = append(disallowed, string(hostname))
+		}
+	}
+	return disallowed
+}
+
+// withoutDisallowedHostnames drops the hostnames of a route outside the allowed domains, so no
+// listeners are provisioned for them
+func (r *GatewayManager) withoutDisallowedHostnames(route routeInfo) routeInfo {
+	if len(r.AllowedDomains) == 0 {
+		return route
+	}
+	hostnames := make([]gatewayv1.Hostname, 0, len(route.Hostnames))
+	for _, hostname := range route.Hostnames {
+		if r.hostnameAllowed(string(hostname)) {
+			hostnames = append(hostnames, hostname)
+		}
+	}
+	route.Hostnames = hostnames
+	return route
+}
+
+// domainRejection returns why a route is rejected for asking for hostnames outside the allowed
+// domains, or an empty string when all its hostnames are allowed
+func (r *GatewayManager) domainRejection(route client.Object) string {
+	info, ok := newRouteInfo(route)
+	if !ok {
+		return ""
+	}
+	disallowed := r.disallowedHostnames(info)
+	if len(disallowed) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("hostnames %s are not in the allowed domains %s",
+		strings.Join(disallowed, ", "), strings.Join(r.AllowedDomains, ", "))
+}
+
+// ensureRouteAllowed rejects routes with invalid hostnames, asking for hostnames outside the
+// allowed domains or for an IPAM zone their namespace may not use, with a warning event for each
+// reason, and records the reasons in the rejected annotation on the route. The annotation is removed again once the route
+// is allowed.
+func (r *GatewayManager) ensureRouteAllowed(ctx context.Context, route client.Object) error {
+	type rejection struct {
+		eventReason string
+		message     string
+	}
+	var rejections []rejection
+	if info, ok := newRouteInfo(route); ok && len(info.InvalidHostnames) > 0 {
+		rejections = append(rejections, rejection{eventReason: "HostnameInvalid",
+			message: fmt.Sprintf("hostnames %s are not valid DNS names", strings.Join(info.InvalidHostnames, ", "))})
+	}
+	if message := r.domainRejection(route); message != "" {
+		rejections = append(rejections, rejection{eventReason: "HostnameNotAllowed", message: message})
+	}
+	if message := r.zoneRejection(ctx, route); message != "" {
+		rejections = append(rejections, rejection{eventReason: "ZoneNotAllowed", message: message})
+	}
+
+	messages := make([]string, 0, len(rejections))
+	for _, rejected := range rejections {
+		messages = append(messages, rejected.message)
+	}
+	reason := strings.Join(messages, "; ")
+	if err := r.setRejectedAnnotation(ctx, route, reason); err != nil {
+		return err
+	}
+	if reason == "" {
+		return nil
+	}
+
+	for _, rejected := range rejections {
+		r.Recorder.Eventf(route, corev1.EventTypeWarning, rejected.eventReason, "Rejected: %s", rejected.message)
+	}
+	err := errors.NewBadRequest(reason)
+	logf.FromContext(ctx).Error(err, "Route is not allowed", "route", route.GetName(), "namespace", route.GetNamespace())
+	return err
+}
+
+// setRejectedAnnotation records why a route is rejected in its rejected annotation, or removes the
+// annotation when reason is empty
+func (r *GatewayManager) setRejectedAnnotation(ctx context.Context, route client.Object, reason string) error {
+	current, exists := route.GetAnnotations()[rejectedAnnotationKey]
+	if current == reason && (exists || reason == "") {
+		return nil
+	}
+
+	patch := client.MergeFrom(route.DeepCopyObject().(client.Object))
+	annotations := route.GetAnnotations()
+	if reason == "" {
+		delete(annotations, rejectedAnnotationKey)
+	} else {
+		if annotations == nil {
+			annotations = make(map[string]string)
+		}
+		annotations[rejectedAnnotationKey] = reason
+	}
+	route.SetAnnotations(annotations)
+	return r.Patch(ctx, route, patch)
+}
@@ -0,0 +1,41 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// allowedZones returns the IPAM zones the routes of a namespace may use: the zones of the
+// namespace's allowed-zones annotation, or the operator default. Empty allows every zone.
+func (r *GatewayManager) allowedZones(ctx context.Context, namespace string) []string {
+	if value, exists := r.namespaceDefaults(ctx, namespace)[AnnotationAllowedZones]; exists {
+		var zones []string
+		for _, zone := range strings.Split(value, ",") {
+			if zone = strings.TrimSpace(zone); zone != "" {
+				zones = append(zones, zone)
+			}
+		}
+		return zones
+	}
+	return r.AllowedZones
+}
+
+// zoneRejection returns why a route is rejected for using an IPAM zone its namespace may not use,
+// or an empty string when the zone is allowed. The zone is the one the route ends up with, so
+// zones coming from a GatewayProfile or the namespace defaults are checked as well.
+func (r *GatewayManager) zoneRejection(ctx context.Context, route client.Object) string {
+	allowed := r.allowedZones(ctx, route.GetNamespace())
+	if len(allowed) == 0 {
+		return ""
+	}
+	zone := r.routeIPAMZone(ctx, route)
+	if slices.Contains(allowed, zone) {
+		return ""
+	}
+	return fmt.Sprintf("IPAM zone %s is not allowed in namespace %s, allowed zones are %s",
+		zone, route.GetNamespace(), strings.Join(allowed, ", "))
+}
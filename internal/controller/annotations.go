@@ -8,7 +8,179 @@ const (
 	// AnnotationIPAMZone specifies the zone
 	// Value type: string
 	AnnotationIPAMZone = "ipam.vitistack.io/zone"
+	// AnnotationGatewayClass specifies the GatewayClass of the Gateway created for the route
+	// Value type: string
+	AnnotationGatewayClass = "gatewayapi-operator.vitistack.io/gateway-class"
+	// AnnotationInfrastructureLabels sets labels on the infrastructure of the route's Gateway,
+	// overriding the operator defaults
+	// Value type: string (comma separated key=value pairs, e.g. "team=web,tier=frontend")
+	AnnotationInfrastructureLabels = "gatewayapi-operator.vitistack.io/infrastructure-labels"
+	// AnnotationAddress requests a static address for the route's Gateway, e.g. an IP address
+	// firewall rules are written for. Values that aren't IP addresses are requested as named addresses
+	// Value type: string
+	AnnotationAddress = "gatewayapi-operator.vitistack.io/address"
+	// AnnotationIPFamily selects the address families of the route's Gateway
+	// Value type: string ("IPv4", "IPv6" or "DualStack")
+	AnnotationIPFamily = "gatewayapi-operator.vitistack.io/ip-family"
+	// AnnotationProfile selects a GatewayProfile bundling the IPAM zone, issuer, GatewayClass, IP
+	// family and TLS options of the route's Gateway. Annotations set on the route override the profile
+	// Value type: string
+	AnnotationProfile = "gatewayapi-operator.vitistack.io/profile"
 	// AnnotationClusterIssuer specifies the cert-manager cluster issuer for TLS certificates
 	// Value type: string
 	AnnotationClusterIssuer = "gatewayapi-operator.vitistack.io/cluster-issuer"
+	// AnnotationIssuerKind selects the kind of the issuer named by AnnotationClusterIssuer. An Issuer
+	// must be in the namespace the certificates are requested in
+	// Value type: string ("ClusterIssuer" or "Issuer", default "ClusterIssuer")
+	AnnotationIssuerKind = "gatewayapi-operator.vitistack.io/issuer-kind"
+	// AnnotationProtocol selects the protocol of the listeners of HTTPRoutes and GRPCRoutes. HTTP
+	// creates plain HTTP listeners without TLS
+	// Value type: string ("HTTPS" or "HTTP", default "HTTPS")
+	AnnotationProtocol = "gatewayapi-operator.vitistack.io/protocol"
+	// AnnotationListenerPort moves the listeners of the route to a non-standard port, e.g. 8443. The
+	// port must be in the configured listener port range and not collide with other listeners
+	// Value type: int
+	AnnotationListenerPort = "gatewayapi-operator.vitistack.io/listener-port"
+	// AnnotationTLSMode selects how TLS is handled for TLSRoute listeners
+	// Value type: string ("terminate" or "passthrough", default "terminate")
+	AnnotationTLSMode = "gatewayapi-operator.vitistack.io/tls-mode"
+	// AnnotationTCPPort holds the listener port allocated to a TCPRoute.
+	// Set by the operator, but can be set up front to request a specific port
+	// Value type: int
+	AnnotationTCPPort = "gatewayapi-operator.vitistack.io/tcp-port"
+	// AnnotationTLSSecretName points the route's listeners at an existing certificate secret, e.g. a
+	// wildcard certificate bought externally, instead of the secret named by the TLS secret template
+	// Value type: string
+	AnnotationTLSSecretName = "gatewayapi-operator.vitistack.io/tls-secret-name"
+	// AnnotationTLSSecretNamespace puts the certificate secrets of the route's listeners in another
+	// namespace than the Gateway's, e.g. a central certificate store. The operator manages the
+	// ReferenceGrant allowing the Gateway to reference them
+	// Value type: string
+	AnnotationTLSSecretNamespace = "gatewayapi-operator.vitistack.io/tls-secret-namespace"
+	// AnnotationAllowedZones restricts the IPAM zones the routes of a namespace may use, overriding
+	// the operator default. Set on namespaces by platform admins
+	// Value type: string (comma separated IPAM zones)
+	AnnotationAllowedZones = "gatewayapi-operator.vitistack.io/allowed-zones"
+	// AnnotationDefaultGateway names the standard Gateway of a namespace, which the route defaulting
+	// webhook attaches routes without parent references to. Set on namespaces by platform admins
+	// Value type: string (name, or namespace/name)
+	AnnotationDefaultGateway = "gatewayapi-operator.vitistack.io/default-gateway"
+	// AnnotationListenerQuota limits how many hostnames the routes of a namespace may add to Gateways
+	// in other namespaces, overriding the operator default. Set on namespaces by platform admins
+	// Value type: int (0 is unlimited)
+	AnnotationListenerQuota = "gatewayapi-operator.vitistack.io/listener-quota"
+	// AnnotationTLSOptions sets implementation specific TLS options on the route's listeners, e.g. the
+	// minimum TLS version or ALPN protocols, overriding the operator defaults
+	// Value type: string (comma separated key=value pairs)
+	AnnotationTLSOptions = "gatewayapi-operator.vitistack.io/tls-options"
+	// AnnotationBackendTLSCA enables TLS to the route's backend Services, validated against the CA
+	// certificates in the named ConfigMap in the route's namespace, or "system" for the system CAs
+	// Value type: string
+	AnnotationBackendTLSCA = "gatewayapi-operator.vitistack.io/backend-tls-ca"
+	// AnnotationBackendTLSHostname is the hostname backend certificates are validated against.
+	// Defaults to the cluster DNS name of the first backend Service
+	// Value type: string
+	AnnotationBackendTLSHostname = "gatewayapi-operator.vitistack.io/backend-tls-hostname"
+	// AnnotationOIDCIssuer enables OIDC authentication of the users of an HTTPRoute with the
+	// issuer, through an Envoy Gateway SecurityPolicy
+	// Value type: string (issuer URL)
+	AnnotationOIDCIssuer = "gatewayapi-operator.vitistack.io/oidc-issuer"
+	// AnnotationOIDCClientID is the client ID the route authenticates users with
+	// Value type: string
+	AnnotationOIDCClientID = "gatewayapi-operator.vitistack.io/oidc-client-id"
+	// AnnotationOIDCClientSecret names the Secret in the route's namespace holding the client secret
+	// under the client-secret key
+	// Value type: string
+	AnnotationOIDCClientSecret = "gatewayapi-operator.vitistack.io/oidc-client-secret"
+	// AnnotationOIDCRedirectURL is the URL the issuer redirects users back to after logging in.
+	// Defaults to the Envoy Gateway default, /oauth2/callback on the requested hostname
+	// Value type: string (URL)
+	AnnotationOIDCRedirectURL = "gatewayapi-operator.vitistack.io/oidc-redirect-url"
+	// AnnotationOIDCScopes are the scopes requested besides openid
+	// Value type: string (comma separated scopes)
+	AnnotationOIDCScopes = "gatewayapi-operator.vitistack.io/oidc-scopes"
+	// AnnotationJWTIssuer enables JWT validation of the route's requests through an Envoy Gateway
+	// SecurityPolicy: requests without a valid JWT from the issuer are rejected with 401
+	// Value type: string (issuer, matched against the iss claim)
+	AnnotationJWTIssuer = "gatewayapi-operator.vitistack.io/jwt-issuer"
+	// AnnotationJWTJWKSURI is the URL of the issuer's JSON Web Key Set the JWTs are verified with
+	// Value type: string (URL)
+	AnnotationJWTJWKSURI = "gatewayapi-operator.vitistack.io/jwt-jwks-uri"
+	// AnnotationBasicAuthSecret enables basic authentication of the route's users through an Envoy
+	// Gateway SecurityPolicy, with the users in the htpasswd file under the .htpasswd key of the Secret
+	// Value type: string (name of a Secret in the route's namespace)
+	AnnotationBasicAuthSecret = "gatewayapi-operator.vitistack.io/basic-auth-secret"
+	// AnnotationCORSAllowOrigins enables CORS for the route through an Envoy Gateway SecurityPolicy,
+	// for requests from the given origins
+	// Value type: string (comma separated origins, e.g. https://app.example.com,https://*.example.com, or *)
+	AnnotationCORSAllowOrigins = "gatewayapi-operator.vitistack.io/cors-allow-origins"
+	// AnnotationCORSAllowMethods are the methods allowed in CORS requests
+	// Value type: string (comma separated methods, e.g. GET,POST)
+	AnnotationCORSAllowMethods = "gatewayapi-operator.vitistack.io/cors-allow-methods"
+	// AnnotationCORSAllowHeaders are the request headers allowed in CORS requests
+	// Value type: string (comma separated header names)
+	AnnotationCORSAllowHeaders = "gatewayapi-operator.vitistack.io/cors-allow-headers"
+	// AnnotationCORSExposeHeaders are the response headers exposed to the scripts of the origins
+	// Value type: string (comma separated header names)
+	AnnotationCORSExposeHeaders = "gatewayapi-operator.vitistack.io/cors-expose-headers"
+	// AnnotationCORSAllowCredentials allows CORS requests with credentials
+	// Value type: boolean ("true" or "false")
+	AnnotationCORSAllowCredentials = "gatewayapi-operator.vitistack.io/cors-allow-credentials"
+	// AnnotationAllowedCIDRs restricts the client addresses allowed to reach the route, through an
+	// Envoy Gateway SecurityPolicy. Requests from other addresses are denied
+	// Value type: string (comma separated CIDRs, e.g. 10.0.0.0/8,2001:db8::/32)
+	AnnotationAllowedCIDRs = "gatewayapi-operator.vitistack.io/allowed-cidrs"
+	// AnnotationRateLimit limits the request rate of the route, through an Envoy Gateway
+	// BackendTrafficPolicy. Requests above the limit are answered with 429
+	// Value type: string (requests per second, minute, hour or day, e.g. 100rps, 600rpm)
+	AnnotationRateLimit = "gatewayapi-operator.vitistack.io/rate-limit"
+	// AnnotationClientIdleTimeout is the idle timeout of client connections to the route's Gateway
+	// Value type: duration (e.g. 5m)
+	AnnotationClientIdleTimeout = "gatewayapi-operator.vitistack.io/client-idle-timeout"
+	// AnnotationProxyProtocol makes the route's Gateway expect the PROXY protocol from its clients,
+	// e.g. behind a load balancer preserving the client addresses with it
+	// Value type: boolean ("true" or "false")
+	AnnotationProxyProtocol = "gatewayapi-operator.vitistack.io/proxy-protocol"
+	// AnnotationTCPKeepaliveIdleTime enables TCP keepalive on client connections to the route's
+	// Gateway, probing connections idle for the given time
+	// Value type: duration (e.g. 1h)
+	AnnotationTCPKeepaliveIdleTime = "gatewayapi-operator.vitistack.io/tcp-keepalive-idle-time"
+	// AnnotationAccessLog enables or disables the access logs of the route's Gateway, overriding the
+	// access log zones of the operator
+	// Value type: boolean ("true" or "false")
+	AnnotationAccessLog = "gatewayapi-operator.vitistack.io/access-log"
+	// AnnotationHostnameFallback selects how listeners are created for routes without spec.hostnames
+	// Value type: string ("match-rules" derives hostnames from Host header matches,
+	// "wildcard" creates a catch-all listener without a hostname)
+	AnnotationHostnameFallback = "gatewayapi-operator.vitistack.io/hostname-fallback"
+	// AnnotationManagedGateway marks a Gateway the operator may manage listeners on.
+	// Set on Gateways created by the operator, and set manually to adopt an existing Gateway
+	// Value type: bool
+	AnnotationManagedGateway = "gatewayapi-operator.vitistack.io/managed"
+	// AnnotationBreakGlass lets users other than the operator delete a managed Gateway and change
+	// the listeners the operator applied, when the Gateway webhook protects them
+	// Value type: bool
+	AnnotationBreakGlass = "gatewayapi-operator.vitistack.io/break-glass"
+	// AnnotationDeletionPolicy overrides what happens to a Gateway when no routes reference it anymore
+	// Value type: string ("Delete", "Orphan" or "RetainEmpty")
+	AnnotationDeletionPolicy = "gatewayapi-operator.vitistack.io/deletion-policy"
+	// AnnotationDeletionTTL delays the deletion of a Gateway without routes
+	// Value type: duration (e.g. "10m")
+	AnnotationDeletionTTL = "gatewayapi-operator.vitistack.io/deletion-ttl"
+	// AnnotationDryRun makes the operator only plan the changes for a route, reporting them in
+	// events instead of applying them
+	// Value type: bool
+	AnnotationDryRun = "gatewayapi-operator.vitistack.io/dry-run"
+	// AnnotationPaused suspends reconciliation of a route or Gateway, e.g. during maintenance.
+	// Nothing is changed or deleted, and finalizers stay in place until the annotation is removed
+	// Value type: bool
+	AnnotationPaused = "gatewayapi-operator.vitistack.io/paused"
+	// AnnotationAdoptIngress opts an Ingress in to being served by HTTPRoutes the operator
+	// generates from it, with --enable-ingress-adoption
+	// Value type: bool
+	AnnotationAdoptIngress = "gatewayapi-operator.vitistack.io/adopt-ingress"
+	// AnnotationIngressGateway selects the Gateway of the HTTPRoutes generated from an Ingress,
+	// instead of the namespace's standard Gateway
+	// Value type: string ("name" or "namespace/name")
+	AnnotationIngressGateway = "gatewayapi-operator.vitistack.io/ingress-gateway"
 )
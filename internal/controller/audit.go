@@ -0,0 +1,37 @@
+package controller
+
+import (
+	"context"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// Actions recorded in the audit log
+const (
+	auditActionCreate    = "create"
+	auditActionUpdate    = "update"
+	auditActionDelete    = "delete"
+	auditActionOrphan    = "orphan"
+	auditActionMarkEmpty = "mark-empty"
+)
+
+// audit records a change the operator made to a Gateway in the audit log, when it is enabled.
+// Entries are written to the operator's log stream by the audit logger, with the controller and
+// object whose reconcile made the change, so they can be filtered and shipped on their own to
+// answer questions like who removed a listener. The log timestamp tells when. keysAndValues
+// describe the change, e.g. the listeners added and removed.
+func (r *GatewayManager) audit(ctx context.Context, action, kind string, obj client.Object, keysAndValues ...any) {
+	if !r.AuditLog {
+		return
+	}
+	logf.FromContext(ctx).WithName("audit").Info("Audit",
+		append([]any{
+			"action", action,
+			"kind", kind,
+			"name", obj.GetName(),
+			"namespace", obj.GetNamespace(),
+			"actor", fieldManager,
+			"resourceVersion", obj.GetResourceVersion(),
+		}, keysAndValues...)...)
+}
@@ -0,0 +1,142 @@
+package controller
+
+import (
+	"context"
+	"slices"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+	gatewayv1alpha3 "sigs.k8s.io/gateway-api/apis/v1alpha3"
+)
+
+// +kubebuilder:rbac:groups=gateway.networking.k8s.io,resources=backendtlspolicies,verbs=get;list;watch;create;update;patch;delete
+
+// backendTLSPolicySuffix is the suffix of the name of the BackendTLSPolicy created for a route
+const backendTLSPolicySuffix = "-backend-tls"
+
+// backendTLSSystemCA is the AnnotationBackendTLSCA value selecting the well-known system CAs
+const backendTLSSystemCA = "system"
+
+// routeServiceBackends returns the names of the Services in the route's own namespace the route
+// sends traffic to. A BackendTLSPolicy can only target Services in its own namespace.
+func routeServiceBackends(route client.Object) []string {
+	var refs []gatewayv1.BackendObjectReference
+	switch route := route.(type) {
+	case *gatewayv1.HTTPRoute:
+		for _, rule := range route.Spec.Rules {
+			for _, backend := range rule.BackendRefs {
+				refs = append(refs, backend.BackendObjectReference)
+			}
+		}
+	case *gatewayv1.GRPCRoute:
+		for _, rule := range route.Spec.Rules {
+			for _, backend := range rule.BackendRefs {
+				refs = append(refs, backend.BackendObjectReference)
+			}
+		}
+	case *gatewayv1alpha2.TLSRoute:
+		for _, rule := range route.Spec.Rules {
+			for _, backend := range rule.BackendRefs {
+				refs = append(refs, backend.BackendObjectReference)
+			}
+		}
+	}
+
+	var services []string
+	for _, ref := range refs {
+		if ref.Group != nil && *ref.Group != "" || ref.Kind != nil && *ref.Kind != "Service" {
+			continue
+		}
+		if ref.Namespace != nil && string(*ref.Namespace) != route.GetNamespace() {
+			continue
+		}
+		if !slices.Contains(services, string(ref.Name)) {
+			services = append(services, string(ref.Name))
+		}
+	}
+	return services
+}
+
+// syncBackendTLSPolicy creates a BackendTLSPolicy for the route's backend Services when the route
+// asks for TLS to its backends with the backend-tls-ca annotation, and deletes it when the route
+// no longer does. The backends are validated against the CA certificates in the named ConfigMap,
+// or the system CAs, and the hostname from the backend-tls-hostname annotation, defaulting to the
+// cluster DNS name of the first backend Service.
+func (r *GatewayManager) syncBackendTLSPolicy(ctx context.Context, route client.Object, gvk schema.GroupVersionKind) error {
+	log := logf.FromContext(ctx)
+	name := route.GetName() + backendTLSPolicySuffix
+	annotations := route.GetAnnotations()
+
+	ca := annotations[AnnotationBackendTLSCA]
+	services := routeServiceBackends(route)
+	if ca == "" || len(services) == 0 {
+		var policy gatewayv1alpha3.BackendTLSPolicy
+		if err := r.Get(ctx, client.ObjectKey{Name: name, Namespace: route.GetNamespace()}, &policy); err != nil {
+			return client.IgnoreNotFound(err)
+		}
+		if !metav1.IsControlledBy(&policy, route) {
+			return nil
+		}
+		if err := r.Delete(ctx, &policy); client.IgnoreNotFound(err) != nil {
+			return err
+		}
+		log.Info("Deleted BackendTLSPolicy", "backendTLSPolicy", name, "namespace", route.GetNamespace())
+		return nil
+	}
+
+	hostname := annotations[AnnotationBackendTLSHostname]
+	if hostname == "" {
+		hostname = services[0] + "." + route.GetNamespace() + ".svc"
+	}
+
+	targetRefs := make([]gatewayv1alpha2.LocalPolicyTargetReferenceWithSectionName, 0, len(services))
+	for _, service := range services {
+		targetRefs = append(targetRefs, gatewayv1alpha2.LocalPolicyTargetReferenceWithSectionName{
+			LocalPolicyTargetReference: gatewayv1alpha2.LocalPolicyTargetReference{
+				Group: "",
+				Kind:  "Service",
+				Name:  gatewayv1.ObjectName(service),
+			},
+		})
+	}
+
+	validation := gatewayv1alpha3.BackendTLSPolicyValidation{
+		Hostname: gatewayv1.PreciseHostname(hostname),
+	}
+	if ca == backendTLSSystemCA {
+		system := gatewayv1alpha3.WellKnownCACertificatesSystem
+		validation.WellKnownCACertificates = &system
+	} else {
+		validation.CACertificateRefs = []gatewayv1.LocalObjectReference{
+			{Group: "", Kind: "ConfigMap", Name: gatewayv1.ObjectName(ca)},
+		}
+	}
+
+	policy := &gatewayv1alpha3.BackendTLSPolicy{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: gatewayv1alpha3.GroupVersion.String(),
+			Kind:       "BackendTLSPolicy",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: route.GetNamespace(),
+			Labels:    map[string]string{managedByLabel: managedByValue},
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(route, gvk),
+			},
+		},
+		Spec: gatewayv1alpha3.BackendTLSPolicySpec{
+			TargetRefs: targetRefs,
+			Validation: validation,
+		},
+	}
+	if err := r.Patch(ctx, policy, client.Apply, client.ForceOwnership, client.FieldOwner(fieldManager)); err != nil {
+		return err
+	}
+	log.Info("Applied BackendTLSPolicy", "backendTLSPolicy", name, "namespace", route.GetNamespace(), "services", services)
+	return nil
+}
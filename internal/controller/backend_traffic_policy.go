@@ -0,0 +1,123 @@
+package controller
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// +kubebuilder:rbac:groups=gateway.envoyproxy.io,resources=backendtrafficpolicies,verbs=get;list;watch;create;update;patch;delete
+
+// backendTrafficPolicySuffix is the suffix of the name of the BackendTrafficPolicy created for a route
+const backendTrafficPolicySuffix = "-traffic"
+
+// backendTrafficPolicyGVK is the Envoy Gateway BackendTrafficPolicy
+var backendTrafficPolicyGVK = schema.GroupVersionKind{
+	Group:   "gateway.envoyproxy.io",
+	Version: "v1alpha1",
+	Kind:    "BackendTrafficPolicy",
+}
+
+// rateLimitUnits maps the units of the rate-limit annotation to Envoy Gateway's rate limit units
+var rateLimitUnits = map[string]string{
+	"rps": "Second",
+	"rpm": "Minute",
+	"rph": "Hour",
+	"rpd": "Day",
+}
+
+// syncBackendTrafficPolicy applies the Envoy Gateway BackendTrafficPolicy limiting the request rate
+// of a route with the rate-limit annotation, and deletes it when the route no longer has one
+func (r *GatewayManager) syncBackendTrafficPolicy(ctx context.Context, route client.Object, gvk schema.GroupVersionKind) error {
+	log := logf.FromContext(ctx)
+	name := route.GetName() + backendTrafficPolicySuffix
+
+	value, exists := route.GetAnnotations()[AnnotationRateLimit]
+	if !exists {
+		policy := &unstructured.Unstructured{}
+		policy.SetGroupVersionKind(backendTrafficPolicyGVK)
+		if err := r.Get(ctx, client.ObjectKey{Name: name, Namespace: route.GetNamespace()}, policy); err != nil {
+			return client.IgnoreNotFound(err)
+		}
+		if !metav1.IsControlledBy(policy, route) {
+			return nil
+		}
+		if err := r.Delete(ctx, policy); client.IgnoreNotFound(err) != nil {
+			return err
+		}
+		log.Info("Deleted BackendTrafficPolicy", "backendTrafficPolicy", name, "namespace", route.GetNamespace())
+		return nil
+	}
+
+	switch route.(type) {
+	case *gatewayv1.HTTPRoute, *gatewayv1.GRPCRoute:
+	default:
+		return errors.NewBadRequest("rate limits are only supported for HTTPRoutes and GRPCRoutes")
+	}
+	requests, unit, err := parseRateLimit(value)
+	if err != nil {
+		return err
+	}
+
+	policy := &unstructured.Unstructured{}
+	policy.SetGroupVersionKind(backendTrafficPolicyGVK)
+	policy.SetName(name)
+	policy.SetNamespace(route.GetNamespace())
+	policy.SetLabels(map[string]string{managedByLabel: managedByValue})
+	policy.SetOwnerReferences([]metav1.OwnerReference{*metav1.NewControllerRef(route, gvk)})
+	policy.Object["spec"] = map[string]any{
+		"targetRefs": []any{
+			map[string]any{
+				"group": gvk.Group,
+				"kind":  gvk.Kind,
+				"name":  route.GetName(),
+			},
+		},
+		"rateLimit": map[string]any{
+			"type": "Local",
+			"local": map[string]any{
+				"rules": []any{
+					map[string]any{
+						"limit": map[string]any{
+							"requests": requests,
+							"unit":     unit,
+						},
+					},
+				},
+			},
+		},
+	}
+	if err := r.Patch(ctx, policy, client.Apply, client.ForceOwnership, client.FieldOwner(fieldManager)); err != nil {
+		return err
+	}
+	log.Info("Applied BackendTrafficPolicy", "backendTrafficPolicy", name, "namespace", route.GetNamespace(),
+		"rateLimit", value)
+	return nil
+}
+
+// parseRateLimit parses a rate-limit annotation value like 100rps into the number of requests and
+// the Envoy Gateway rate limit unit
+func parseRateLimit(value string) (int64, string, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	for suffix, unit := range rateLimitUnits {
+		number, found := strings.CutSuffix(value, suffix)
+		if !found {
+			continue
+		}
+		requests, err := strconv.ParseInt(strings.TrimSpace(number), 10, 64)
+		if err != nil || requests < 1 {
+			break
+		}
+		return requests, unit, nil
+	}
+	return 0, "", errors.NewBadRequest(AnnotationRateLimit + ": " + strconv.Quote(value) +
+		" must be a positive number of requests followed by rps, rpm, rph or rpd")
+}
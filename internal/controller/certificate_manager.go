@@ -0,0 +1,342 @@
+package controller
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// +kubebuilder:rbac:groups=cert-manager.io,resources=certificates,verbs=get;list;watch;create;update;patch;delete
+
+// CertificateMode selects how certificates for the TLS listeners of managed Gateways are requested
+type CertificateMode string
+
+const (
+	// CertificateModeAnnotation sets the cluster issuer annotation on the Gateway and leaves
+	// creating the certificates to cert-manager's Gateway support
+	CertificateModeAnnotation CertificateMode = "annotation"
+
+	// CertificateModeCertificate makes the operator create a cert-manager Certificate per listener
+	// hostname and remove it again together with the listener
+	CertificateModeCertificate CertificateMode = "certificate"
+)
+
+// IsValid reports whether the mode is one of the known certificate modes
+func (m CertificateMode) IsValid() bool {
+	switch m {
+	case CertificateModeAnnotation, CertificateModeCertificate:
+		return true
+	}
+	return false
+}
+
+// CertificateStrategy selects how many certificates the listeners of a managed Gateway use
+type CertificateStrategy string
+
+const (
+	// CertificateStrategyPerHostname gives every listener hostname its own certificate secret
+	CertificateStrategyPerHostname CertificateStrategy = "per-hostname"
+
+	// CertificateStrategyPerGateway makes all listeners of a Gateway share one certificate secret
+	// in the Gateway's namespace, with all their hostnames as SANs
+	CertificateStrategyPerGateway CertificateStrategy = "per-gateway"
+)
+
+// IsValid reports whether the strategy is one of the known certificate strategies
+func (s CertificateStrategy) IsValid() bool {
+	switch s {
+	case CertificateStrategyPerHostname, CertificateStrategyPerGateway:
+		return true
+	}
+	return false
+}
+
+// gatewaySecretName returns the name of the certificate secret shared by the listeners of a gateway
+// with the per-gateway strategy
+func (r *GatewayManager) gatewaySecretName(gatewayName string) string {
+	return r.tlsSecretName(gatewayName)
+}
+
+// useGatewaySecret points the listeners' certificate references at the gateway's shared secret,
+// for the per-gateway strategy. Secrets a route points at with the tls-secret-name annotation are
+// kept.
+func (r *GatewayManager) useGatewaySecret(gatewayName, gatewayNamespace string, listeners []gatewayv1.Listener) {
+	if r.CertificateStrategy != CertificateStrategyPerGateway {
+		return
+	}
+	for i := range listeners {
+		listener := &listeners[i]
+		if listener.TLS == nil || listener.Hostname == nil {
+			continue
+		}
+		for j, ref := range listener.TLS.CertificateRefs {
+			if !r.issuedByOperator(gatewayName, *listener, ref) {
+				continue
+			}
+			namespace := gatewayv1.Namespace(gatewayNamespace)
+			listener.TLS.CertificateRefs[j].Name = gatewayv1.ObjectName(r.gatewaySecretName(gatewayName))
+			listener.TLS.CertificateRefs[j].Namespace = &namespace
+		}
+	}
+}
+
+// certificateRequeueInterval is how often a gateway with listeners waiting for their certificate
+// is checked again
+const certificateRequeueInterval = 30 * time.Second
+
+// certificateGVK is the cert-manager Certificate kind. cert-manager is not a dependency of the
+// operator, so Certificates are handled as unstructured objects.
+var certificateGVK = schema.GroupVersionKind{
+	Group:   "cert-manager.io",
+	Version: "v1",
+	Kind:    "Certificate",
+}
+
+// listenerIssuers maps the listeners of the routes owning a gateway to the issuer of the route
+// they come from. When routes with different issuers share a listener, the oldest route wins.
+func (r *GatewayManager) listenerIssuers(
+	ctx context.Context,
+	gatewayName, gatewayNamespace string,
+) (map[gatewayv1.SectionName]issuerRef, error) {
+	log := logf.FromContext(ctx)
+
+	routes, err := r.gatewayOwners(ctx, gatewayName, gatewayNamespace)
+	if err != nil {
+		return nil, err
+	}
+	sortOldestFirst(routes)
+
+	issuers := make(map[gatewayv1.SectionName]issuerRef)
+	for _, route := range routes {
+		if route.GetAnnotations()[AnnotationDryRun] == "true" {
+			continue
+		}
+		issuer := r.routeIssuer(ctx, route)
+		for _, parentRef := range route.parentRefsFor(gatewayName, gatewayNamespace) {
+			for _, listener := range r.listenersForParentRef(ctx, route, parentRef, gatewayNamespace) {
+				existing, taken := issuers[listener.Name]
+				if !taken {
+					issuers[listener.Name] = issuer
+					continue
+				}
+				if existing != issuer {
+					log.Info("Routes ask for different issuers for the same listener, using the issuer of the oldest route",
+						"listener", listener.Name, "issuer", existing.String(), "route", route.GetName(), "namespace", route.GetNamespace(),
+						"routeIssuer", issuer.String())
+				}
+			}
+		}
+	}
+	return issuers, nil
+}
+
+// syncCertificates creates a Certificate for every certificate secret the gateway's listeners
+// reference, in the namespace of the secret, and deletes the gateway's Certificates that are no
+// longer referenced. Each Certificate uses the issuer of the route its listener comes from,
+// so hostnames on the same gateway can be issued by different issuers. A secret shared by several
+// listeners, like the gateway secret of the per-gateway strategy, gets one Certificate for all their
+// hostnames, issued by the gateway's issuer. Listeners without a hostname
+// get no Certificate. Certificates can live in other namespaces than the gateway, so they are
+// tracked by annotation instead of owner references. Nothing is done unless the operator runs in
+// certificate mode.
+func (r *GatewayManager) syncCertificates(
+	ctx context.Context,
+	gatewayName, gatewayNamespace string,
+	defaultIssuer issuerRef,
+	listeners []gatewayv1.Listener,
+) error {
+	if r.CertificateMode != CertificateModeCertificate {
+		return nil
+	}
+	log := logf.FromContext(ctx)
+	gatewayKey := gatewayIndexValue(gatewayName, gatewayNamespace)
+
+	issuers, err := r.listenerIssuers(ctx, gatewayName, gatewayNamespace)
+	if err != nil {
+		return err
+	}
+
+	// The hostnames and issuer of each secret, in listener order
+	var secrets []client.ObjectKey
+	dnsNames := make(map[client.ObjectKey][]string)
+	secretIssuers := make(map[client.ObjectKey]issuerRef)
+	for _, listener := range listeners {
+		if listener.TLS == nil || listener.Hostname == nil {
+			continue
+		}
+		for _, ref := range listener.TLS.CertificateRefs {
+			if !r.issuedByOperator(gatewayName, listener, ref) {
+				continue
+			}
+			namespace := gatewayNamespace
+			if ref.Namespace != nil {
+				namespace = string(*ref.Namespace)
+			}
+			key := client.ObjectKey{Name: string(ref.Name), Namespace: namespace}
+			if _, seen := dnsNames[key]; !seen {
+				secrets = append(secrets, key)
+				issuer, exists := issuers[listener.Name]
+				if !exists || key.Name == r.gatewaySecretName(gatewayName) {
+					issuer = defaultIssuer
+				}
+				secretIssuers[key] = issuer
+			}
+			if !slices.Contains(dnsNames[key], string(*listener.Hostname)) {
+				dnsNames[key] = append(dnsNames[key], string(*listener.Hostname))
+			}
+		}
+	}
+
+	desired := make(map[client.ObjectKey]bool, len(secrets))
+	for _, key := range secrets {
+		desired[key] = true
+		certificate := r.certificateFor(key, dnsNames[key], secretIssuers[key], gatewayKey)
+		if err := r.Patch(ctx, certificate, client.Apply, client.ForceOwnership, client.FieldOwner(fieldManager)); err != nil {
+			return err
+		}
+	}
+
+	var certificates unstructured.UnstructuredList
+	certificates.SetGroupVersionKind(certificateGVK.GroupVersion().WithKind(certificateGVK.Kind + "List"))
+	if err := r.List(ctx, &certificates, client.MatchingLabels{managedByLabel: managedByValue}); err != nil {
+		return err
+	}
+	for i := range certificates.Items {
+		certificate := &certificates.Items[i]
+		if certificate.GetAnnotations()[certificateGatewayAnnotationKey] != gatewayKey || desired[client.ObjectKeyFromObject(certificate)] {
+			continue
+		}
+		if err := r.Delete(ctx, certificate); client.IgnoreNotFound(err) != nil {
+			return err
+		}
+		log.Info("Deleted unused Certificate", "certificate", client.ObjectKeyFromObject(certificate).String(), "gateway", gatewayKey)
+	}
+	return nil
+}
+
+// issuedByOperator reports whether the operator requests the certificate of a listener secret. Secrets
+// a route points at with the tls-secret-name annotation exist already and are left alone.
+func (r *GatewayManager) issuedByOperator(gatewayName string, listener gatewayv1.Listener, ref gatewayv1.SecretObjectReference) bool {
+	if listener.Hostname == nil {
+		return false
+	}
+	return string(ref.Name) == r.tlsSecretName(string(*listener.Hostname)) ||
+		(r.CertificateStrategy == CertificateStrategyPerGateway && string(ref.Name) == r.gatewaySecretName(gatewayName))
+}
+
+// certificateFor builds the Certificate issuing a secret for the listener hostnames using it
+func (r *GatewayManager) certificateFor(secret client.ObjectKey, hostnames []string, issuer issuerRef, gatewayKey string) *unstructured.Unstructured {
+	certificate := &unstructured.Unstructured{}
+	certificate.SetGroupVersionKind(certificateGVK)
+	certificate.SetName(secret.Name)
+	certificate.SetNamespace(secret.Namespace)
+	certificate.SetLabels(map[string]string{managedByLabel: managedByValue})
+	certificate.SetAnnotations(map[string]string{certificateGatewayAnnotationKey: gatewayKey})
+
+	dnsNames := make([]any, 0, len(hostnames))
+	for _, hostname := range hostnames {
+		dnsNames = append(dnsNames, hostname)
+	}
+	spec := map[string]any{
+		"secretName": secret.Name,
+		"dnsNames":   dnsNames,
+		"issuerRef": map[string]any{
+			"group": certificateGVK.Group,
+			"kind":  issuer.Kind,
+			"name":  issuer.Name,
+		},
+	}
+	if r.CertificateKeyAlgorithm != "" {
+		spec["privateKey"] = map[string]any{
+			"algorithm":      r.CertificateKeyAlgorithm,
+			"rotationPolicy": "Always",
+		}
+	}
+	if r.CertificateDuration > 0 {
+		spec["duration"] = r.CertificateDuration.String()
+	}
+	if r.CertificateRenewBefore > 0 {
+		spec["renewBefore"] = r.CertificateRenewBefore.String()
+	}
+	certificate.Object["spec"] = spec
+	return certificate
+}
+
+// certificateReady reports whether the Certificate issuing a secret exists and is Ready
+func (r *GatewayManager) certificateReady(ctx context.Context, secret client.ObjectKey) (bool, error) {
+	certificate := &unstructured.Unstructured{}
+	certificate.SetGroupVersionKind(certificateGVK)
+	if err := r.Get(ctx, secret, certificate); err != nil {
+		return false, client.IgnoreNotFound(err)
+	}
+
+	conditions, _, _ := unstructured.NestedSlice(certificate.Object, "status", "conditions")
+	for _, item := range conditions {
+		condition, ok := item.(map[string]any)
+		if ok && condition["type"] == "Ready" {
+			return condition["status"] == "True", nil
+		}
+	}
+	return false, nil
+}
+
+// withReadyCertificates holds back new listeners until the Certificates of their secrets are
+// Ready, so Envoy never serves a listener without a certificate. Listeners the gateway already
+// serves, given by current, are kept while their certificate is renewed. Only Certificates created by the operator are
+// checked, in annotation mode cert-manager only requests the certificate once the listener exists.
+// It returns the listeners to apply and the names of the listeners held back.
+func (r *GatewayManager) withReadyCertificates(
+	ctx context.Context,
+	gatewayName, gatewayNamespace string,
+	current []gatewayv1.Listener,
+	listeners []gatewayv1.Listener,
+) ([]gatewayv1.Listener, []gatewayv1.SectionName, error) {
+	if r.CertificateMode != CertificateModeCertificate {
+		return listeners, nil, nil
+	}
+
+	existing := make(map[gatewayv1.SectionName]bool, len(current))
+	for _, listener := range current {
+		existing[listener.Name] = true
+	}
+
+	ready := make([]gatewayv1.Listener, 0, len(listeners))
+	pending := make([]gatewayv1.SectionName, 0)
+	for _, listener := range listeners {
+		if listener.TLS == nil || listener.Hostname == nil || existing[listener.Name] {
+			ready = append(ready, listener)
+			continue
+		}
+
+		allReady := true
+		for _, ref := range listener.TLS.CertificateRefs {
+			if !r.issuedByOperator(gatewayName, listener, ref) {
+				continue
+			}
+			namespace := gatewayNamespace
+			if ref.Namespace != nil {
+				namespace = string(*ref.Namespace)
+			}
+			ok, err := r.certificateReady(ctx, client.ObjectKey{Name: string(ref.Name), Namespace: namespace})
+			if err != nil {
+				return nil, nil, err
+			}
+			if !ok {
+				allReady = false
+				break
+			}
+		}
+		if !allReady {
+			pending = append(pending, listener.Name)
+			continue
+		}
+		ready = append(ready, listener)
+	}
+	return ready, pending, nil
+}
@@ -0,0 +1,104 @@
+package controller
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// certificateExpiryCheckInterval is how often the certificates of a managed gateway's listeners
+// are checked, so the expiry metrics stay current and expiring certificates are reported in time
+const certificateExpiryCheckInterval = time.Hour
+
+// defaultCertificateExpiryWarning is how long before expiry a certificate is reported unless
+// configured otherwise
+const defaultCertificateExpiryWarning = 14 * 24 * time.Hour
+
+// certificateExpirySeconds is the time left until the certificate of a listener hostname expires
+var certificateExpirySeconds = prometheus.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Name: "gatewayapi_operator_certificate_expiry_seconds",
+		Help: "Seconds until the certificate served for a listener hostname of a managed Gateway expires",
+	},
+	[]string{"hostname", "gateway", "namespace"},
+)
+
+func init() {
+	metrics.Registry.MustRegister(certificateExpirySeconds)
+}
+
+// forgetCertificateExpiry removes the expiry metrics of a gateway's listeners
+func forgetCertificateExpiry(gatewayName, gatewayNamespace string) {
+	certificateExpirySeconds.DeletePartialMatch(prometheus.Labels{"gateway": gatewayName, "namespace": gatewayNamespace})
+}
+
+// checkCertificateExpiry exports the expiry of the certificates served by the gateway's listeners,
+// and emits warning events on the gateway for certificate secrets that are missing, invalid or
+// close to expiry. Secrets are read without the cache, since only their metadata is cached.
+func (r *GatewayManager) checkCertificateExpiry(ctx context.Context, gateway *gatewayv1.Gateway) error {
+	log := logf.FromContext(ctx)
+	warning := r.CertificateExpiryWarning
+	if warning == 0 {
+		warning = defaultCertificateExpiryWarning
+	}
+
+	forgetCertificateExpiry(gateway.Name, gateway.Namespace)
+	for _, listener := range gateway.Spec.Listeners {
+		if listener.TLS == nil || len(listener.TLS.CertificateRefs) == 0 || listener.Hostname == nil {
+			continue
+		}
+		hostname := string(*listener.Hostname)
+		ref := listener.TLS.CertificateRefs[0]
+		key := client.ObjectKey{Name: string(ref.Name), Namespace: gateway.Namespace}
+		if ref.Namespace != nil {
+			key.Namespace = string(*ref.Namespace)
+		}
+
+		var secret corev1.Secret
+		if err := r.APIReader.Get(ctx, key, &secret); err != nil {
+			if client.IgnoreNotFound(err) != nil {
+				return err
+			}
+			log.Info("Certificate secret of listener is missing", "listener", listener.Name, "secret", key.String())
+			r.Recorder.Eventf(gateway, corev1.EventTypeWarning, "CertificateMissing",
+				"Certificate secret %s of listener %s does not exist", key, listener.Name)
+			continue
+		}
+
+		certificate, err := parseCertificate(secret.Data[corev1.TLSCertKey])
+		if err != nil {
+			log.Info("Certificate secret of listener is invalid", "listener", listener.Name, "secret", key.String(), "error", err.Error())
+			r.Recorder.Eventf(gateway, corev1.EventTypeWarning, "CertificateInvalid",
+				"Certificate secret %s of listener %s is invalid: %v", key, listener.Name, err)
+			continue
+		}
+
+		remaining := time.Until(certificate.NotAfter)
+		certificateExpirySeconds.WithLabelValues(hostname, gateway.Name, gateway.Namespace).Set(remaining.Seconds())
+		if remaining < warning {
+			log.Info("Certificate of listener is close to expiry", "listener", listener.Name, "secret", key.String(),
+				"notAfter", certificate.NotAfter)
+			r.Recorder.Eventf(gateway, corev1.EventTypeWarning, "CertificateExpiring",
+				"Certificate %s of listener %s expires at %s", key, listener.Name, certificate.NotAfter.Format(time.RFC3339))
+		}
+	}
+	return nil
+}
+
+// parseCertificate parses the leaf certificate of PEM encoded certificate data
+func parseCertificate(data []byte) (*x509.Certificate, error) {
+	block, _ := pem.Decode(data)
+	if block == nil || block.Type != "CERTIFICATE" {
+		return nil, fmt.Errorf("no PEM encoded certificate in %s", corev1.TLSCertKey)
+	}
+	return x509.ParseCertificate(block.Bytes)
+}
@@ -0,0 +1,194 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// +kubebuilder:rbac:groups=gateway.envoyproxy.io,resources=clienttrafficpolicies,verbs=get;list;watch;create;update;patch;delete
+
+// clientTrafficPolicySuffix is the suffix of the name of the ClientTrafficPolicy created for a gateway
+const clientTrafficPolicySuffix = "-client-traffic"
+
+// clientTrafficPolicyGVK is the Envoy Gateway ClientTrafficPolicy
+var clientTrafficPolicyGVK = schema.GroupVersionKind{
+	Group:   "gateway.envoyproxy.io",
+	Version: "v1alpha1",
+	Kind:    "ClientTrafficPolicy",
+}
+
+// clientTrafficSettings are the settings of the connections from clients to a gateway. Zero
+// values leave Envoy Gateway's defaults.
+type clientTrafficSettings struct {
+	IdleTimeout          time.Duration
+	ProxyProtocol        bool
+	TCPKeepaliveIdleTime time.Duration
+}
+
+// securityHeaders returns the response headers of the security baseline, with HSTS for the given
+// max age. Headers set by the backends are overwritten.
+func securityHeaders(hstsMaxAge time.Duration) []any {
+	header := func(name, value string) any {
+		return map[string]any{"name": name, "value": value}
+	}
+	return []any{
+		header("Strict-Transport-Security", fmt.Sprintf("max-age=%d; includeSubDomains", int64(hstsMaxAge.Seconds()))),
+		header("X-Content-Type-Options", "nosniff"),
+		header("X-Frame-Options", "SAMEORIGIN"),
+		header("Referrer-Policy", "strict-origin-when-cross-origin"),
+	}
+}
+
+// gatewayDuration formats a duration the way Gateway API and Envoy Gateway expect it, e.g. 1h30m0s
+// or 1500ms
+func gatewayDuration(d time.Duration) string {
+	if d%time.Second == 0 {
+		return d.String()
+	}
+	return strconv.FormatInt(d.Milliseconds(), 10) + "ms"
+}
+
+// clientTrafficSettings returns the client connection settings of a gateway: the operator's
+// defaults, overridden by the annotations of the routes owning the gateway. When routes disagree
+// on a setting, the oldest route wins. Invalid values are logged and ignored.
+func (r *GatewayManager) clientTrafficSettings(ctx context.Context, gateway *gatewayv1.Gateway) (clientTrafficSettings, error) {
+	log := logf.FromContext(ctx)
+	settings := clientTrafficSettings{
+		IdleTimeout:          r.ClientIdleTimeout,
+		ProxyProtocol:        r.ClientProxyProtocol,
+		TCPKeepaliveIdleTime: r.ClientTCPKeepaliveIdleTime,
+	}
+
+	routes, err := r.gatewayOwners(ctx, gateway.Name, gateway.Namespace)
+	if err != nil {
+		return settings, err
+	}
+	sortOldestFirst(routes)
+
+	parseDuration := func(route routeInfo, key string) (time.Duration, bool) {
+		value, exists := route.GetAnnotations()[key]
+		if !exists {
+			return 0, false
+		}
+		d, err := time.ParseDuration(value)
+		if err == nil && d < 0 {
+			err = fmt.Errorf("%q must not be negative", value)
+		}
+		if err != nil {
+			log.Error(err, "Invalid client traffic annotation, ignoring it", "route", route.GetName(),
+				"namespace", route.GetNamespace(), "annotation", key)
+			return 0, false
+		}
+		return d, true
+	}
+	var idleTimeoutSet, proxyProtocolSet, keepaliveSet bool
+	for _, route := range routes {
+		if d, ok := parseDuration(route, AnnotationClientIdleTimeout); ok && !idleTimeoutSet {
+			settings.IdleTimeout, idleTimeoutSet = d, true
+		}
+		if d, ok := parseDuration(route, AnnotationTCPKeepaliveIdleTime); ok && !keepaliveSet {
+			settings.TCPKeepaliveIdleTime, keepaliveSet = d, true
+		}
+		if value, exists := route.GetAnnotations()[AnnotationProxyProtocol]; exists && !proxyProtocolSet {
+			enabled, err := strconv.ParseBool(value)
+			if err != nil {
+				log.Error(err, "Invalid client traffic annotation, ignoring it", "route", route.GetName(),
+					"namespace", route.GetNamespace(), "annotation", AnnotationProxyProtocol)
+				continue
+			}
+			settings.ProxyProtocol, proxyProtocolSet = enabled, true
+		}
+	}
+	return settings, nil
+}
+
+// syncClientTrafficPolicy applies the Envoy Gateway ClientTrafficPolicy combining the gateway's
+// client connection settings and security response headers, and deletes it when there are none
+func (r *GatewayManager) syncClientTrafficPolicy(ctx context.Context, gateway *gatewayv1.Gateway) error {
+	log := logf.FromContext(ctx)
+	name := gateway.Name + clientTrafficPolicySuffix
+
+	settings, err := r.clientTrafficSettings(ctx, gateway)
+	if err != nil {
+		return err
+	}
+	spec := map[string]any{}
+	if r.EnableSecurityHeaders {
+		spec["headers"] = map[string]any{
+			"lateResponseHeaders": map[string]any{
+				"set": securityHeaders(r.HSTSMaxAge),
+			},
+		}
+	}
+	if settings.IdleTimeout > 0 {
+		spec["timeout"] = map[string]any{
+			"http": map[string]any{"idleTimeout": gatewayDuration(settings.IdleTimeout)},
+			"tcp":  map[string]any{"idleTimeout": gatewayDuration(settings.IdleTimeout)},
+		}
+	}
+	if settings.ProxyProtocol {
+		spec["enableProxyProtocol"] = true
+	}
+	if settings.TCPKeepaliveIdleTime > 0 {
+		spec["tcpKeepalive"] = map[string]any{
+			"idleTime": gatewayDuration(settings.TCPKeepaliveIdleTime),
+		}
+	}
+
+	if len(spec) == 0 {
+		policy := &unstructured.Unstructured{}
+		policy.SetGroupVersionKind(clientTrafficPolicyGVK)
+		if err := r.Get(ctx, client.ObjectKey{Name: name, Namespace: gateway.Namespace}, policy); err != nil {
+			if meta.IsNoMatchError(err) {
+				return nil
+			}
+			return client.IgnoreNotFound(err)
+		}
+		if !metav1.IsControlledBy(policy, gateway) {
+			return nil
+		}
+		if err := r.Delete(ctx, policy); client.IgnoreNotFound(err) != nil {
+			return err
+		}
+		log.Info("Deleted ClientTrafficPolicy", "clientTrafficPolicy", name, "namespace", gateway.Namespace)
+		return nil
+	}
+
+	spec["targetRefs"] = []any{
+		map[string]any{
+			"group": gatewayv1.GroupName,
+			"kind":  "Gateway",
+			"name":  gateway.Name,
+		},
+	}
+	policy := &unstructured.Unstructured{}
+	policy.SetGroupVersionKind(clientTrafficPolicyGVK)
+	policy.SetName(name)
+	policy.SetNamespace(gateway.Namespace)
+	policy.SetLabels(map[string]string{managedByLabel: managedByValue})
+	policy.SetOwnerReferences([]metav1.OwnerReference{
+		*metav1.NewControllerRef(gateway, gatewayv1.SchemeGroupVersion.WithKind("Gateway")),
+	})
+	policy.Object["spec"] = spec
+	if err := r.Patch(ctx, policy, client.Apply, client.ForceOwnership, client.FieldOwner(fieldManager)); err != nil {
+		if meta.IsNoMatchError(err) {
+			log.V(1).Info("Envoy Gateway's ClientTrafficPolicy CRD is not installed, not applying client traffic settings",
+				"gateway", gateway.Name)
+			return nil
+		}
+		return err
+	}
+	log.V(1).Info("Applied ClientTrafficPolicy", "clientTrafficPolicy", name, "namespace", gateway.Namespace,
+		"securityHeaders", r.EnableSecurityHeaders, "settings", settings)
+	return nil
+}
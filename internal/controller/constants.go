@@ -7,27 +7,108 @@ const (
 	// reconcileAnnotationKey marks HTTPRoute resources that have been reconciled
 	reconcileAnnotationKey = "gatewayapi-operator.vitistack.io/reconciled"
 
-	// previousGatewayAnnotationKey tracks the previous gateway reference
-	// TODO: find a better way to implement this:
-	previousGatewayAnnotationKey = "gatewayapi-operator.vitistack.io/previous-gateway"
+	// legacyPreviousGatewayAnnotationKey tracked the gateway reference of routes in older versions.
+	// It is removed from the routes, the gateway index replaces it
+	legacyPreviousGatewayAnnotationKey = "gatewayapi-operator.vitistack.io/previous-gateway"
+
+	// rejectedAnnotationKey records why the operator rejects a route
+	rejectedAnnotationKey = "gatewayapi-operator.vitistack.io/rejected"
+
+	// statusAnnotationKey holds the conditions the operator reports on a route, as JSON
+	statusAnnotationKey = "gatewayapi-operator.vitistack.io/status"
+
+	// gatewayAddressAnnotationKey holds the addresses of a route's Gateway
+	gatewayAddressAnnotationKey = "gatewayapi-operator.vitistack.io/gateway-address"
+
+	// issuerRotationAnnotationKey records the previous issuer of a Gateway while its certificates
+	// are reissued by its new issuer
+	issuerRotationAnnotationKey = "gatewayapi-operator.vitistack.io/issuer-rotation"
+
+	// emptySinceAnnotationKey records when the last route stopped referencing a Gateway
+	emptySinceAnnotationKey = "gatewayapi-operator.vitistack.io/empty-since"
 
 	// clusterIssuerAnnotation specifies the cert-manager cluster issuer
 	clusterIssuerAnnotation = "cert-manager.io/cluster-issuer"
 
+	// certificateGatewayAnnotationKey records the gateway a Certificate created by the operator is for
+	certificateGatewayAnnotationKey = "gatewayapi-operator.vitistack.io/gateway"
+
+	// issuerAnnotation specifies the namespaced cert-manager issuer
+	issuerAnnotation = "cert-manager.io/issuer"
+
 	// defaultClusterIssuer is the default cert-manager cluster issuer
 	defaultClusterIssuer = "internpki"
 
-	// gatewayClassName is the Gateway API gateway class name
-	gatewayClassName = "eg"
+	// defaultGatewayClassName is the Gateway API gateway class name used unless configured otherwise
+	defaultGatewayClassName = "eg"
 
 	// httpsPort is the default HTTPS port
 	httpsPort = 443
 
-	// tlsCertSuffix is the suffix for TLS certificate secret names
-	tlsCertSuffix = "-tls"
+	// httpPort is the plain HTTP port redirected to HTTPS
+	httpPort = 80
+
+	// defaultTLSSecretTemplate is the name template of TLS certificate secrets used unless configured otherwise
+	defaultTLSSecretTemplate = hostnameTemplatePlaceholder + "-tls"
+
+	// hostnameTemplatePlaceholder is replaced by the listener hostname in name templates
+	hostnameTemplatePlaceholder = "{hostname}"
+
+	// tlsListenerPrefix is the section name prefix for TLS listeners created for TLSRoutes
+	tlsListenerPrefix = "tls-"
+
+	// tcpListenerPrefix is the section name prefix for TCP listeners, followed by the port
+	tcpListenerPrefix = "tcp-"
+
+	// httpListenerPrefix is the section name prefix for plain HTTP listeners
+	httpListenerPrefix = "http-"
+
+	// wildcardListenerName is the section name of catch-all listeners without a hostname
+	wildcardListenerName = "wildcard"
+
+	// hostnameFallbackMatchRules is the AnnotationHostnameFallback value that derives hostnames from match rules
+	hostnameFallbackMatchRules = "match-rules"
+
+	// hostnameFallbackWildcard is the AnnotationHostnameFallback value that creates a catch-all listener
+	hostnameFallbackWildcard = "wildcard"
+
+	// protocolHTTP is the AnnotationProtocol value that selects plain HTTP listeners
+	protocolHTTP = "HTTP"
+
+	// tlsModePassthrough is the AnnotationTLSMode value that selects TLS passthrough
+	tlsModePassthrough = "passthrough"
+
+	// ipFamilyInfrastructureAnnotation is the Gateway infrastructure annotation asking the IPAM and
+	// load balancer stack for the address families of the Gateway's service
+	ipFamilyInfrastructureAnnotation = "ipam.vitistack.io/ip-family"
 
 	// defaultIPAMZone is the default IPAM zone if not specified
 	defaultIPAMZone = "hnet-private"
+
+	// namespaceTemplatePlaceholder is replaced by the namespace name in name templates
+	namespaceTemplatePlaceholder = "{namespace}"
+
+	// fieldManager is the field manager the operator applies changes as
+	fieldManager = "gatewayapi-operator"
+
+	// legacyCreateFieldManager is the field manager older versions of the operator created Gateways
+	// as, the client's default taken from the name of the manager binary
+	legacyCreateFieldManager = "manager"
+
+	// managedByLabel marks resources created and owned by the operator
+	managedByLabel = "app.kubernetes.io/managed-by"
+
+	// managedByValue is the managedByLabel value for resources owned by the operator
+	managedByValue = "gatewayapi-operator"
+
+	// referenceGrantPrefix is the name prefix of ReferenceGrants created by the operator,
+	// followed by the namespace the grant is for
+	referenceGrantPrefix = "gatewayapi-operator-"
+
+	// secretReferenceGrantPrefix is the name prefix of ReferenceGrants created by the operator
+	// to let a Gateway reference certificates in another namespace, followed by the gateway's
+	// namespace and name
+	secretReferenceGrantPrefix = "gatewayapi-operator-secrets-"
 )
 
 // ptr returns a pointer to the provided string
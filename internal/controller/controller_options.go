@@ -0,0 +1,33 @@
+package controller
+
+import (
+	"golang.org/x/time/rate"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// Overall rate at which a controller requeues failed reconciles, on top of the backoff per
+// object, matching client-go's default controller rate limiter
+const (
+	requeueQPS   = 10
+	requeueBurst = 100
+)
+
+// controllerOptions returns the options of the operator's controllers: MaxConcurrentReconciles
+// reconciles in parallel, and failed reconciles requeued with a backoff per object doubling from
+// RequeueBaseDelay up to RequeueMaxDelay, so a failing Gateway or route backs off instead of
+// hot-looping, within an overall rate limit that spreads requeue storms, e.g. after a restart.
+// Zero delays keep controller-runtime's defaults.
+func (r *GatewayManager) controllerOptions() controller.Options {
+	options := controller.Options{
+		MaxConcurrentReconciles: r.MaxConcurrentReconciles,
+	}
+	if r.RequeueBaseDelay > 0 && r.RequeueMaxDelay > 0 {
+		options.RateLimiter = workqueue.NewTypedMaxOfRateLimiter(
+			workqueue.NewTypedItemExponentialFailureRateLimiter[reconcile.Request](r.RequeueBaseDelay, r.RequeueMaxDelay),
+			&workqueue.TypedBucketRateLimiter[reconcile.Request]{Limiter: rate.NewLimiter(rate.Limit(requeueQPS), requeueBurst)},
+		)
+	}
+	return options
+}
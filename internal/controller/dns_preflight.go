@@ -0,0 +1,95 @@
+package controller
+
+import (
+	"context"
+	"net"
+	"net/netip"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// dnsPreflightTimeout bounds how long resolving a listener hostname may take
+const dnsPreflightTimeout = 2 * time.Second
+
+// dnsPreflightWarnings counts listener hostnames whose DNS records didn't point at their Gateway
+// when the listener was added, by reason
+var dnsPreflightWarnings = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "gatewayapi_operator_dns_preflight_warnings_total",
+		Help: "Listener hostnames added to managed Gateways without DNS records, with records pointing elsewhere, " +
+			"or outside the address ranges of the Gateway's zone, by reason",
+	},
+	[]string{"reason"},
+)
+
+func init() {
+	metrics.Registry.MustRegister(dnsPreflightWarnings)
+}
+
+// checkListenerDNS resolves the hostnames of listeners about to be added to a gateway and warns
+// with an event and the preflight metric when a hostname has no records, or when the gateway has
+// addresses and the records point elsewhere, so a certificate issued for a hostname whose DNS
+// points at another gateway or cluster is noticed early. The listeners are added regardless.
+// Wildcard hostnames are not checked.
+func (r *GatewayManager) checkListenerDNS(ctx context.Context, gateway *gatewayv1.Gateway, listeners []gatewayv1.Listener) {
+	if !r.DNSPreflight {
+		return
+	}
+	log := logf.FromContext(ctx)
+
+	var gatewayAddresses []netip.Addr
+	for _, address := range gateway.Status.Addresses {
+		if addr, err := netip.ParseAddr(address.Value); err == nil {
+			gatewayAddresses = append(gatewayAddresses, addr.Unmap())
+		}
+	}
+	zoneRanges := r.ZoneAddressRanges[gatewayZone(gateway)]
+
+	checked := make(map[gatewayv1.Hostname]bool)
+	for _, listener := range listeners {
+		if listener.Hostname == nil || checked[*listener.Hostname] || strings.HasPrefix(string(*listener.Hostname), "*") {
+			continue
+		}
+		hostname := *listener.Hostname
+		checked[hostname] = true
+
+		lookupCtx, cancel := context.WithTimeout(ctx, dnsPreflightTimeout)
+		records, err := net.DefaultResolver.LookupNetIP(lookupCtx, "ip", string(hostname))
+		cancel()
+		if err != nil || len(records) == 0 {
+			log.Info("Listener hostname doesn't resolve", "gateway", gateway.Name, "hostname", hostname, "error", err)
+			dnsPreflightWarnings.WithLabelValues("unresolved").Inc()
+			r.Recorder.Eventf(gateway, corev1.EventTypeWarning, "DNSUnresolved",
+				"Hostname %s of listener %s has no DNS records yet", hostname, listener.Name)
+			continue
+		}
+		for i := range records {
+			records[i] = records[i].Unmap()
+		}
+		if len(gatewayAddresses) == 0 || slices.ContainsFunc(records, func(addr netip.Addr) bool {
+			return slices.Contains(gatewayAddresses, addr)
+		}) {
+			continue
+		}
+
+		reason, message := "mismatch", "points at %v instead of the Gateway's addresses %v"
+		if len(zoneRanges) > 0 && !slices.ContainsFunc(records, func(addr netip.Addr) bool {
+			return slices.ContainsFunc(zoneRanges, func(prefix netip.Prefix) bool { return prefix.Contains(addr) })
+		}) {
+			reason, message = "foreign", "points at %v outside the address ranges of the Gateway's zone, it may be "+
+				"served by another cluster, instead of the Gateway's addresses %v"
+		}
+		log.Info("Listener hostname points elsewhere", "gateway", gateway.Name, "hostname", hostname,
+			"records", records, "addresses", gatewayAddresses, "reason", reason)
+		dnsPreflightWarnings.WithLabelValues(reason).Inc()
+		r.Recorder.Eventf(gateway, corev1.EventTypeWarning, "DNSMismatch",
+			"Hostname %s of listener %s "+message, hostname, listener.Name, records, gatewayAddresses)
+	}
+}
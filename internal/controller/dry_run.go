@@ -0,0 +1,144 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// dryRunFor reports whether changes for the route are only planned, either because the operator
+// runs in dry-run mode or because the route asks for it
+func (r *GatewayManager) dryRunFor(route client.Object) bool {
+	return r.DryRun || route.GetAnnotations()[AnnotationDryRun] == "true"
+}
+
+// planRoute computes the listeners the route's gateway would get with the route included, and
+// reports how they differ from the gateway as it is in the log and a DryRun event on the route.
+// Nothing is written.
+func (r *GatewayManager) planRoute(
+	ctx context.Context,
+	route client.Object,
+	gatewayName, gatewayNamespace string,
+) error {
+	log := logf.FromContext(ctx)
+
+	listeners, _, err := r.collectListenersForGateway(ctx, gatewayName, gatewayNamespace, route)
+	if err != nil {
+		return err
+	}
+
+	var gateway gatewayv1.Gateway
+	if err := r.Get(ctx, client.ObjectKey{Name: gatewayName, Namespace: gatewayNamespace}, &gateway); err != nil {
+		if client.IgnoreNotFound(err) != nil {
+			return err
+		}
+		log.Info("Dry run: would create Gateway", "gateway", gatewayName, "namespace", gatewayNamespace, "listeners", len(listeners))
+		r.Recorder.Eventf(route, corev1.EventTypeNormal, "DryRun",
+			"Would create Gateway %s/%s with listeners %s", gatewayNamespace, gatewayName, listenerNames(listeners))
+		return nil
+	}
+
+	if !isManagedGateway(&gateway) {
+		log.Info("Dry run: Gateway is not managed by the operator, would not change it", "gateway", gatewayName, "namespace", gatewayNamespace)
+		r.Recorder.Eventf(route, corev1.EventTypeNormal, "DryRun",
+			"Would not change Gateway %s/%s, it is not managed by the operator", gatewayNamespace, gatewayName)
+		return nil
+	}
+
+	desired, _ := withoutForeignConflicts(listeners, foreignListeners(&gateway))
+	diff := diffListeners(&gateway, desired)
+	log.Info("Dry run: planned Gateway listener changes", "gateway", gatewayName, "namespace", gatewayNamespace, "changes", diff)
+	r.Recorder.Eventf(route, corev1.EventTypeNormal, "DryRun", "Gateway %s/%s: %s", gatewayNamespace, gatewayName, diff)
+	return nil
+}
+
+// planGateway reports how the gateway's listeners would change in the log and a DryRun event on
+// the gateway, without writing anything
+func (r *GatewayManager) planGateway(ctx context.Context, gateway *gatewayv1.Gateway) error {
+	log := logf.FromContext(ctx)
+
+	listeners, routeCount, err := r.collectListenersForGateway(ctx, gateway.Name, gateway.Namespace)
+	if err != nil {
+		return err
+	}
+	if routeCount == 0 {
+		policy := r.deletionPolicyFor(ctx, gateway)
+		log.Info("Dry run: no routes reference the gateway, would apply its deletion policy", "gateway", gateway.Name,
+			"namespace", gateway.Namespace, "policy", policy)
+		r.Recorder.Eventf(gateway, corev1.EventTypeNormal, "DryRun",
+			"No routes reference the Gateway, would apply deletion policy %s", policy)
+		return nil
+	}
+
+	desired, _ := withoutForeignConflicts(listeners, foreignListeners(gateway))
+	if !listenersDrifted(gateway, desired) {
+		return nil
+	}
+	diff := diffListeners(gateway, desired)
+	log.Info("Dry run: planned Gateway listener changes", "gateway", gateway.Name, "namespace", gateway.Namespace, "changes", diff)
+	r.Recorder.Eventf(gateway, corev1.EventTypeNormal, "DryRun", "Listeners: %s", diff)
+	return nil
+}
+
+// listenerChanges returns the desired listeners the gateway doesn't have yet, the ones it has with
+// another port, protocol or hostname, and the listeners the operator applied that are no longer desired
+func listenerChanges(gateway *gatewayv1.Gateway, desired []gatewayv1.Listener) (added, changed, removed []gatewayv1.Listener) {
+	current := make(map[gatewayv1.SectionName]gatewayv1.Listener, len(gateway.Spec.Listeners))
+	for _, listener := range gateway.Spec.Listeners {
+		current[listener.Name] = listener
+	}
+
+	applied := appliedListeners(gateway)
+	wanted := make(map[gatewayv1.SectionName]bool, len(desired))
+	for _, listener := range desired {
+		wanted[listener.Name] = true
+		existing, exists := current[listener.Name]
+		switch {
+		case !exists:
+			added = append(added, listener)
+		case existing.Port != listener.Port || existing.Protocol != listener.Protocol || !sameHostname(existing.Hostname, listener.Hostname):
+			changed = append(changed, listener)
+		}
+	}
+	for _, listener := range gateway.Spec.Listeners {
+		if applied[listener.Name] && !wanted[listener.Name] {
+			removed = append(removed, listener)
+		}
+	}
+	return added, changed, removed
+}
+
+// diffListeners describes how the operator would change the gateway's listeners to get the desired ones
+func diffListeners(gateway *gatewayv1.Gateway, desired []gatewayv1.Listener) string {
+	added, changed, removed := listenerChanges(gateway, desired)
+	if len(added)+len(changed)+len(removed) == 0 {
+		return "no listener changes"
+	}
+	var parts []string
+	if len(added) > 0 {
+		parts = append(parts, "would add "+listenerNames(added))
+	}
+	if len(changed) > 0 {
+		parts = append(parts, "would change "+listenerNames(changed))
+	}
+	if len(removed) > 0 {
+		parts = append(parts, "would remove "+listenerNames(removed))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// listenerNames formats the names of listeners for logs and events
+func listenerNames(listeners []gatewayv1.Listener) string {
+	names := make([]string, 0, len(listeners))
+	for _, listener := range listeners {
+		names = append(names, string(listener.Name))
+	}
+	slices.Sort(names)
+	return fmt.Sprintf("[%s]", strings.Join(names, ", "))
+}
@@ -0,0 +1,139 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	"sigs.k8s.io/yaml"
+)
+
+// +kubebuilder:rbac:groups=gateway.envoyproxy.io,resources=envoyproxies,verbs=get;list;watch;create;update;patch;delete
+
+// envoyProxySuffix is the suffix of the name of the EnvoyProxy created for a gateway
+const envoyProxySuffix = "-envoy-proxy"
+
+// envoyProxyGVK is the Envoy Gateway EnvoyProxy
+var envoyProxyGVK = schema.GroupVersionKind{
+	Group:   "gateway.envoyproxy.io",
+	Version: "v1alpha1",
+	Kind:    "EnvoyProxy",
+}
+
+// parseZoneEnvoyProxies parses the zoneEnvoyProxies key of the operator ConfigMap: a YAML map of
+// IPAM zones to the spec of the EnvoyProxy of the zone's Gateways
+func parseZoneEnvoyProxies(value string) (map[string]map[string]any, error) {
+	specs := make(map[string]map[string]any)
+	if value == "" {
+		return specs, nil
+	}
+	if err := yaml.Unmarshal([]byte(value), &specs); err != nil {
+		return nil, err
+	}
+	for _, zone := range slices.Sorted(maps.Keys(specs)) {
+		if len(specs[zone]) == 0 {
+			return nil, fmt.Errorf("the EnvoyProxy spec of zone %q is empty", zone)
+		}
+	}
+	return specs, nil
+}
+
+// zoneEnvoyProxy returns the EnvoyProxy spec of the gateway's IPAM zone, or nil when the zone's
+// Gateways use the GatewayClass's Envoy deployment
+func (r *GatewayManager) zoneEnvoyProxy(gateway *gatewayv1.Gateway) map[string]any {
+	// The configuration was validated when it was loaded
+	specs, _ := parseZoneEnvoyProxies(r.config().ZoneEnvoyProxies)
+	return specs[gatewayZone(gateway)]
+}
+
+// envoyProxySpec returns the spec of the gateway's EnvoyProxy: the spec of its IPAM zone with the
+// access log settings of the gateway, see accessLogSpec. Access logs set in the zone's spec take
+// precedence. It returns nil when the gateway needs no EnvoyProxy of its own.
+func (r *GatewayManager) envoyProxySpec(ctx context.Context, gateway *gatewayv1.Gateway) (map[string]any, error) {
+	spec := r.zoneEnvoyProxy(gateway)
+	accessLog, err := r.accessLogSpec(ctx, gateway)
+	if err != nil || accessLog == nil {
+		return spec, err
+	}
+	if spec == nil {
+		spec = make(map[string]any)
+	}
+	telemetry, ok := spec["telemetry"].(map[string]any)
+	if !ok {
+		telemetry = make(map[string]any)
+		spec["telemetry"] = telemetry
+	}
+	if _, set := telemetry["accessLog"]; !set {
+		telemetry["accessLog"] = accessLog
+	}
+	return spec, nil
+}
+
+// setEnvoyProxyRef points the gateway's infrastructure at its EnvoyProxy when it has one, see
+// envoyProxySpec
+func setEnvoyProxyRef(gateway *gatewayv1.Gateway, spec map[string]any) {
+	if spec == nil {
+		return
+	}
+	if gateway.Spec.Infrastructure == nil {
+		gateway.Spec.Infrastructure = &gatewayv1.GatewayInfrastructure{}
+	}
+	gateway.Spec.Infrastructure.ParametersRef = &gatewayv1.LocalParametersReference{
+		Group: gatewayv1.Group(envoyProxyGVK.Group),
+		Kind:  gatewayv1.Kind(envoyProxyGVK.Kind),
+		Name:  gateway.Name + envoyProxySuffix,
+	}
+}
+
+// syncEnvoyProxy applies the EnvoyProxy with the Envoy deployment of the gateway's IPAM zone and
+// its access log settings, and deletes it when there are none
+func (r *GatewayManager) syncEnvoyProxy(ctx context.Context, gateway *gatewayv1.Gateway) error {
+	log := logf.FromContext(ctx)
+	name := gateway.Name + envoyProxySuffix
+
+	spec, err := r.envoyProxySpec(ctx, gateway)
+	if err != nil {
+		return err
+	}
+	if spec == nil {
+		proxy := &unstructured.Unstructured{}
+		proxy.SetGroupVersionKind(envoyProxyGVK)
+		if err := r.Get(ctx, client.ObjectKey{Name: name, Namespace: gateway.Namespace}, proxy); err != nil {
+			if meta.IsNoMatchError(err) {
+				return nil
+			}
+			return client.IgnoreNotFound(err)
+		}
+		if !metav1.IsControlledBy(proxy, gateway) {
+			return nil
+		}
+		if err := r.Delete(ctx, proxy); client.IgnoreNotFound(err) != nil {
+			return err
+		}
+		log.Info("Deleted EnvoyProxy", "envoyProxy", name, "namespace", gateway.Namespace)
+		return nil
+	}
+
+	proxy := &unstructured.Unstructured{}
+	proxy.SetGroupVersionKind(envoyProxyGVK)
+	proxy.SetName(name)
+	proxy.SetNamespace(gateway.Namespace)
+	proxy.SetLabels(map[string]string{managedByLabel: managedByValue})
+	proxy.SetOwnerReferences([]metav1.OwnerReference{
+		*metav1.NewControllerRef(gateway, gatewayv1.SchemeGroupVersion.WithKind("Gateway")),
+	})
+	proxy.Object["spec"] = spec
+	if err := r.Patch(ctx, proxy, client.Apply, client.ForceOwnership, client.FieldOwner(fieldManager)); err != nil {
+		return err
+	}
+	log.V(1).Info("Applied EnvoyProxy", "envoyProxy", name, "namespace", gateway.Namespace)
+	return nil
+}
@@ -0,0 +1,58 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// jsonPatchOperation is an operation of a JSON patch
+type jsonPatchOperation struct {
+	Op    string `json:"op"`
+	Path  string `json:"path"`
+	Value any    `json:"value,omitempty"`
+}
+
+// addFinalizer adds the finalizer to the object with a JSON patch appending it to the finalizers,
+// so finalizers other controllers add or remove at the same time are kept. An object without
+// finalizers only gets the finalizer when it is unchanged since it was read.
+func (r *GatewayManager) addFinalizer(ctx context.Context, obj client.Object, finalizer string) error {
+	if slices.Contains(obj.GetFinalizers(), finalizer) {
+		return nil
+	}
+	operations := []jsonPatchOperation{{Op: "add", Path: "/metadata/finalizers/-", Value: finalizer}}
+	if len(obj.GetFinalizers()) == 0 {
+		operations = []jsonPatchOperation{
+			{Op: "test", Path: "/metadata/resourceVersion", Value: obj.GetResourceVersion()},
+			{Op: "add", Path: "/metadata/finalizers", Value: []string{finalizer}},
+		}
+	}
+	return r.patchFinalizers(ctx, obj, operations)
+}
+
+// removeFinalizer removes the finalizer from the object with a JSON patch removing just that entry,
+// which fails instead of removing another finalizer when the finalizers changed since they were read
+func (r *GatewayManager) removeFinalizer(ctx context.Context, obj client.Object, finalizer string) error {
+	index := slices.Index(obj.GetFinalizers(), finalizer)
+	if index < 0 {
+		return nil
+	}
+	path := fmt.Sprintf("/metadata/finalizers/%d", index)
+	return r.patchFinalizers(ctx, obj, []jsonPatchOperation{
+		{Op: "test", Path: path, Value: finalizer},
+		{Op: "remove", Path: path},
+	})
+}
+
+// patchFinalizers applies the JSON patch operations to the object
+func (r *GatewayManager) patchFinalizers(ctx context.Context, obj client.Object, operations []jsonPatchOperation) error {
+	patch, err := json.Marshal(operations)
+	if err != nil {
+		return err
+	}
+	return r.Patch(ctx, obj, client.RawPatch(types.JSONPatchType, patch))
+}
@@ -0,0 +1,143 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"net/netip"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// gatewayAddress returns the Gateway address requested with the address annotation. Values that
+// parse as an IP address are IPAddress addresses, anything else is a NamedAddress understood by
+// the load balancer implementation.
+func gatewayAddress(value string) gatewayv1.GatewayAddress {
+	addressType := gatewayv1.NamedAddressType
+	if _, err := netip.ParseAddr(value); err == nil {
+		addressType = gatewayv1.IPAddressType
+	}
+	return gatewayv1.GatewayAddress{Type: &addressType, Value: value}
+}
+
+// validateAddress checks that the static address a route asks for belongs to its IPAM zone. Named
+// addresses, and IP addresses in zones without configured address ranges, are not checked.
+func (r *GatewayManager) validateAddress(settings gatewaySettings) error {
+	if settings.Address == "" {
+		return nil
+	}
+	addr, err := netip.ParseAddr(settings.Address)
+	if err != nil {
+		return nil
+	}
+
+	ranges := r.ZoneAddressRanges[settings.IPAMZone]
+	if len(ranges) == 0 {
+		return nil
+	}
+	for _, prefix := range ranges {
+		if prefix.Contains(addr) {
+			return nil
+		}
+	}
+	return errors.NewBadRequest(fmt.Sprintf("Route address '%s' is not in the address ranges of IPAM zone '%s'",
+		settings.Address, settings.IPAMZone))
+}
+
+// hasAddress reports whether the gateway requests the address
+func hasAddress(gateway *gatewayv1.Gateway, address string) bool {
+	for _, existing := range gateway.Spec.Addresses {
+		if existing.Value == address {
+			return true
+		}
+	}
+	return false
+}
+
+// requestedAddress returns the static address the routes owning a gateway ask for, if any. When
+// routes disagree, the oldest route wins.
+func (r *GatewayManager) requestedAddress(ctx context.Context, gatewayName, gatewayNamespace string) (string, error) {
+	routes, err := r.gatewayOwners(ctx, gatewayName, gatewayNamespace)
+	if err != nil {
+		return "", err
+	}
+	sortOldestFirst(routes)
+
+	for _, route := range routes {
+		if address := route.GetAnnotations()[AnnotationAddress]; address != "" {
+			return address, nil
+		}
+	}
+	return "", nil
+}
+
+// setGatewayAddress requests the static address on the gateway. Addresses are replaced as a whole,
+// so nothing is set when no address is requested, leaving addresses managed by others alone.
+func setGatewayAddress(gateway *gatewayv1.Gateway, address string) {
+	if address == "" {
+		return
+	}
+	gateway.Spec.Addresses = []gatewayv1.GatewayAddress{gatewayAddress(address)}
+}
+
+// assignedAddresses returns the addresses assigned to the gateway, comma separated
+func assignedAddresses(gateway *gatewayv1.Gateway) string {
+	addresses := make([]string, 0, len(gateway.Status.Addresses))
+	for _, address := range gateway.Status.Addresses {
+		addresses = append(addresses, address.Value)
+	}
+	return strings.Join(addresses, ",")
+}
+
+// addressesChanged is a predicate passing updates of Gateways that changed their assigned addresses
+var addressesChanged = predicate.Funcs{
+	UpdateFunc: func(e event.UpdateEvent) bool {
+		oldGateway, ok := e.ObjectOld.(*gatewayv1.Gateway)
+		if !ok {
+			return false
+		}
+		newGateway, ok := e.ObjectNew.(*gatewayv1.Gateway)
+		return ok && assignedAddresses(oldGateway) != assignedAddresses(newGateway)
+	},
+}
+
+// propagateGatewayAddress writes the addresses assigned to a gateway onto the routes it serves, the
+// routes whose first parent reference is the gateway, and tells their owners with an event which
+// address to point DNS at. Application teams often can't read Gateways in other namespaces.
+func (r *GatewayManager) propagateGatewayAddress(ctx context.Context, gateway *gatewayv1.Gateway) error {
+	address := assignedAddresses(gateway)
+	if address == "" {
+		return nil
+	}
+	routes, err := r.gatewayOwners(ctx, gateway.Name, gateway.Namespace)
+	if err != nil {
+		return err
+	}
+	for _, route := range routes {
+		name, namespace := parentGateway(route.GetNamespace(), route.ParentRefs[0])
+		if name != gateway.Name || namespace != gateway.Namespace || route.GetAnnotations()[gatewayAddressAnnotationKey] == address {
+			continue
+		}
+		patch := client.MergeFrom(route.DeepCopyObject().(client.Object))
+		annotations := route.GetAnnotations()
+		if annotations == nil {
+			annotations = make(map[string]string)
+		}
+		annotations[gatewayAddressAnnotationKey] = address
+		route.SetAnnotations(annotations)
+		if err := r.Patch(ctx, route.Object, patch); client.IgnoreNotFound(err) != nil {
+			return err
+		}
+		logf.FromContext(ctx).Info("Propagated Gateway address to route", "gateway", gateway.Name, "namespace", gateway.Namespace,
+			"route", route.GetName(), "routeNamespace", route.GetNamespace(), "address", address)
+		r.Recorder.Eventf(route.Object, corev1.EventTypeNormal, "GatewayAddressAssigned",
+			"Gateway %s/%s has address %s, point the DNS records of the route's hostnames at it", gateway.Namespace, gateway.Name, address)
+	}
+	return nil
+}
@@ -0,0 +1,169 @@
+package controller
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// appliedUnchanged reports whether applying the patch would leave the gateway unchanged: every
+// field of the patch already has its value on the gateway, and every field the operator applied
+// before is still in the patch, so the apply would neither set nor remove anything. Listeners are
+// matched by name, their order doesn't matter. Fields set by the API server's defaulting are
+// ignored, as they aren't part of the patch.
+func appliedUnchanged(gateway, patch *gatewayv1.Gateway) bool {
+	current, err := toJSONMap(gateway)
+	if err != nil {
+		return false
+	}
+	desired, err := toJSONMap(patch)
+	if err != nil {
+		return false
+	}
+	for _, key := range []string{"metadata", "spec"} {
+		if !jsonSubset(desired[key], current[key], false) {
+			return false
+		}
+	}
+
+	for _, entry := range gateway.ManagedFields {
+		if entry.Manager != fieldManager || entry.Operation != metav1.ManagedFieldsOperationApply ||
+			entry.Subresource != "" || entry.FieldsV1 == nil {
+			continue
+		}
+		var owned map[string]any
+		if err := json.Unmarshal(entry.FieldsV1.Raw, &owned); err != nil {
+			return false
+		}
+		if !fieldsCovered(owned, desired) {
+			return false
+		}
+	}
+	return true
+}
+
+// toJSONMap converts an object to its JSON representation as a map
+func toJSONMap(obj any) (map[string]any, error) {
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+	var m map[string]any
+	return m, json.Unmarshal(data, &m)
+}
+
+// jsonSubset reports whether every value set in desired has the same value in current. Lists of
+// named items are matched by name. Unless keyed, like the listeners other tools can add to, the
+// lists must have the same items, as the apply replaces them as a whole.
+func jsonSubset(desired, current any, keyed bool) bool {
+	switch desired := desired.(type) {
+	case nil:
+		return true
+	case map[string]any:
+		current, ok := current.(map[string]any)
+		if !ok {
+			return false
+		}
+		for key, value := range desired {
+			if value == nil {
+				continue
+			}
+			if !jsonSubset(value, current[key], key == "listeners") {
+				return false
+			}
+		}
+		return true
+	case []any:
+		current, ok := current.([]any)
+		if !ok || (!keyed && len(current) != len(desired)) {
+			return false
+		}
+		for i, item := range desired {
+			match := itemNamed(current, item)
+			if match == nil && !keyed && i < len(current) {
+				match = current[i]
+			}
+			if !jsonSubset(item, match, false) {
+				return false
+			}
+		}
+		return true
+	default:
+		return reflect.DeepEqual(desired, current)
+	}
+}
+
+// itemNamed returns the item of a list with the same name as item, or nil
+func itemNamed(list []any, item any) any {
+	named, ok := item.(map[string]any)
+	if !ok || named["name"] == nil {
+		return nil
+	}
+	for _, candidate := range list {
+		if other, ok := candidate.(map[string]any); ok && other["name"] == named["name"] {
+			return candidate
+		}
+	}
+	return nil
+}
+
+// fieldsCovered reports whether every field in a managed fields set, e.g. {"f:spec":{"f:listeners":
+// {"k:{\"name\":\"a\"}":{}}}}, is set in the object, so applying the object keeps owning it
+func fieldsCovered(owned map[string]any, obj any) bool {
+	for key, children := range owned {
+		var child any
+		switch {
+		case key == ".":
+			continue
+		case strings.HasPrefix(key, "f:"):
+			fields, ok := obj.(map[string]any)
+			if !ok {
+				return false
+			}
+			child = fields[strings.TrimPrefix(key, "f:")]
+		case strings.HasPrefix(key, "k:"):
+			var keyFields map[string]any
+			if err := json.Unmarshal([]byte(strings.TrimPrefix(key, "k:")), &keyFields); err != nil {
+				return false
+			}
+			child = itemWithKey(obj, keyFields)
+		default:
+			// Sets and lists owned by index are replaced by the apply as a whole, compared by jsonSubset
+			continue
+		}
+		if child == nil {
+			return false
+		}
+		if nested, ok := children.(map[string]any); ok && len(nested) > 0 {
+			if !fieldsCovered(nested, child) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// itemWithKey returns the item of a list whose key fields have the given values, or nil
+func itemWithKey(list any, keyFields map[string]any) any {
+	items, _ := list.([]any)
+	for _, item := range items {
+		fields, ok := item.(map[string]any)
+		if !ok {
+			continue
+		}
+		matches := true
+		for key, value := range keyFields {
+			if !reflect.DeepEqual(fields[key], value) {
+				matches = false
+				break
+			}
+		}
+		if matches {
+			return item
+		}
+	}
+	return nil
+}
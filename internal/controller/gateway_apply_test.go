@@ -0,0 +1,163 @@
+package controller
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func TestJSONSubset(t *testing.T) {
+	listener := func(name string, port int) map[string]any {
+		return map[string]any{"name": name, "port": float64(port)}
+	}
+	tests := []struct {
+		name    string
+		desired any
+		current any
+		keyed   bool
+		want    bool
+	}{
+		{name: "nil", desired: nil, current: map[string]any{"a": "b"}, want: true},
+		{name: "equal values", desired: "a", current: "a", want: true},
+		{name: "different values", desired: "a", current: "b", want: false},
+		{name: "extra current fields", desired: map[string]any{"a": "b"}, current: map[string]any{"a": "b", "c": "d"}, want: true},
+		{name: "missing field", desired: map[string]any{"a": "b"}, current: map[string]any{}, want: false},
+		{name: "null desired field", desired: map[string]any{"a": nil}, current: map[string]any{}, want: true},
+		{name: "not a map", desired: map[string]any{"a": "b"}, current: "a", want: false},
+		{
+			name:    "named items in another order",
+			desired: []any{listener("a", 443), listener("b", 443)},
+			current: []any{listener("b", 443), listener("a", 443)},
+			want:    true,
+		},
+		{
+			name:    "extra item in a replaced list",
+			desired: []any{listener("a", 443)},
+			current: []any{listener("a", 443), listener("b", 443)},
+			want:    false,
+		},
+		{
+			name:    "extra item in a keyed list",
+			desired: []any{listener("a", 443)},
+			current: []any{listener("a", 443), listener("b", 443)},
+			keyed:   true,
+			want:    true,
+		},
+		{
+			name:    "missing item in a keyed list",
+			desired: []any{listener("a", 443), listener("b", 443)},
+			current: []any{listener("a", 443)},
+			keyed:   true,
+			want:    false,
+		},
+		{
+			name:    "changed item",
+			desired: []any{listener("a", 443)},
+			current: []any{listener("a", 8443)},
+			want:    false,
+		},
+		{name: "unnamed items by index", desired: []any{"a", "b"}, current: []any{"a", "b"}, want: true},
+		{name: "unnamed items in another order", desired: []any{"a", "b"}, current: []any{"b", "a"}, want: false},
+		{
+			name:    "listeners are keyed",
+			desired: map[string]any{"listeners": []any{listener("a", 443)}},
+			current: map[string]any{"listeners": []any{listener("a", 443), listener("b", 443)}},
+			want:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := jsonSubset(tt.desired, tt.current, tt.keyed); got != tt.want {
+				t.Errorf("jsonSubset() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAppliedUnchanged(t *testing.T) {
+	gatewayWith := func(labels map[string]string, listeners ...string) *gatewayv1.Gateway {
+		gateway := &gatewayv1.Gateway{
+			ObjectMeta: metav1.ObjectMeta{Name: "gateway", Namespace: "team", Labels: labels},
+			Spec:       gatewayv1.GatewaySpec{GatewayClassName: "envoy"},
+		}
+		for _, name := range listeners {
+			gateway.Spec.Listeners = append(gateway.Spec.Listeners, gatewayv1.Listener{
+				Name:     gatewayv1.SectionName(name),
+				Protocol: gatewayv1.HTTPSProtocolType,
+				Port:     httpsPort,
+			})
+		}
+		return gateway
+	}
+	applied := func(fields string) []metav1.ManagedFieldsEntry {
+		return []metav1.ManagedFieldsEntry{{
+			Manager:   fieldManager,
+			Operation: metav1.ManagedFieldsOperationApply,
+			FieldsV1:  &metav1.FieldsV1{Raw: []byte(fields)},
+		}}
+	}
+	labels := map[string]string{"team": "a"}
+	tests := []struct {
+		name          string
+		gateway       *gatewayv1.Gateway
+		managedFields []metav1.ManagedFieldsEntry
+		patch         *gatewayv1.Gateway
+		want          bool
+	}{
+		{
+			name:    "same listeners",
+			gateway: gatewayWith(labels, "a", "b"),
+			patch:   gatewayWith(labels, "b", "a"),
+			want:    true,
+		},
+		{
+			name:    "new listener",
+			gateway: gatewayWith(labels, "a"),
+			patch:   gatewayWith(labels, "a", "b"),
+			want:    false,
+		},
+		{
+			name:    "changed label",
+			gateway: gatewayWith(labels, "a"),
+			patch:   gatewayWith(map[string]string{"team": "b"}, "a"),
+			want:    false,
+		},
+		{
+			name:    "listener of another field manager",
+			gateway: gatewayWith(labels, "a", "foreign"),
+			patch:   gatewayWith(labels, "a"),
+			want:    true,
+		},
+		{
+			name:          "applied listener left out",
+			gateway:       gatewayWith(labels, "a", "b"),
+			managedFields: applied(`{"f:spec":{"f:listeners":{"k:{\"name\":\"a\"}":{},"k:{\"name\":\"b\"}":{}}}}`),
+			patch:         gatewayWith(labels, "a"),
+			want:          false,
+		},
+		{
+			name:          "applied label left out",
+			gateway:       gatewayWith(labels, "a"),
+			managedFields: applied(`{"f:metadata":{"f:labels":{"f:team":{}}}}`),
+			patch:         gatewayWith(nil, "a"),
+			want:          false,
+		},
+		{
+			name:    "applied fields kept",
+			gateway: gatewayWith(labels, "a"),
+			managedFields: applied(`{"f:metadata":{"f:labels":{"f:team":{}}},` +
+				`"f:spec":{"f:gatewayClassName":{},"f:listeners":{"k:{\"name\":\"a\"}":{".":{},"f:port":{}}}}}`),
+			patch: gatewayWith(labels, "a"),
+			want:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.gateway.ManagedFields = tt.managedFields
+			if got := appliedUnchanged(tt.gateway, tt.patch); got != tt.want {
+				t.Errorf("appliedUnchanged() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
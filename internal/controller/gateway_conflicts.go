@@ -0,0 +1,123 @@
+package controller
+
+import (
+	"context"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// GatewayConflictPolicy decides what happens to a route asking for another issuer or IPAM zone
+// than the Gateway it references has
+type GatewayConflictPolicy string
+
+const (
+	// GatewayConflictPolicyReject rejects the route with a mismatch warning event
+	GatewayConflictPolicyReject GatewayConflictPolicy = "Reject"
+
+	// GatewayConflictPolicyFirstWins serves the route from the Gateway with the issuer and zone of
+	// the Gateway, which are the ones its first route asked for
+	GatewayConflictPolicyFirstWins GatewayConflictPolicy = "FirstWins"
+
+	// GatewayConflictPolicySplit moves the route to a Gateway of its own issuer or zone, named after
+	// the Gateway it references
+	GatewayConflictPolicySplit GatewayConflictPolicy = "Split"
+)
+
+// IsValid reports whether the policy is one of the known conflict policies
+func (p GatewayConflictPolicy) IsValid() bool {
+	switch p {
+	case GatewayConflictPolicyReject, GatewayConflictPolicyFirstWins, GatewayConflictPolicySplit:
+		return true
+	}
+	return false
+}
+
+// splitGatewaySuffix returns the suffix of the Gateway routes asking for another issuer or zone
+// than their gateway has are split off to, after the name of their gateway
+func splitGatewaySuffix(value string) string {
+	suffix := strings.Map(func(c rune) rune {
+		if (c >= 'a' && c <= 'z') || (c >= '0' && c <= '9') || c == '-' || c == '.' {
+			return c
+		}
+		return '-'
+	}, strings.ToLower(value))
+	return "-" + strings.Trim(suffix, "-.")
+}
+
+// resolveGatewayConflict applies the conflict policy to a route asking for another issuer or IPAM
+// zone than its gateway has. conflict names what differs, for logs and events, and value is the
+// issuer or zone name the route asks for. It returns the policy applied: with FirstWins the route
+// is served by the gateway as it is, with Split the route was moved to another gateway and is
+// reconciled again against it, and with Reject the caller rejects the route. A route already split
+// off for the same value is rejected, so routes never move from split gateway to split gateway.
+// The decision is reported with events on the route and on the gateway's first route.
+func (r *GatewayManager) resolveGatewayConflict(
+	ctx context.Context,
+	route client.Object,
+	gateway *gatewayv1.Gateway,
+	conflict, gatewayValue, routeValue, value string,
+) (GatewayConflictPolicy, error) {
+	log := logf.FromContext(ctx)
+	policy := r.GatewayConflictPolicy
+	if policy == "" || policy == GatewayConflictPolicyReject {
+		return GatewayConflictPolicyReject, nil
+	}
+
+	suffix := splitGatewaySuffix(value)
+	splitName := gateway.Name + suffix
+	if policy == GatewayConflictPolicySplit && strings.HasSuffix(gateway.Name, suffix) {
+		return GatewayConflictPolicyReject, nil
+	}
+
+	// The gateway's first route is the one it was created for, whose issuer and zone it has
+	owners, err := r.gatewayOwners(ctx, gateway.Name, gateway.Namespace)
+	if err != nil {
+		return "", err
+	}
+	sortOldestFirst(owners)
+	var first client.Object
+	for _, owner := range owners {
+		if owner.GetUID() != route.GetUID() {
+			first = owner.Object
+			break
+		}
+	}
+
+	switch policy {
+	case GatewayConflictPolicyFirstWins:
+		log.Info("Serving route from Gateway with another "+conflict, "gateway", gateway.Name, "namespace", gateway.Namespace,
+			"gatewayValue", gatewayValue, "routeValue", routeValue)
+		r.Recorder.Eventf(route, corev1.EventTypeWarning, "GatewayConflictFirstWins",
+			"Gateway %s/%s has %s %s but the route asks for %s, the route is served with %s, the Gateway's first route asked for it",
+			gateway.Namespace, gateway.Name, conflict, gatewayValue, routeValue, gatewayValue)
+		if first != nil {
+			r.Recorder.Eventf(first, corev1.EventTypeNormal, "GatewayConflictFirstWins",
+				"Route %s/%s asks for %s %s on Gateway %s/%s, it is served with %s this route asked for",
+				route.GetNamespace(), route.GetName(), conflict, routeValue, gateway.Namespace, gateway.Name, gatewayValue)
+		}
+
+	case GatewayConflictPolicySplit:
+		info, ok := newRouteInfo(route)
+		if !ok {
+			return GatewayConflictPolicyReject, nil
+		}
+		if err := r.moveRouteToGateway(ctx, info, gateway.Name, gateway.Namespace, splitName); err != nil {
+			return "", err
+		}
+		log.Info("Split route off to a Gateway of its own "+conflict, "gateway", gateway.Name, "namespace", gateway.Namespace,
+			"splitGateway", splitName, "gatewayValue", gatewayValue, "routeValue", routeValue)
+		r.Recorder.Eventf(route, corev1.EventTypeNormal, "GatewayConflictSplit",
+			"Gateway %s/%s has %s %s but the route asks for %s, route moved to Gateway %s/%s",
+			gateway.Namespace, gateway.Name, conflict, gatewayValue, routeValue, gateway.Namespace, splitName)
+		if first != nil {
+			r.Recorder.Eventf(first, corev1.EventTypeNormal, "GatewayConflictSplit",
+				"Route %s/%s asks for %s %s, it was moved from Gateway %s/%s to Gateway %s/%s",
+				route.GetNamespace(), route.GetName(), conflict, routeValue, gateway.Namespace, gateway.Name, gateway.Namespace, splitName)
+		}
+	}
+	return policy, nil
+}
@@ -0,0 +1,214 @@
+package controller
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+)
+
+// GatewayReconciler computes the listeners of managed Gateways. Route changes enqueue the
+// Gateways the route references, before and after the change, after GatewayUpdateDelay, so a
+// burst of route changes results in a single listener update per Gateway.
+type GatewayReconciler struct {
+	*GatewayManager
+}
+
+// +kubebuilder:rbac:groups=gateway.networking.k8s.io,resources=gateways,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch
+
+// Reconcile applies the listeners of all routes referencing a Gateway to it, and applies the
+// deletion policy when no routes reference it anymore. The certificates of its listeners are
+// checked for expiry on every reconcile, and at least every certificateExpiryCheckInterval.
+func (r *GatewayReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Result, err error) {
+	log := logf.FromContext(ctx)
+	defer func() {
+		if err == nil {
+			r.markStartupReconciled("Gateway", req.NamespacedName)
+		}
+	}()
+
+	// Gateways handled by another operator instance are left to it
+	if !r.gatewayInShard(req.Name, req.Namespace) || shuttingDown(ctx) {
+		return ctrl.Result{}, nil
+	}
+	ctx, cancel := r.drainContext(ctx)
+	defer cancel()
+
+	// Gateways are created by the route reconcilers, which know the zone and issuer to use
+	var gateway gatewayv1.Gateway
+	if err := r.Get(ctx, req.NamespacedName, &gateway); err != nil {
+		if client.IgnoreNotFound(err) == nil {
+			forgetCertificateExpiry(req.Name, req.Namespace)
+		}
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	if gateway.DeletionTimestamp.IsZero() && r.createdBeforeManagedAnnotation(&gateway) && !r.DryRun {
+		// The annotation change enqueues the Gateway again
+		return ctrl.Result{}, r.migrateManagedAnnotation(ctx, &gateway)
+	}
+	if !gateway.DeletionTimestamp.IsZero() || !isManagedGateway(&gateway) {
+		forgetCertificateExpiry(gateway.Name, gateway.Namespace)
+		return ctrl.Result{}, nil
+	}
+
+	defer r.lockGateway(gateway.Name, gateway.Namespace)()
+
+	// Only one listener computation per Gateway is in flight: the next one waits until the cache
+	// has observed the operator's last write, so it doesn't undo it from stale routes or listeners
+	if !r.gatewayObserved(&gateway) {
+		log.V(1).Info("Waiting for the cache to observe the last update of the Gateway", "gateway", gateway.Name)
+		return ctrl.Result{RequeueAfter: expectationRequeueInterval}, nil
+	}
+
+	if r.DryRun {
+		if err := r.planGateway(ctx, &gateway); err != nil {
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{}, nil
+	}
+
+	remaining, err := r.updateGatewayListeners(ctx, &gateway, gateway.Namespace)
+
+	// Record the routes and the outcome of the sync, failed syncs included
+	if r.EnableManagedGateways {
+		if statusErr := r.syncManagedGateway(ctx, &gateway, err); statusErr != nil {
+			log.Error(statusErr, "Failed to update ManagedGateway", "gateway", gateway.Name)
+			if err == nil {
+				return ctrl.Result{}, statusErr
+			}
+		}
+	}
+	if err != nil {
+		log.Error(err, "Failed to update Gateway listeners", "gateway", gateway.Name)
+		return ctrl.Result{}, err
+	}
+
+	// Tell the routes which address to point DNS at once the Gateway has one
+	if err := r.propagateGatewayAddress(ctx, &gateway); err != nil {
+		log.Error(err, "Failed to propagate Gateway address", "gateway", gateway.Name)
+		return ctrl.Result{}, err
+	}
+
+	// The Envoy deployment of the Gateway's IPAM zone
+	if err := r.syncEnvoyProxy(ctx, &gateway); err != nil {
+		log.Error(err, "Failed to sync EnvoyProxy", "gateway", gateway.Name)
+		return ctrl.Result{}, err
+	}
+
+	// Client connection settings and security response headers for every hostname of the Gateway
+	if err := r.syncClientTrafficPolicy(ctx, &gateway); err != nil {
+		log.Error(err, "Failed to sync ClientTrafficPolicy", "gateway", gateway.Name)
+		return ctrl.Result{}, err
+	}
+
+	// A route may have been the last one from its namespace attaching to gateways here
+	if err := r.pruneReferenceGrants(ctx, gateway.Namespace); err != nil {
+		log.Error(err, "Failed to prune ReferenceGrants", "namespace", gateway.Namespace)
+		return ctrl.Result{}, err
+	}
+
+	// Follow a rotation of the Gateway's issuer until its certificates were reissued
+	if err := r.checkIssuerRotation(ctx, &gateway); err != nil {
+		log.Error(err, "Failed to check issuer rotation", "gateway", gateway.Name)
+		return ctrl.Result{}, err
+	}
+
+	// Check the certificates periodically, they expire without anything changing
+	if err := r.checkCertificateExpiry(ctx, &gateway); err != nil {
+		log.Error(err, "Failed to check certificate expiry", "gateway", gateway.Name)
+		return ctrl.Result{}, err
+	}
+	if remaining == 0 || remaining > certificateExpiryCheckInterval {
+		remaining = certificateExpiryCheckInterval
+	}
+	return ctrl.Result{RequeueAfter: remaining}, nil
+}
+
+// gatewaysForRoute maps a route to the Gateways it references
+func gatewaysForRoute(_ context.Context, obj client.Object) []reconcile.Request {
+	route, ok := newRouteInfo(obj)
+	if !ok {
+		return nil
+	}
+	requests := make([]reconcile.Request, 0, len(route.ParentRefs))
+	seen := make(map[client.ObjectKey]bool)
+	for _, parentRef := range route.ParentRefs {
+		name, namespace := parentGateway(route.GetNamespace(), parentRef)
+		key := client.ObjectKey{Name: name, Namespace: namespace}
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		requests = append(requests, reconcile.Request{NamespacedName: key})
+	}
+	return requests
+}
+
+// SetupWithManager sets up the controller with the Manager. Changes to routes of the supported
+// kinds enqueue the Gateways they reference after GatewayUpdateDelay, unless they can't change the
+// listeners, see routeChanged. Managed Gateways are reconciled when their spec or annotations
+// change, so listeners edited or removed by hand are repaired, adopted Gateways get their
+// listeners right away and Gateways waiting for their deletion TTL are picked up, and when their
+// assigned addresses change, so the addresses reach the routes.
+// Certificate secrets, and in certificate mode the operator's Certificates, enqueue the Gateways
+// using them. Only secret metadata is cached. When HTTP-01 listeners are only added during issuance,
+// Challenges coming and going enqueue the managed Gateways.
+func (r *GatewayReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	managed := predicate.NewPredicateFuncs(func(obj client.Object) bool {
+		gateway, ok := obj.(*gatewayv1.Gateway)
+		return ok && (isManagedGateway(gateway) || r.createdBeforeManagedAnnotation(gateway))
+	})
+
+	routeEvents := enqueueGatewaysAfter(r.GatewayUpdateDelay, gatewaysForRoute)
+
+	b := ctrl.NewControllerManagedBy(mgr).
+		For(&gatewayv1.Gateway{}, builder.WithPredicates(
+			managed,
+			predicate.NewPredicateFuncs(func(obj client.Object) bool {
+				return r.gatewayInShard(obj.GetName(), obj.GetNamespace())
+			}),
+			predicate.Or(predicate.GenerationChangedPredicate{}, predicate.AnnotationChangedPredicate{}, addressesChanged),
+		)).
+		Watches(&gatewayv1.HTTPRoute{}, routeEvents, builder.WithPredicates(routeChanged)).
+		Watches(&gatewayv1.GRPCRoute{}, routeEvents, builder.WithPredicates(routeChanged)).
+		Watches(&corev1.Secret{}, handler.EnqueueRequestsFromMapFunc(r.gatewaysForSecret), builder.OnlyMetadata).
+		WatchesRawSource(r.startupSource("Gateway"))
+	if r.EnableTLSRoutes {
+		b = b.Watches(&gatewayv1alpha2.TLSRoute{}, routeEvents, builder.WithPredicates(routeChanged))
+	}
+	if r.EnableTCPRoutes {
+		b = b.Watches(&gatewayv1alpha2.TCPRoute{}, routeEvents, builder.WithPredicates(routeChanged))
+	}
+	if r.HTTP01Listeners == HTTP01ListenersIssuance {
+		challenge := &unstructured.Unstructured{}
+		challenge.SetGroupVersionKind(challengeGVK)
+		b = b.Watches(challenge, handler.EnqueueRequestsFromMapFunc(r.gatewaysForChallenge),
+			builder.WithPredicates(predicate.Funcs{
+				UpdateFunc: func(event.UpdateEvent) bool { return false },
+			}))
+	}
+	if r.CertificateMode == CertificateModeCertificate {
+		certificate := &unstructured.Unstructured{}
+		certificate.SetGroupVersionKind(certificateGVK)
+		b = b.Watches(certificate, handler.EnqueueRequestsFromMapFunc(gatewayForCertificate),
+			builder.WithPredicates(predicate.NewPredicateFuncs(func(obj client.Object) bool {
+				return obj.GetLabels()[managedByLabel] == managedByValue
+			})))
+	}
+
+	return b.
+		Named("gateway").
+		WithOptions(r.controllerOptions()).
+		Complete(r)
+}
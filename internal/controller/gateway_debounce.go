@@ -0,0 +1,45 @@
+package controller
+
+import (
+	"context"
+	"time"
+
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// enqueueGatewaysAfter returns an event handler enqueuing the Gateways a route references, like
+// handler.EnqueueRequestsFromMapFunc, but only after the delay. A Gateway already waiting keeps
+// the time it was first enqueued for, so the route changes of a burst within the delay, e.g. a
+// namespace being migrated, result in a single listener update per Gateway. Without a delay the
+// Gateways are enqueued right away.
+func enqueueGatewaysAfter(delay time.Duration, mapFunc handler.MapFunc) handler.EventHandler {
+	if delay <= 0 {
+		return handler.EnqueueRequestsFromMapFunc(mapFunc)
+	}
+	enqueue := func(ctx context.Context, q workqueue.TypedRateLimitingInterface[reconcile.Request], objs ...client.Object) {
+		for _, obj := range objs {
+			for _, req := range mapFunc(ctx, obj) {
+				q.AddAfter(req, delay)
+			}
+		}
+	}
+	return handler.Funcs{
+		CreateFunc: func(ctx context.Context, e event.CreateEvent, q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+			enqueue(ctx, q, e.Object)
+		},
+		UpdateFunc: func(ctx context.Context, e event.UpdateEvent, q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+			// Both Gateways when the route moved from one to another
+			enqueue(ctx, q, e.ObjectOld, e.ObjectNew)
+		},
+		DeleteFunc: func(ctx context.Context, e event.DeleteEvent, q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+			enqueue(ctx, q, e.Object)
+		},
+		GenericFunc: func(ctx context.Context, e event.GenericEvent, q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+			enqueue(ctx, q, e.Object)
+		},
+	}
+}
@@ -0,0 +1,141 @@
+package controller
+
+import (
+	"context"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// GatewayDeletionPolicy decides what happens to a Gateway when no routes reference it anymore
+type GatewayDeletionPolicy string
+
+const (
+	// GatewayDeletionPolicyDelete deletes the Gateway, releasing its address
+	GatewayDeletionPolicyDelete GatewayDeletionPolicy = "Delete"
+
+	// GatewayDeletionPolicyOrphan leaves the Gateway as it is and stops managing it
+	GatewayDeletionPolicyOrphan GatewayDeletionPolicy = "Orphan"
+
+	// GatewayDeletionPolicyRetainEmpty keeps the Gateway and its address, and keeps managing it
+	// so the listeners are updated as soon as routes reference it again
+	GatewayDeletionPolicyRetainEmpty GatewayDeletionPolicy = "RetainEmpty"
+)
+
+// IsValid reports whether the policy is one of the known deletion policies
+func (p GatewayDeletionPolicy) IsValid() bool {
+	switch p {
+	case GatewayDeletionPolicyDelete, GatewayDeletionPolicyOrphan, GatewayDeletionPolicyRetainEmpty:
+		return true
+	}
+	return false
+}
+
+// deletionPolicyFor returns the deletion policy of a gateway, falling back to the operator default
+// when the gateway has no valid policy annotation
+func (r *GatewayManager) deletionPolicyFor(ctx context.Context, gateway *gatewayv1.Gateway) GatewayDeletionPolicy {
+	value, exists := gateway.Annotations[AnnotationDeletionPolicy]
+	if !exists {
+		return r.config().GatewayDeletionPolicy
+	}
+	if policy := GatewayDeletionPolicy(value); policy.IsValid() {
+		return policy
+	}
+	logf.FromContext(ctx).Info("Ignoring invalid deletion policy on Gateway", "gateway", gateway.Name,
+		"namespace", gateway.Namespace, "policy", value, "default", r.config().GatewayDeletionPolicy)
+	return r.config().GatewayDeletionPolicy
+}
+
+// handleEmptyGateway applies the deletion policy to a gateway no routes reference anymore.
+// With a grace period or deletion TTL the gateway is only deleted once it has been empty for that
+// long, and the returned duration tells when to check again.
+func (r *GatewayManager) handleEmptyGateway(ctx context.Context, gateway *gatewayv1.Gateway) (time.Duration, error) {
+	log := logf.FromContext(ctx)
+
+	if isPaused(gateway) {
+		log.Info("No routes reference this gateway anymore, but its reconciliation is paused", "gateway", gateway.Name, "namespace", gateway.Namespace)
+		return 0, nil
+	}
+
+	switch r.deletionPolicyFor(ctx, gateway) {
+	case GatewayDeletionPolicyOrphan:
+		patch := client.MergeFrom(gateway.DeepCopy())
+		if gateway.Annotations == nil {
+			gateway.Annotations = make(map[string]string)
+		}
+		gateway.Annotations[AnnotationManagedGateway] = "false"
+		delete(gateway.Annotations, emptySinceAnnotationKey)
+		if err := r.Patch(ctx, gateway, patch); err != nil {
+			return 0, err
+		}
+		r.audit(ctx, auditActionOrphan, "Gateway", gateway)
+		log.Info("No routes reference this gateway anymore, orphaning it", "gateway", gateway.Name, "namespace", gateway.Namespace)
+		r.Recorder.Event(gateway, corev1.EventTypeNormal, "GatewayOrphaned", "No routes reference the Gateway anymore, it is no longer managed by the operator")
+		return 0, nil
+
+	case GatewayDeletionPolicyRetainEmpty:
+		log.Info("No routes reference this gateway anymore, retaining it", "gateway", gateway.Name, "namespace", gateway.Namespace)
+		return 0, nil
+	}
+
+	// Keep the gateway, with the listeners it had, until the grace period has passed
+	ttl := r.config().GatewayDeletionGracePeriod
+	if value, exists := gateway.Annotations[AnnotationDeletionTTL]; exists {
+		if parsed, err := time.ParseDuration(value); err != nil {
+			log.Info("Ignoring invalid deletion TTL on Gateway", "gateway", gateway.Name, "namespace", gateway.Namespace, "ttl", value)
+		} else {
+			ttl = parsed
+		}
+	}
+	if ttl > 0 {
+		if remaining, err := r.markGatewayEmpty(ctx, gateway, ttl); err != nil || remaining > 0 {
+			return remaining, err
+		}
+	}
+
+	log.Info("No routes reference this gateway anymore, deleting it", "gateway", gateway.Name, "namespace", gateway.Namespace)
+	if err := r.Delete(ctx, gateway); client.IgnoreNotFound(err) != nil {
+		return 0, err
+	}
+	r.audit(ctx, auditActionDelete, "Gateway", gateway, "listeners", listenerNames(gateway.Spec.Listeners))
+	r.Recorder.Event(gateway, corev1.EventTypeNormal, "GatewayDeleted", "No routes reference the Gateway anymore, deleted it")
+	log.Info("Deleted gateway", "gateway", gateway.Name)
+	return 0, nil
+}
+
+// markGatewayEmpty records when the gateway became empty, and returns how long is left of the TTL
+func (r *GatewayManager) markGatewayEmpty(ctx context.Context, gateway *gatewayv1.Gateway, ttl time.Duration) (time.Duration, error) {
+	if value, exists := gateway.Annotations[emptySinceAnnotationKey]; exists {
+		if emptySince, err := time.Parse(time.RFC3339, value); err == nil {
+			return ttl - time.Since(emptySince), nil
+		}
+	}
+
+	patch := client.MergeFrom(gateway.DeepCopy())
+	if gateway.Annotations == nil {
+		gateway.Annotations = make(map[string]string)
+	}
+	gateway.Annotations[emptySinceAnnotationKey] = time.Now().UTC().Format(time.RFC3339)
+	if err := r.Patch(ctx, gateway, patch); err != nil {
+		return 0, err
+	}
+	r.audit(ctx, auditActionMarkEmpty, "Gateway", gateway, "ttl", ttl.String())
+	logf.FromContext(ctx).Info("No routes reference this gateway anymore, deleting it when the TTL expires",
+		"gateway", gateway.Name, "namespace", gateway.Namespace, "ttl", ttl)
+	r.Recorder.Eventf(gateway, corev1.EventTypeNormal, "GatewayDeletionScheduled",
+		"No routes reference the Gateway anymore, deleting it in %s unless a route references it again", ttl)
+	return ttl, nil
+}
+
+// clearGatewayEmpty removes the empty marker from a gateway routes reference again
+func (r *GatewayManager) clearGatewayEmpty(ctx context.Context, gateway *gatewayv1.Gateway) error {
+	if _, exists := gateway.Annotations[emptySinceAnnotationKey]; !exists {
+		return nil
+	}
+	patch := client.MergeFrom(gateway.DeepCopy())
+	delete(gateway.Annotations, emptySinceAnnotationKey)
+	return r.Patch(ctx, gateway, patch)
+}
@@ -0,0 +1,88 @@
+package controller
+
+import (
+	"context"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/wait"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// GatewayGarbageCollector periodically applies the deletion policy to managed Gateways that no
+// routes reference anymore. Route reconciliation handles Gateways as their last route goes away,
+// the garbage collector catches the ones left behind, e.g. when the operator was down while a
+// route was deleted or a route's finalizer was removed by hand.
+type GatewayGarbageCollector struct {
+	*GatewayManager
+
+	// Interval is the time between garbage collection passes
+	Interval time.Duration
+}
+
+// Start runs garbage collection passes until the context is cancelled
+func (r *GatewayGarbageCollector) Start(ctx context.Context) error {
+	log := logf.FromContext(ctx).WithName("gateway-gc")
+
+	wait.UntilWithContext(ctx, func(ctx context.Context) {
+		if err := r.collectGateways(logf.IntoContext(ctx, log)); err != nil {
+			log.Error(err, "Gateway garbage collection failed")
+		}
+	}, r.Interval)
+	return nil
+}
+
+// NeedLeaderElection makes sure only the leader deletes Gateways
+func (r *GatewayGarbageCollector) NeedLeaderElection() bool {
+	return true
+}
+
+// collectGateways applies the deletion policy to every managed Gateway without routes, and
+// releases the IPAM addresses claimed for Gateways that were never created
+func (r *GatewayGarbageCollector) collectGateways(ctx context.Context) error {
+	log := logf.FromContext(ctx)
+
+	var gateways gatewayv1.GatewayList
+	if err := r.List(ctx, &gateways); err != nil {
+		return err
+	}
+
+	for i := range gateways.Items {
+		gateway := &gateways.Items[i]
+		if !gateway.DeletionTimestamp.IsZero() || !isManagedGateway(gateway) || !r.gatewayInShard(gateway.Name, gateway.Namespace) {
+			continue
+		}
+		// Gateways already waiting for their grace period are handled by the Gateway reconciler
+		if _, exists := gateway.Annotations[emptySinceAnnotationKey]; exists {
+			continue
+		}
+
+		if r.deletionPolicyFor(ctx, gateway) == GatewayDeletionPolicyRetainEmpty {
+			continue
+		}
+		owners, err := r.gatewayOwners(ctx, gateway.Name, gateway.Namespace)
+		if err != nil {
+			return err
+		}
+		if len(owners) > 0 {
+			continue
+		}
+
+		log.Info("Found managed Gateway without routes", "gateway", gateway.Name, "namespace", gateway.Namespace)
+		if r.DryRun {
+			log.Info("Dry run: would apply the deletion policy", "gateway", gateway.Name, "namespace", gateway.Namespace,
+				"policy", r.deletionPolicyFor(ctx, gateway))
+			continue
+		}
+		if _, err := r.handleEmptyGateway(ctx, gateway); client.IgnoreNotFound(err) != nil {
+			return err
+		}
+	}
+
+	// Addresses claimed for Gateways that were never created have no Gateway to be released with
+	if len(r.ZoneIPPools) > 0 {
+		return r.releaseOrphanedClaims(ctx)
+	}
+	return nil
+}
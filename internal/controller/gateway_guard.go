@@ -0,0 +1,116 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// +kubebuilder:webhook:path=/validate-gateway-networking-k8s-io-v1-gateway,mutating=false,failurePolicy=ignore,sideEffects=None,groups=gateway.networking.k8s.io,resources=gateways,verbs=update;delete,versions=v1,name=vgateway-v1.gatewayapi-operator.vitistack.io,admissionReviewVersions=v1
+
+// systemServiceAccountPrefix is the username prefix of the Kubernetes controllers, which delete
+// Gateways together with their namespace or owner
+const systemServiceAccountPrefix = "system:serviceaccount:kube-system:"
+
+// GatewayGuard is a validating admission webhook protecting managed Gateways from manual changes,
+// since a deleted Gateway or a broken listener takes down every hostname it serves. Only the
+// operator may delete a managed Gateway or change and remove the listeners it applied, listeners
+// added by others stay editable. Setting the break-glass annotation on the Gateway lifts the
+// protection for emergencies.
+type GatewayGuard struct {
+	*GatewayManager
+
+	// OperatorUsers are the usernames of the operator, e.g. the username of its service account
+	OperatorUsers []string
+}
+
+// allowed reports whether the user of the admission request may change managed Gateways, and
+// returns the user for the denial otherwise
+func (g *GatewayGuard) allowed(ctx context.Context) (bool, string) {
+	req, err := admission.RequestFromContext(ctx)
+	if err != nil {
+		return true, ""
+	}
+	username := req.UserInfo.Username
+	return slices.Contains(g.OperatorUsers, username) || strings.HasPrefix(username, systemServiceAccountPrefix), username
+}
+
+// ValidateCreate allows every Gateway to be created
+func (g *GatewayGuard) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// ValidateUpdate rejects changes to and removal of the listeners the operator applied on a managed
+// Gateway, unless they are made by the operator or the Gateway has the break-glass annotation
+func (g *GatewayGuard) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	oldGateway, ok := oldObj.(*gatewayv1.Gateway)
+	if !ok {
+		return nil, fmt.Errorf("expected a Gateway, got %T", oldObj)
+	}
+	newGateway, ok := newObj.(*gatewayv1.Gateway)
+	if !ok {
+		return nil, fmt.Errorf("expected a Gateway, got %T", newObj)
+	}
+	if !isManagedGateway(oldGateway) || newGateway.Annotations[AnnotationBreakGlass] == "true" {
+		return nil, nil
+	}
+	allowed, username := g.allowed(ctx)
+	if allowed {
+		return nil, nil
+	}
+
+	current := make(map[gatewayv1.SectionName]gatewayv1.Listener, len(newGateway.Spec.Listeners))
+	for _, listener := range newGateway.Spec.Listeners {
+		current[listener.Name] = listener
+	}
+	applied := appliedListeners(oldGateway)
+	for _, listener := range oldGateway.Spec.Listeners {
+		if !applied[listener.Name] {
+			continue
+		}
+		if updated, exists := current[listener.Name]; exists && equality.Semantic.DeepEqual(listener, updated) {
+			continue
+		}
+		logf.FromContext(ctx).Info("Rejected change to listener of managed Gateway", "gateway", oldGateway.Name,
+			"namespace", oldGateway.Namespace, "listener", listener.Name, "user", username)
+		return nil, fmt.Errorf("listener %s of managed Gateway %s/%s can't be changed by %s, "+
+			"set the %s annotation to %q on the Gateway to override", listener.Name, oldGateway.Namespace, oldGateway.Name,
+			username, AnnotationBreakGlass, "true")
+	}
+	return nil, nil
+}
+
+// ValidateDelete rejects the deletion of a managed Gateway, unless it is deleted by the operator or
+// the Gateway has the break-glass annotation
+func (g *GatewayGuard) ValidateDelete(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	gateway, ok := obj.(*gatewayv1.Gateway)
+	if !ok {
+		return nil, fmt.Errorf("expected a Gateway, got %T", obj)
+	}
+	if !isManagedGateway(gateway) || gateway.Annotations[AnnotationBreakGlass] == "true" {
+		return nil, nil
+	}
+	allowed, username := g.allowed(ctx)
+	if allowed {
+		return nil, nil
+	}
+
+	logf.FromContext(ctx).Info("Rejected deletion of managed Gateway", "gateway", gateway.Name,
+		"namespace", gateway.Namespace, "user", username)
+	return nil, fmt.Errorf("managed Gateway %s/%s can't be deleted by %s, "+
+		"set the %s annotation to %q on the Gateway to override", gateway.Namespace, gateway.Name, username,
+		AnnotationBreakGlass, "true")
+}
+
+// SetupWithManager registers the webhook for Gateways
+func (g *GatewayGuard) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).For(&gatewayv1.Gateway{}).WithValidator(g).Complete()
+}
@@ -0,0 +1,235 @@
+package controller
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+)
+
+// gatewayIndexKey is the field index of routes by the gateways they reference
+const gatewayIndexKey = ".spec.parentRefs.gateway"
+
+// gatewayNamespaceIndexKey is the field index of routes by the namespaces of the gateways they reference
+const gatewayNamespaceIndexKey = ".spec.parentRefs.namespace"
+
+// certificateRefIndexKey is the field index of Gateways by the certificate secrets their listeners
+// reference, with values namespace/name
+const certificateRefIndexKey = ".spec.listeners.tls.certificateRefs"
+
+// gatewayIndexValue returns the gatewayIndexKey value of a gateway
+func gatewayIndexValue(gatewayName, gatewayNamespace string) string {
+	return gatewayNamespace + "/" + gatewayName
+}
+
+// SetupIndexes indexes the routes of the supported kinds by the gateways they reference and by the
+// namespaces of those gateways, so the routes of a gateway, or of the gateways in a namespace, can
+// be looked up without going through every route in the cluster.
+// It must be called before the manager is started.
+func (r *GatewayManager) SetupIndexes(ctx context.Context, mgr ctrl.Manager) error {
+	objects := []client.Object{&gatewayv1.HTTPRoute{}, &gatewayv1.GRPCRoute{}}
+	if r.EnableTLSRoutes {
+		objects = append(objects, &gatewayv1alpha2.TLSRoute{})
+	}
+	if r.EnableTCPRoutes {
+		objects = append(objects, &gatewayv1alpha2.TCPRoute{})
+	}
+
+	for _, obj := range objects {
+		if err := mgr.GetFieldIndexer().IndexField(ctx, obj, gatewayIndexKey, indexRouteGateways); err != nil {
+			return err
+		}
+		if err := mgr.GetFieldIndexer().IndexField(ctx, obj, gatewayNamespaceIndexKey, indexRouteGatewayNamespaces); err != nil {
+			return err
+		}
+	}
+	return mgr.GetFieldIndexer().IndexField(ctx, &gatewayv1.Gateway{}, certificateRefIndexKey, indexGatewayCertificateRefs)
+}
+
+// indexGatewayCertificateRefs returns the certificateRefIndexKey values of the secrets a gateway's
+// listeners reference
+func indexGatewayCertificateRefs(obj client.Object) []string {
+	gateway, ok := obj.(*gatewayv1.Gateway)
+	if !ok {
+		return nil
+	}
+	var secrets []string
+	for _, listener := range gateway.Spec.Listeners {
+		if listener.TLS == nil {
+			continue
+		}
+		for _, ref := range listener.TLS.CertificateRefs {
+			namespace := gateway.Namespace
+			if ref.Namespace != nil {
+				namespace = string(*ref.Namespace)
+			}
+			value := gatewayIndexValue(string(ref.Name), namespace)
+			if !slices.Contains(secrets, value) {
+				secrets = append(secrets, value)
+			}
+		}
+	}
+	return secrets
+}
+
+// indexRouteGateways returns the gatewayIndexKey values of the gateways a route references
+func indexRouteGateways(obj client.Object) []string {
+	route, ok := newRouteInfo(obj)
+	if !ok {
+		return nil
+	}
+	gateways := make([]string, 0, len(route.ParentRefs))
+	for _, parentRef := range route.ParentRefs {
+		value := gatewayIndexValue(parentGateway(route.GetNamespace(), parentRef))
+		if !slices.Contains(gateways, value) {
+			gateways = append(gateways, value)
+		}
+	}
+	return gateways
+}
+
+// indexRouteGatewayNamespaces returns the gatewayNamespaceIndexKey values of the namespaces of the
+// gateways a route references
+func indexRouteGatewayNamespaces(obj client.Object) []string {
+	route, ok := newRouteInfo(obj)
+	if !ok {
+		return nil
+	}
+	namespaces := make([]string, 0, 1)
+	for _, parentRef := range route.ParentRefs {
+		if _, namespace := parentGateway(route.GetNamespace(), parentRef); !slices.Contains(namespaces, namespace) {
+			namespaces = append(namespaces, namespace)
+		}
+	}
+	return namespaces
+}
+
+// listGatewayRoutes returns the routes referencing a gateway, looked up through the gateway index
+func (r *GatewayManager) listGatewayRoutes(ctx context.Context, gatewayName, gatewayNamespace string) ([]routeInfo, error) {
+	return r.listRoutes(ctx, client.MatchingFields{gatewayIndexKey: gatewayIndexValue(gatewayName, gatewayNamespace)})
+}
+
+// gatewayOwners returns the routes owning a gateway: the routes enabled for the operator that
+// reference the gateway and aren't being deleted. A managed gateway without owners is garbage.
+func (r *GatewayManager) gatewayOwners(ctx context.Context, gatewayName, gatewayNamespace string) ([]routeInfo, error) {
+	routes, err := r.listGatewayRoutes(ctx, gatewayName, gatewayNamespace)
+	if err != nil {
+		return nil, err
+	}
+	owners := make([]routeInfo, 0, len(routes))
+	for _, route := range routes {
+		if (route.GetDeletionTimestamp().IsZero() || isPaused(route)) && route.Enabled {
+			owners = append(owners, route)
+		}
+	}
+	return owners, nil
+}
+
+// routesForDeletedGateway returns a map function enqueueing the routes of one kind, given by its
+// list type, that reference a gateway. It is used to recreate gateways deleted under the routes.
+func (r *GatewayManager) routesForDeletedGateway(list client.ObjectList) handler.MapFunc {
+	return func(ctx context.Context, obj client.Object) []reconcile.Request {
+		routes := list.DeepCopyObject().(client.ObjectList)
+		if err := r.List(ctx, routes, client.MatchingFields{gatewayIndexKey: gatewayIndexValue(obj.GetName(), obj.GetNamespace())}); err != nil {
+			logf.FromContext(ctx).Error(err, "Failed to list routes for deleted Gateway", "gateway", obj.GetName(), "namespace", obj.GetNamespace())
+			return nil
+		}
+
+		var requests []reconcile.Request
+		_ = meta.EachListItem(routes, func(item runtime.Object) error {
+			route := item.(client.Object)
+			if route.GetDeletionTimestamp().IsZero() && r.routeEnabled(ctx, route) {
+				requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(route)})
+			}
+			return nil
+		})
+		return requests
+	}
+}
+
+// gatewaysForSecret enqueues the managed Gateways whose listeners reference a certificate secret,
+// so a secret being issued, rotated or deleted lets the Gateway's listeners converge
+func (r *GatewayManager) gatewaysForSecret(ctx context.Context, obj client.Object) []reconcile.Request {
+	var gateways gatewayv1.GatewayList
+	if err := r.List(ctx, &gateways, client.MatchingFields{certificateRefIndexKey: gatewayIndexValue(obj.GetName(), obj.GetNamespace())}); err != nil {
+		logf.FromContext(ctx).Error(err, "Failed to list Gateways for certificate secret", "secret", obj.GetName(), "namespace", obj.GetNamespace())
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for i := range gateways.Items {
+		if isManagedGateway(&gateways.Items[i]) {
+			requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&gateways.Items[i])})
+		}
+	}
+	return requests
+}
+
+// gatewayForCertificate enqueues the Gateway a Certificate created by the operator is for, so
+// listeners waiting for the certificate are added as soon as it is Ready
+func gatewayForCertificate(_ context.Context, obj client.Object) []reconcile.Request {
+	value, exists := obj.GetAnnotations()[certificateGatewayAnnotationKey]
+	if !exists {
+		return nil
+	}
+	namespace, name, ok := strings.Cut(value, "/")
+	if !ok {
+		return nil
+	}
+	return []reconcile.Request{{NamespacedName: client.ObjectKey{Name: name, Namespace: namespace}}}
+}
+
+// gatewayDeleted only lets Gateway deletions through
+var gatewayDeleted = predicate.Funcs{
+	CreateFunc:  func(event.CreateEvent) bool { return false },
+	UpdateFunc:  func(event.UpdateEvent) bool { return false },
+	DeleteFunc:  func(event.DeleteEvent) bool { return true },
+	GenericFunc: func(event.GenericEvent) bool { return false },
+}
+
+// operatorRouteAnnotations are the route annotations written by the operator. Changes to them
+// alone don't change what the operator does with the route.
+var operatorRouteAnnotations = []string{
+	reconcileAnnotationKey,
+	legacyPreviousGatewayAnnotationKey,
+	rejectedAnnotationKey,
+	statusAnnotationKey,
+	gatewayAddressAnnotationKey,
+}
+
+// routeChanged lets through route updates that can change the route's listeners or Gateway: changes
+// to its spec, labels, finalizers or deletion, and to annotations other than the ones the operator
+// writes. Status updates by the Gateway implementation and the operator's own annotations are
+// skipped. Resyncs, which don't change the route, are let through so drift is still repaired.
+var routeChanged = predicate.Funcs{
+	UpdateFunc: func(e event.UpdateEvent) bool {
+		oldRoute, newRoute := e.ObjectOld, e.ObjectNew
+		if oldRoute == nil || newRoute == nil || oldRoute.GetResourceVersion() == newRoute.GetResourceVersion() {
+			return true
+		}
+		userAnnotations := func(route client.Object) map[string]string {
+			annotations := maps.Clone(route.GetAnnotations())
+			for _, key := range operatorRouteAnnotations {
+				delete(annotations, key)
+			}
+			return annotations
+		}
+		return oldRoute.GetGeneration() != newRoute.GetGeneration() ||
+			!maps.Equal(oldRoute.GetLabels(), newRoute.GetLabels()) ||
+			!slices.Equal(oldRoute.GetFinalizers(), newRoute.GetFinalizers()) ||
+			!oldRoute.GetDeletionTimestamp().Equal(newRoute.GetDeletionTimestamp()) ||
+			!maps.Equal(userAnnotations(oldRoute), userAnnotations(newRoute))
+	},
+}
@@ -0,0 +1,79 @@
+package controller
+
+import (
+	"strconv"
+	"sync"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// lockGateway serializes the reconciles changing a Gateway, so with several concurrent reconciles
+// routes referencing the same Gateway don't race each other or the Gateway reconciler, e.g. both
+// creating the Gateway or choosing a shard for it. Reconciles of different Gateways still run in
+// parallel. The returned function releases the lock. Locks are kept for the lifetime of the
+// operator, one per Gateway.
+func (r *GatewayManager) lockGateway(gatewayName, gatewayNamespace string) func() {
+	key := client.ObjectKey{Name: gatewayName, Namespace: gatewayNamespace}
+	lock, _ := r.gatewayLocks.LoadOrStore(key, &sync.Mutex{})
+	mutex := lock.(*sync.Mutex)
+	mutex.Lock()
+	return mutex.Unlock
+}
+
+// Expectations are dropped after expectationTimeout, in case the cache never observes the write,
+// e.g. because the Gateway was deleted right after. Reconciles waiting for the cache to catch up
+// check again every expectationRequeueInterval.
+const (
+	expectationTimeout         = 30 * time.Second
+	expectationRequeueInterval = time.Second
+)
+
+// gatewayExpectation is the resource version of a Gateway the operator wrote
+type gatewayExpectation struct {
+	resourceVersion string
+	expires         time.Time
+}
+
+// expectGateway records that the operator wrote the gateway, so its listeners aren't computed
+// again from a cache that hasn't observed the write yet, see gatewayObserved
+func (r *GatewayManager) expectGateway(gateway *gatewayv1.Gateway) {
+	if gateway.ResourceVersion == "" {
+		return
+	}
+	r.gatewayExpectations.Store(client.ObjectKeyFromObject(gateway), gatewayExpectation{
+		resourceVersion: gateway.ResourceVersion,
+		expires:         time.Now().Add(expectationTimeout),
+	})
+}
+
+// gatewayExpected reports whether the operator wrote the Gateway recently and the cache hasn't
+// observed it yet, e.g. a Gateway just created that is still missing from the cache
+func (r *GatewayManager) gatewayExpected(key client.ObjectKey) bool {
+	value, exists := r.gatewayExpectations.Load(key)
+	return exists && time.Now().Before(value.(gatewayExpectation).expires)
+}
+
+// gatewayObserved reports whether the cached gateway includes the operator's last write to it, so
+// two listener computations never work from different versions of the Gateway and its routes.
+// Resource versions are compared as numbers when they are, otherwise only the written version
+// satisfies the expectation until it expires.
+func (r *GatewayManager) gatewayObserved(gateway *gatewayv1.Gateway) bool {
+	key := client.ObjectKeyFromObject(gateway)
+	value, exists := r.gatewayExpectations.Load(key)
+	if !exists {
+		return true
+	}
+	expectation := value.(gatewayExpectation)
+	observed := gateway.ResourceVersion == expectation.resourceVersion || time.Now().After(expectation.expires)
+	if cached, err := strconv.ParseUint(gateway.ResourceVersion, 10, 64); err == nil {
+		if expected, err := strconv.ParseUint(expectation.resourceVersion, 10, 64); err == nil && cached >= expected {
+			observed = true
+		}
+	}
+	if observed {
+		r.gatewayExpectations.CompareAndDelete(key, expectation)
+	}
+	return observed
+}
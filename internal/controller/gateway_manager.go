@@ -2,23 +2,385 @@ package controller
 
 import (
 	"context"
+	"fmt"
+	"net/netip"
+	"sync"
+	"sync/atomic"
+	"time"
 
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
 )
 
-// ensureGateway ensures a Gateway exists with proper listeners.
-// Creates the gateway if it doesn't exist, otherwise updates its listeners.
-func (r *HTTPRouteReconciler) ensureGateway(
+// GatewayManager holds the Gateway and listener logic shared by the route reconcilers,
+// so that every supported route kind contributes listeners to the same Gateways.
+type GatewayManager struct {
+	client.Client
+	Scheme *runtime.Scheme
+
+	// EnableTLSRoutes includes TLSRoutes when collecting listeners. TLSRoute is part of
+	// the experimental Gateway API channel, so its CRD is not always installed.
+	EnableTLSRoutes bool
+
+	// EnableTCPRoutes includes TCPRoutes when collecting listeners. TCPRoute is part of
+	// the experimental Gateway API channel, so its CRD is not always installed.
+	EnableTCPRoutes bool
+
+	// EnableBackendTLSPolicies creates BackendTLSPolicies for routes asking for TLS to their
+	// backends. BackendTLSPolicy is part of the experimental Gateway API channel as well.
+	EnableBackendTLSPolicies bool
+
+	// EnableSecurityPolicies creates Envoy Gateway SecurityPolicies for routes asking for OIDC
+	// authentication or restricting their client addresses. Requires Envoy Gateway's CRDs.
+	EnableSecurityPolicies bool
+
+	// EnableBackendTrafficPolicies creates Envoy Gateway BackendTrafficPolicies for routes asking
+	// for a rate limit. Requires Envoy Gateway's CRDs.
+	EnableBackendTrafficPolicies bool
+
+	// EnableSecurityHeaders adds HSTS and the other security response headers of the baseline to
+	// every response of managed Gateways, through Envoy Gateway ClientTrafficPolicies
+	EnableSecurityHeaders bool
+
+	// HSTSMaxAge is the max-age of the Strict-Transport-Security header
+	HSTSMaxAge time.Duration
+
+	// ClientIdleTimeout, ClientProxyProtocol and ClientTCPKeepaliveIdleTime are the default client
+	// connection settings of managed Gateways, applied through Envoy Gateway ClientTrafficPolicies.
+	// Routes override them with annotations. Zero values leave Envoy Gateway's defaults
+	ClientIdleTimeout          time.Duration
+	ClientProxyProtocol        bool
+	ClientTCPKeepaliveIdleTime time.Duration
+
+	// DNSPreflight resolves the hostnames of listeners before they are added to a Gateway, warning
+	// when they don't point at the Gateway's addresses
+	DNSPreflight bool
+
+	// AccessLogZones are the IPAM zones whose Gateways get access logs, unless the operator ConfigMap
+	// sets them. AccessLogFormat and AccessLogOTLPEndpoint select the format and the sink
+	AccessLogZones        string
+	AccessLogFormat       AccessLogFormat
+	AccessLogOTLPEndpoint string
+
+	// EnableHTTPSRedirect adds an HTTP listener for every HTTPS hostname, with a companion
+	// HTTPRoute redirecting plain HTTP requests to HTTPS
+	EnableHTTPSRedirect bool
+
+	// EnableManagedGateways records the routes contributing to every managed Gateway and the outcome
+	// of its last sync in a ManagedGateway of the same name
+	EnableManagedGateways bool
+
+	// EnableHostnameClaims claims the hostnames of routes for their namespace with HostnameClaims,
+	// handling routes whose hostnames are claimed by another namespace by HostnameCollisionPolicy
+	EnableHostnameClaims bool
+
+	// HostnameCollisionPolicy decides what happens to routes asking for a hostname claimed by
+	// another namespace
+	HostnameCollisionPolicy HostnameCollisionPolicy
+
+	// ListenerNaming selects how listener section names are derived from hostnames
+	ListenerNaming ListenerNaming
+
+	// HTTP01Listeners selects when Gateways get port 80 listeners for cert-manager's ACME HTTP-01
+	// solver routes, for hostnames whose issuer uses HTTP-01
+	HTTP01Listeners HTTP01ListenerMode
+
+	// TCPPortRangeStart and TCPPortRangeEnd bound the listener ports allocated to TCPRoutes
+	TCPPortRangeStart gatewayv1.PortNumber
+	TCPPortRangeEnd   gatewayv1.PortNumber
+
+	// ListenerPortRangeStart and ListenerPortRangeEnd bound the listener ports routes may ask for
+	// with the listener-port annotation, besides the standard ports
+	ListenerPortRangeStart gatewayv1.PortNumber
+	ListenerPortRangeEnd   gatewayv1.PortNumber
+
+	// CreateReferenceGrants creates missing ReferenceGrants for routes attaching to
+	// gateways in other namespaces instead of only warning about them
+	CreateReferenceGrants bool
+
+	// RouteSelector and EnabledNamespaceSelector enroll routes without the enabled annotation: routes
+	// whose labels match RouteSelector, and all routes in namespaces matching EnabledNamespaceSelector
+	RouteSelector            labels.Selector
+	EnabledNamespaceSelector labels.Selector
+
+	// WatchNamespaces, ExcludeNamespaces and NamespaceSelector restrict the operator to the routes
+	// of some namespaces. Routes in other namespaces are ignored and contribute no listeners
+	WatchNamespaces   []string
+	ExcludeNamespaces []string
+	NamespaceSelector labels.Selector
+
+	// AllowedZones are the IPAM zones the routes of namespaces without the allowed-zones annotation
+	// may use. Empty allows every zone
+	AllowedZones []string
+
+	// NamespaceListenerQuota is how many hostnames the routes of a namespace may add to Gateways in
+	// other namespaces, unless the namespace has its own quota. Zero is unlimited
+	NamespaceListenerQuota int
+
+	// AllowedDomains are the domains route hostnames must be in, e.g. helsenett.no for
+	// app.helsenett.no. Routes asking for other hostnames are rejected. Empty allows every hostname
+	AllowedDomains []string
+
+	// SharedGatewayNamespace is the central namespace for shared Gateways. Listeners on Gateways
+	// in this namespace only admit routes from the namespaces requesting them, and reference
+	// certificates in those namespaces through ReferenceGrants managed by the operator
+	SharedGatewayNamespace string
+
+	// ZoneWildcardDomains maps IPAM zones to a domain, e.g. apps.example.com. Hostnames directly under
+	// the domain of their route's zone share a single *.domain listener and certificate
+	ZoneWildcardDomains map[string]string
+
+	// TLSOptions are the default TLS options of listeners, e.g. the minimum TLS version.
+	// Routes override them with the tls-options annotation
+	TLSOptions map[string]string
+
+	// TLSSecretTemplate names the certificate secrets of listeners, {hostname} is replaced by the
+	// listener hostname. Defaults to {hostname}-tls
+	TLSSecretTemplate string
+
+	// TLSSecretNamespaces are the namespaces routes may put their certificate secrets in with the
+	// tls-secret-namespace annotation. Namespace annotations set by platform admins are not restricted
+	TLSSecretNamespaces []string
+
+	// NamespaceGatewayTemplate enables gateway-per-namespace mode when set. Every namespace gets
+	// one Gateway named from the template, where {namespace} is replaced by the namespace name
+	NamespaceGatewayTemplate string
+
+	// EnableGatewaySharding moves routes that don't fit on their gateway to gateway shards
+	// named {gateway}-2, {gateway}-3 and so on, instead of exceeding MaxListenersPerGateway
+	EnableGatewaySharding  bool
+	MaxListenersPerGateway int
+
+	// EnableListenerSets puts the listeners into experimental ListenerSets attached to the Gateway
+	// instead of the Gateway's own listeners, which lifts the limit of 64 listeners per Gateway
+	EnableListenerSets bool
+
+	// GatewayDeletionPolicy decides what happens to a Gateway when no routes reference it anymore,
+	// unless the Gateway overrides it with AnnotationDeletionPolicy
+	GatewayDeletionPolicy GatewayDeletionPolicy
+
+	// GatewayDeletionGracePeriod is how long an empty Gateway is kept before it is deleted, so a route
+	// that is deleted and recreated doesn't tear down the Gateway and its address. Gateways can
+	// override it with AnnotationDeletionTTL
+	GatewayDeletionGracePeriod time.Duration
+
+	// DryRun makes the operator only plan its changes, reporting them in logs and events
+	// without writing anything. Routes can ask for this on their own with AnnotationDryRun
+	DryRun bool
+
+	// AuditLog makes the operator record every change it makes to Gateways with the audit logger
+	AuditLog bool
+
+	// LogFormat, LogLevel and ControllerLogLevels are the log configuration given by the flags, and
+	// Logging is the logger they are applied to when the operator ConfigMap changes them
+	LogFormat           LogFormat
+	LogLevel            string
+	ControllerLogLevels string
+	Logging             *DynamicLogger
+
+	// DefaultGatewayClass is the GatewayClass of created Gateways, unless the route asks for
+	// another class or ZoneGatewayClasses maps the route's IPAM zone to one
+	DefaultGatewayClass string
+	ZoneGatewayClasses  map[string]string
+
+	// PropagateLabels and PropagateAnnotations are the label and annotation keys copied from the
+	// routes owning a Gateway to the Gateway and its infrastructure
+	PropagateLabels      []string
+	PropagateAnnotations []string
+
+	// InfrastructureLabels are the default labels set on the infrastructure of managed Gateways.
+	// Routes override them with the infrastructure-labels annotation
+	InfrastructureLabels map[string]string
+
+	// ZoneAddressRanges are the address ranges of each IPAM zone. Static IP addresses requested by
+	// routes must be in the ranges of their zone, zones without ranges are not checked
+	ZoneAddressRanges map[string][]netip.Prefix
+
+	// ZoneIPPools are the IPAM pools the addresses of the Gateways of each IPAM zone are claimed
+	// from with IPAddressClaims, unless a route requests a static address
+	ZoneIPPools map[string]corev1.TypedLocalObjectReference
+
+	// EnableIssuerRotation switches a Gateway to another issuer once all its routes ask for it,
+	// instead of rejecting the routes with an issuer mismatch
+	EnableIssuerRotation bool
+
+	// GatewayConflictPolicy decides what happens to routes asking for another issuer or IPAM zone
+	// than their Gateway has, when it can't rotate or migrate to it
+	GatewayConflictPolicy GatewayConflictPolicy
+
+	// EnableZoneMigration moves a Gateway to another IPAM zone once all its routes ask for it,
+	// instead of rejecting the routes with a zone mismatch
+	EnableZoneMigration bool
+
+	// CertificateMode selects whether cert-manager creates the certificates from the Gateway's
+	// issuer annotation, or the operator creates Certificates with the settings below
+	CertificateMode         CertificateMode
+	CertificateKeyAlgorithm string
+	CertificateDuration     time.Duration
+	CertificateRenewBefore  time.Duration
+
+	// CertificateStrategy selects whether every listener hostname gets its own certificate secret,
+	// or all listeners of a Gateway share one
+	CertificateStrategy CertificateStrategy
+
+	// CertificateExpiryWarning is how long before expiry the certificate of a listener is reported
+	// with a warning event
+	CertificateExpiryWarning time.Duration
+
+	// GatewayProgrammedTimeout is how long a route waits for its Gateway to be programmed before the
+	// route is reported as failed. Zero doesn't wait for Gateways to be programmed
+	GatewayProgrammedTimeout time.Duration
+
+	// GatewayUpdateDelay is how long route changes are collected before the listeners of their
+	// Gateways are computed again, so bursts of changes update each Gateway once. Zero updates the
+	// Gateways right away
+	GatewayUpdateDelay time.Duration
+
+	// MaxConcurrentReconciles is the number of routes, and of Gateways, each controller reconciles
+	// in parallel. Reconciles changing the same Gateway are serialized, see lockGateway
+	MaxConcurrentReconciles int
+
+	// OperatorShards is the number of operator instances sharing the Gateways, and OperatorShard the
+	// index of this instance from 0, see gatewayInShard. One instance handles all Gateways
+	OperatorShards int
+	OperatorShard  int
+
+	// ShutdownDrainTimeout is how long reconciles in flight may keep running when the operator
+	// shuts down, see drainContext
+	ShutdownDrainTimeout time.Duration
+
+	// RequeueBaseDelay and RequeueMaxDelay bound the backoff of failed reconciles, see controllerOptions
+	RequeueBaseDelay time.Duration
+	RequeueMaxDelay  time.Duration
+
+	// loadedConfig is the configuration loaded from the operator ConfigMap, see config
+	loadedConfig atomic.Pointer[OperatorConfig]
+
+	// startupEvents feed the objects of the startup pass to the controllers by kind, startupPending
+	// holds the ones not reconciled yet, see StartupPass, and converged whether the startup pass
+	// completed, see ConvergedCheck
+	startupEvents  map[string]chan event.GenericEvent
+	startupPending sync.Map
+	startupTotal   atomic.Int64
+	startupStarted atomic.Bool
+	converged      atomic.Bool
+
+	// gatewayLocks holds a mutex per Gateway, see lockGateway, and gatewayExpectations the
+	// Gateway versions written by the operator the cache hasn't observed yet, see gatewayObserved
+	gatewayLocks        sync.Map
+	gatewayExpectations sync.Map
+
+	// issuerRotationProgress holds the progress last reported for each Gateway rotating its issuer,
+	// see checkIssuerRotation
+	issuerRotationProgress sync.Map
+
+	// APIReader reads the certificate secrets of listeners without caching every Secret in the cluster
+	APIReader client.Reader
+
+	// DefaultIPFamily is the IP family of Gateways created for routes without the ip-family annotation
+	DefaultIPFamily IPFamily
+
+	// Recorder emits events on the routes the operator reconciles
+	Recorder record.EventRecorder
+}
+
+// isSharedGateway reports whether gateways in the namespace are shared gateways
+func (r *GatewayManager) isSharedGateway(gatewayNamespace string) bool {
+	return r.SharedGatewayNamespace != "" && gatewayNamespace == r.SharedGatewayNamespace
+}
+
+// isManagedGateway reports whether the operator may manage the gateway's listeners: Gateways
+// created by the operator, or adopted, carry the managed annotation. Orphaned Gateways have the
+// annotation set to "false".
+func isManagedGateway(gateway *gatewayv1.Gateway) bool {
+	return gateway.Annotations[AnnotationManagedGateway] == "true"
+}
+
+// createdBeforeManagedAnnotation reports whether the operator created the gateway before Gateways
+// got the managed annotation: its creation is recorded by the operator's field manager of the time,
+// or it is the namespace's Gateway in gateway-per-namespace mode. Gateways the operator only
+// applied listeners to may have been created by others, so they are not recognized.
+func (r *GatewayManager) createdBeforeManagedAnnotation(gateway *gatewayv1.Gateway) bool {
+	if _, exists := gateway.Annotations[AnnotationManagedGateway]; exists {
+		return false
+	}
+	if r.NamespaceGatewayTemplate != "" && gateway.Name == r.namespaceGatewayName(gateway.Namespace) {
+		return true
+	}
+	for _, entry := range gateway.ManagedFields {
+		if entry.Manager == legacyCreateFieldManager && entry.Operation == metav1.ManagedFieldsOperationUpdate &&
+			entry.Subresource == "" && entry.Time != nil && entry.Time.Equal(&gateway.CreationTimestamp) {
+			return true
+		}
+	}
+	return false
+}
+
+// migrateManagedAnnotation sets the managed annotation on a Gateway the operator created before
+// Gateways got it
+func (r *GatewayManager) migrateManagedAnnotation(ctx context.Context, gateway *gatewayv1.Gateway) error {
+	patch := client.MergeFrom(gateway.DeepCopy())
+	if gateway.Annotations == nil {
+		gateway.Annotations = make(map[string]string)
+	}
+	gateway.Annotations[AnnotationManagedGateway] = "true"
+	if err := r.Patch(ctx, gateway, patch); err != nil {
+		return err
+	}
+	logf.FromContext(ctx).Info("Marked Gateway created by an older operator version as managed",
+		"gateway", gateway.Name, "namespace", gateway.Namespace)
+	return nil
+}
+
+// isPaused reports whether reconciliation of a route or gateway is suspended
+func isPaused(obj client.Object) bool {
+	return obj.GetAnnotations()[AnnotationPaused] == "true"
+}
+
+// ensureGateway ensures a Gateway exists for the route with the expected zone, issuer and class.
+// Creates the gateway with its listeners if it doesn't exist, otherwise the Gateway reconciler
+// updates its listeners.
+func (r *GatewayManager) ensureGateway(
 	ctx context.Context,
+	route client.Object,
 	gatewayName, gatewayNamespace string,
-	ipamZone string,
-	clusterIssuer string,
+	settings gatewaySettings,
 ) error {
 	log := logf.FromContext(ctx)
+	ipamZone, issuer := settings.IPAMZone, settings.Issuer
+
+	// A static address must belong to the zone the gateway is placed in
+	if err := r.validateAddress(settings); err != nil {
+		log.Error(err, "Invalid Gateway address", "address", settings.Address, "ipamZone", ipamZone)
+		return err
+	}
+	if err := r.validateIPFamily(settings); err != nil {
+		log.Error(err, "Invalid Gateway IP family", "ipFamily", settings.IPFamily, "ipamZone", ipamZone)
+		return err
+	}
+
+	// Listeners of an issuer that doesn't exist or isn't ready never get certificates. cert-manager
+	// requests certificates in the gateway's namespace, the operator in the namespace of the secrets.
+	// Routes served over plain HTTP need no certificates.
+	plainHTTP := servesPlainHTTP(route)
+	issuerNamespace := gatewayNamespace
+	if r.CertificateMode == CertificateModeCertificate {
+		issuerNamespace = r.routeSecretNamespace(ctx, route, gatewayNamespace)
+	}
+	if !plainHTTP {
+		if err := r.ensureIssuerReady(ctx, route, issuer, issuerNamespace); err != nil {
+			return err
+		}
+	}
 
 	// Check if Gateway exists
 	gateway := &gatewayv1.Gateway{}
@@ -29,78 +391,259 @@ func (r *HTTPRouteReconciler) ensureGateway(
 
 	if err != nil {
 		if errors.IsNotFound(err) {
-			// Gateway doesn't exist, create it
+			// A Gateway the operator just created may not be in the cache yet, the Gateway
+			// reconciler adds the route's listeners once it is
+			if r.gatewayExpected(client.ObjectKey{Name: gatewayName, Namespace: gatewayNamespace}) {
+				log.V(1).Info("Gateway was just created, waiting for the cache", "gateway", gatewayName, "namespace", gatewayNamespace)
+				return nil
+			}
+			// Gateway doesn't exist, create it once its class is ready for it
+			if err := r.ensureGatewayClassAccepted(ctx, route, settings.GatewayClass); err != nil {
+				return err
+			}
 			log.Info("Creating new Gateway", "gateway", gatewayName, "namespace", gatewayNamespace)
-			return r.createGateway(ctx, gatewayName, gatewayNamespace, ipamZone, clusterIssuer)
+			return r.createGateway(ctx, route, gatewayName, gatewayNamespace, settings)
 		}
 		log.Error(err, "Failed to get Gateway", "gateway", gatewayName)
 		return err
 	}
 
-	// Gateway exists, validate cluster issuer matches
-	existingIssuer := gateway.Annotations[clusterIssuerAnnotation]
-	if existingIssuer != clusterIssuer {
-		err := errors.NewBadRequest("HTTPRoute cluster issuer mismatch: Gateway has issuer '" + existingIssuer + "' but HTTPRoute requires '" + clusterIssuer + "'")
-		log.Error(err, "Cluster issuer mismatch", "gateway", gatewayName, "gatewayIssuer", existingIssuer, "routeIssuer", clusterIssuer)
-		return err
+	// Gateways created outside the operator are left alone until they are adopted
+	if !isManagedGateway(gateway) {
+		log.Info("Gateway is not managed by the operator, not updating listeners", "gateway", gatewayName, "namespace", gatewayNamespace)
+		r.Recorder.Eventf(route, corev1.EventTypeWarning, "GatewayUnmanaged",
+			"Gateway %s/%s was not created by the operator, annotate it with %s=true to let the operator manage its listeners",
+			gatewayNamespace, gatewayName, AnnotationManagedGateway)
+		return nil
 	}
 
-	// Gateway exists, validate IPAM zone matches if set
+	// Gateway exists, validate issuer matches. Certificates created by the operator are
+	// issued per hostname, so routes with different issuers can share a gateway. Gateways rotate
+	// to the issuer all their routes ask for, other mismatches are resolved by the conflict policy
+	existingIssuer := gatewayIssuer(gateway)
+	if r.CertificateMode != CertificateModeCertificate && !plainHTTP && existingIssuer != issuer && r.EnableIssuerRotation {
+		agreed, err := r.issuerRotationAgreed(ctx, gateway, issuer)
+		if err != nil {
+			return err
+		}
+		if agreed {
+			return r.rotateGatewayIssuer(ctx, route, gateway, issuer)
+		}
+	}
+	if r.CertificateMode != CertificateModeCertificate && !plainHTTP && existingIssuer != issuer {
+		policy, err := r.resolveGatewayConflict(ctx, route, gateway, "issuer", existingIssuer.String(), issuer.String(), issuer.Name)
+		if err != nil {
+			return err
+		}
+		switch policy {
+		case GatewayConflictPolicySplit:
+			return nil
+		case GatewayConflictPolicyReject:
+			err := errors.NewBadRequest("Route issuer mismatch: Gateway has issuer '" + existingIssuer.String() + "' but route requires '" + issuer.String() + "'")
+			log.Error(err, "Issuer mismatch", "gateway", gatewayName, "gatewayIssuer", existingIssuer.String(), "routeIssuer", issuer.String())
+			gatewayMismatches.WithLabelValues("issuer").Inc()
+			r.Recorder.Eventf(route, corev1.EventTypeWarning, "IssuerMismatch",
+				"Gateway %s/%s has issuer %s but the route requires %s", gatewayNamespace, gatewayName, existingIssuer.String(), issuer.String())
+			return err
+		}
+	}
+
+	// Gateway exists, validate IPAM zone matches if set. Gateways move to the zone all their routes
+	// ask for, other mismatches are resolved by the conflict policy
 	if gateway.Spec.Infrastructure != nil && gateway.Spec.Infrastructure.Annotations != nil {
 		if existingZone, exists := gateway.Spec.Infrastructure.Annotations["ipam.vitistack.io/zone"]; exists {
+			if string(existingZone) != ipamZone && r.EnableZoneMigration {
+				agreed, err := r.zoneMigrationAgreed(ctx, gateway, ipamZone)
+				if err != nil {
+					return err
+				}
+				if agreed {
+					return r.migrateGatewayZone(ctx, route, gateway, ipamZone)
+				}
+			}
 			if string(existingZone) != ipamZone {
-				err := errors.NewBadRequest("HTTPRoute IPAM zone mismatch: Gateway has zone '" + string(existingZone) + "' but HTTPRoute requires '" + ipamZone + "'")
-				log.Error(err, "IPAM zone mismatch", "gateway", gatewayName, "gatewayZone", string(existingZone), "routeZone", ipamZone)
-				return err
+				policy, err := r.resolveGatewayConflict(ctx, route, gateway, "IPAM zone", string(existingZone), ipamZone, ipamZone)
+				if err != nil {
+					return err
+				}
+				switch policy {
+				case GatewayConflictPolicySplit:
+					return nil
+				case GatewayConflictPolicyReject:
+					err := errors.NewBadRequest("Route IPAM zone mismatch: Gateway has zone '" + string(existingZone) + "' but route requires '" + ipamZone + "'")
+					log.Error(err, "IPAM zone mismatch", "gateway", gatewayName, "gatewayZone", string(existingZone), "routeZone", ipamZone)
+					gatewayMismatches.WithLabelValues("zone").Inc()
+					r.Recorder.Eventf(route, corev1.EventTypeWarning, "ZoneMismatch",
+						"Gateway %s/%s has IPAM zone %s but the route requires %s", gatewayNamespace, gatewayName, existingZone, ipamZone)
+					return err
+				}
 			}
 		}
 	}
 
-	// Gateway exists and configuration matches, update listeners
-	log.Info("Gateway exists, updating listeners", "gateway", gatewayName, "namespace", gatewayNamespace)
-	return r.updateGatewayListeners(ctx, gateway, gatewayNamespace)
+	// Gateway exists, validate the GatewayClass matches if the route asks for one
+	if _, exists := route.GetAnnotations()[AnnotationGatewayClass]; exists && gateway.Spec.GatewayClassName != settings.GatewayClass {
+		err := errors.NewBadRequest("Route GatewayClass mismatch: Gateway has class '" + string(gateway.Spec.GatewayClassName) + "' but route requires '" + string(settings.GatewayClass) + "'")
+		log.Error(err, "GatewayClass mismatch", "gateway", gatewayName, "gatewayClass", gateway.Spec.GatewayClassName, "routeClass", settings.GatewayClass)
+		gatewayMismatches.WithLabelValues("gatewayClass").Inc()
+		r.Recorder.Eventf(route, corev1.EventTypeWarning, "GatewayClassMismatch",
+			"Gateway %s/%s has GatewayClass %s but the route requires %s", gatewayNamespace, gatewayName, gateway.Spec.GatewayClassName, settings.GatewayClass)
+		return err
+	}
+
+	// Gateway exists, validate the IP family matches if the route asks for one
+	if _, exists := route.GetAnnotations()[AnnotationIPFamily]; exists {
+		if existingFamily, set := gatewayIPFamily(gateway); set && existingFamily != settings.IPFamily {
+			err := errors.NewBadRequest("Route IP family mismatch: Gateway has IP family '" + string(existingFamily) + "' but route requires '" + string(settings.IPFamily) + "'")
+			log.Error(err, "IP family mismatch", "gateway", gatewayName, "gatewayIPFamily", existingFamily, "routeIPFamily", settings.IPFamily)
+			gatewayMismatches.WithLabelValues("ipFamily").Inc()
+			r.Recorder.Eventf(route, corev1.EventTypeWarning, "IPFamilyMismatch",
+				"Gateway %s/%s has IP family %s but the route requires %s", gatewayNamespace, gatewayName, existingFamily, settings.IPFamily)
+			return err
+		}
+	}
+
+	// Gateway exists, validate the static address matches if the route asks for one
+	if settings.Address != "" && len(gateway.Spec.Addresses) > 0 && !hasAddress(gateway, settings.Address) {
+		err := errors.NewBadRequest("Route address mismatch: Gateway does not have address '" + settings.Address + "' the route requires")
+		log.Error(err, "Address mismatch", "gateway", gatewayName, "gatewayAddresses", gateway.Spec.Addresses, "routeAddress", settings.Address)
+		gatewayMismatches.WithLabelValues("address").Inc()
+		r.Recorder.Eventf(route, corev1.EventTypeWarning, "AddressMismatch",
+			"Gateway %s/%s does not have the address %s the route requires", gatewayNamespace, gatewayName, settings.Address)
+		return err
+	}
+
+	// Gateway exists and configuration matches, the Gateway reconciler updates its listeners
+	log.V(1).Info("Gateway exists, listeners are updated by the Gateway reconciler", "gateway", gatewayName, "namespace", gatewayNamespace)
+	return nil
 }
 
-// createGateway creates a new Gateway resource with initial configuration
-func (r *HTTPRouteReconciler) createGateway(
+// createGateway creates a new Gateway resource with initial configuration. A Gateway that would get
+// more listeners than it can hold is not created, with a warning event on the route.
+func (r *GatewayManager) createGateway(
 	ctx context.Context,
+	route client.Object,
 	gatewayName, gatewayNamespace string,
-	ipamZone string,
-	clusterIssuer string,
+	settings gatewaySettings,
 ) error {
 	log := logf.FromContext(ctx)
 
-	// Collect all listeners from HTTPRoutes that reference this gateway
-	listeners, err := r.collectListenersForGateway(ctx, gatewayName, gatewayNamespace)
+	// Collect all listeners from routes that reference this gateway
+	listeners, _, err := r.collectListenersForGateway(ctx, gatewayName, gatewayNamespace)
 	if err != nil {
 		log.Error(err, "Failed to collect listeners for new Gateway")
 		return err
 	}
 
+	// A Gateway needs at least one listener, and routes that only attach to listeners
+	// managed outside the operator expect the Gateway to be created outside it as well
+	if len(listeners) == 0 {
+		log.Info("No operator managed listeners for new Gateway, not creating it", "gateway", gatewayName, "namespace", gatewayNamespace)
+		return nil
+	}
+
+	// Allow the Gateway to reference certificates in other namespaces
+	if err := r.syncSecretReferenceGrants(ctx, gatewayName, gatewayNamespace, listeners); err != nil {
+		log.Error(err, "Failed to sync ReferenceGrants for certificates", "gateway", gatewayName)
+		return err
+	}
+	if err := r.syncCertificates(ctx, gatewayName, gatewayNamespace, settings.Issuer, listeners); err != nil {
+		log.Error(err, "Failed to sync Certificates", "gateway", gatewayName)
+		return err
+	}
+
+	// HTTP-01 challenges need their listeners before the certificates can be issued
+	http01, err := r.http01Listeners(ctx, gatewayName, gatewayNamespace, settings.Issuer, listeners)
+	if err != nil {
+		return err
+	}
+
+	// Listeners wait for their certificate, the Gateway is created once one of them can be added
+	listeners, pending, err := r.withReadyCertificates(ctx, gatewayName, gatewayNamespace, nil, listeners)
+	if err != nil {
+		return err
+	}
+	if len(listeners) == 0 {
+		log.Info("Waiting for certificates before creating Gateway", "gateway", gatewayName, "namespace", gatewayNamespace, "listeners", pending)
+		return fmt.Errorf("waiting for the certificates of listeners %v", pending)
+	}
+	listeners, httpListeners := r.withHTTPListeners(gatewayNamespace, listeners, http01)
+	if r.exceedsListenerLimit(ctx, route, gatewayName, gatewayNamespace, listeners) {
+		return nil
+	}
+
 	newGateway := &gatewayv1.Gateway{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "gateway.networking.k8s.io/v1",
+			Kind:       "Gateway",
+		},
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      gatewayName,
 			Namespace: gatewayNamespace,
 			Annotations: map[string]string{
-				clusterIssuerAnnotation: clusterIssuer,
+				AnnotationManagedGateway: "true",
 			},
 		},
 		Spec: gatewayv1.GatewaySpec{
-			GatewayClassName: gatewayClassName,
+			GatewayClassName: settings.GatewayClass,
 			Listeners:        listeners,
 			Infrastructure: &gatewayv1.GatewayInfrastructure{
 				Annotations: map[gatewayv1.AnnotationKey]gatewayv1.AnnotationValue{
-					"ipam.vitistack.io/zone": gatewayv1.AnnotationValue(ipamZone),
+					"ipam.vitistack.io/zone":         gatewayv1.AnnotationValue(settings.IPAMZone),
+					ipFamilyInfrastructureAnnotation: gatewayv1.AnnotationValue(settings.IPFamily),
 				},
 			},
 		},
 	}
+	r.setGatewayIssuer(newGateway.Annotations, settings.Issuer)
 
-	if err := r.Create(ctx, newGateway); err != nil {
+	// Labels and annotations propagated from the routes
+	labels, annotations, err := r.propagatedMetadata(ctx, gatewayName, gatewayNamespace)
+	if err != nil {
+		return err
+	}
+	setPropagatedMetadata(newGateway, labels, annotations)
+	infraLabels, err := r.infrastructureLabels(ctx, gatewayName, gatewayNamespace)
+	if err != nil {
+		return err
+	}
+	setInfrastructureLabels(newGateway, infraLabels)
+	envoyProxy, err := r.envoyProxySpec(ctx, newGateway)
+	if err != nil {
+		return err
+	}
+	setEnvoyProxyRef(newGateway, envoyProxy)
+	address, err := r.requestedAddress(ctx, gatewayName, gatewayNamespace)
+	if err != nil {
+		return err
+	}
+	if address == "" {
+		if address, err = r.claimAddress(ctx, gatewayName, gatewayNamespace, settings.IPAMZone, nil); err != nil {
+			return err
+		}
+	}
+	setGatewayAddress(newGateway, address)
+
+	if r.EnableListenerSets {
+		if err := r.createGatewayWithListenerSets(ctx, newGateway, listeners); err != nil {
+			log.Error(err, "Failed to create Gateway with ListenerSets", "gateway", gatewayName)
+			return err
+		}
+		log.Info("Successfully created Gateway", "gateway", gatewayName, "namespace", gatewayNamespace, "listeners", len(listeners))
+		r.Recorder.Eventf(newGateway, corev1.EventTypeNormal, "GatewayCreated", "Created Gateway with %d listeners in ListenerSets", len(listeners))
+		return r.syncRedirectRoute(ctx, newGateway, listeners, httpListeners)
+	}
+
+	// Create the gateway through Server-Side Apply, so the listeners are owned by the operator's
+	// field manager from the start and later applies can tell them apart from foreign listeners
+	if err := r.Patch(ctx, newGateway, client.Apply, client.FieldOwner(fieldManager)); err != nil {
 		log.Error(err, "Failed to create Gateway", "gateway", gatewayName)
 		return err
 	}
+	r.expectGateway(newGateway)
+	r.audit(ctx, auditActionCreate, "Gateway", newGateway, "added", listenerNames(listeners))
 
 	log.Info("Successfully created Gateway", "gateway", gatewayName, "namespace", gatewayNamespace, "listeners", len(listeners))
-	return nil
+	r.Recorder.Eventf(newGateway, corev1.EventTypeNormal, "GatewayCreated", "Created Gateway with listeners %s", listenerNames(listeners))
+	return r.syncRedirectRoute(ctx, newGateway, listeners, httpListeners)
 }
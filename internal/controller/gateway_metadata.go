@@ -0,0 +1,203 @@
+package controller
+
+import (
+	"context"
+	"sort"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8slabels "k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// gatewayForApply returns the Gateway the operator applies to an existing gateway it manages.
+// Server-Side Apply removes fields the operator applied before and leaves out now, so besides the
+// listeners it carries the settings the gateway was created with and the propagated metadata.
+func (r *GatewayManager) gatewayForApply(gateway *gatewayv1.Gateway, listeners []gatewayv1.Listener) *gatewayv1.Gateway {
+	patch := &gatewayv1.Gateway{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "gateway.networking.k8s.io/v1",
+			Kind:       "Gateway",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      gateway.Name,
+			Namespace: gateway.Namespace,
+			Annotations: map[string]string{
+				AnnotationManagedGateway: "true",
+			},
+		},
+		Spec: gatewayv1.GatewaySpec{
+			GatewayClassName: gateway.Spec.GatewayClassName,
+			Listeners:        listeners,
+		},
+	}
+
+	if issuer := gatewayIssuer(gateway); issuer.Name != "" {
+		r.setGatewayIssuer(patch.Annotations, issuer)
+	}
+	if gateway.Spec.Infrastructure != nil {
+		for _, key := range []gatewayv1.AnnotationKey{AnnotationIPAMZone, ipFamilyInfrastructureAnnotation} {
+			value, exists := gateway.Spec.Infrastructure.Annotations[key]
+			if !exists {
+				continue
+			}
+			if patch.Spec.Infrastructure == nil {
+				patch.Spec.Infrastructure = &gatewayv1.GatewayInfrastructure{
+					Annotations: make(map[gatewayv1.AnnotationKey]gatewayv1.AnnotationValue),
+				}
+			}
+			patch.Spec.Infrastructure.Annotations[key] = value
+		}
+	}
+	return patch
+}
+
+// propagatedMetadata returns the allow-listed labels and annotations of the routes owning a gateway.
+// When routes disagree on a value, the oldest route wins.
+func (r *GatewayManager) propagatedMetadata(
+	ctx context.Context,
+	gatewayName, gatewayNamespace string,
+) (map[string]string, map[string]string, error) {
+	if len(r.PropagateLabels) == 0 && len(r.PropagateAnnotations) == 0 {
+		return nil, nil, nil
+	}
+
+	routes, err := r.gatewayOwners(ctx, gatewayName, gatewayNamespace)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	sortOldestFirst(routes)
+
+	labels := make(map[string]string)
+	annotations := make(map[string]string)
+	for _, route := range routes {
+		for _, key := range r.PropagateLabels {
+			if value, exists := route.GetLabels()[key]; exists {
+				if _, taken := labels[key]; !taken {
+					labels[key] = value
+				}
+			}
+		}
+		for _, key := range r.PropagateAnnotations {
+			if value, exists := route.GetAnnotations()[key]; exists {
+				if _, taken := annotations[key]; !taken {
+					annotations[key] = value
+				}
+			}
+		}
+	}
+	return labels, annotations, nil
+}
+
+// infrastructureLabels returns the labels for the gateway's infrastructure: the operator defaults,
+// overridden by the infrastructure-labels annotation of the routes owning the gateway. When routes
+// disagree on a value, the oldest route wins.
+func (r *GatewayManager) infrastructureLabels(
+	ctx context.Context,
+	gatewayName, gatewayNamespace string,
+) (map[string]string, error) {
+	log := logf.FromContext(ctx)
+
+	routes, err := r.gatewayOwners(ctx, gatewayName, gatewayNamespace)
+	if err != nil {
+		return nil, err
+	}
+	sortOldestFirst(routes)
+
+	overrides := make(map[string]string)
+	for _, route := range routes {
+		value, exists := route.GetAnnotations()[AnnotationInfrastructureLabels]
+		if !exists {
+			continue
+		}
+		routeLabels, err := k8slabels.ConvertSelectorToLabelsMap(value)
+		if err != nil {
+			log.Error(err, "Invalid infrastructure labels annotation, ignoring it", "route", route.GetName(),
+				"namespace", route.GetNamespace(), "value", value)
+			continue
+		}
+		for key, val := range routeLabels {
+			if _, taken := overrides[key]; !taken {
+				overrides[key] = val
+			}
+		}
+	}
+
+	labels := make(map[string]string, len(r.InfrastructureLabels)+len(overrides))
+	for key, value := range r.InfrastructureLabels {
+		labels[key] = value
+	}
+	for key, value := range overrides {
+		labels[key] = value
+	}
+	return labels, nil
+}
+
+// sortOldestFirst sorts routes by creation time, falling back to namespace/name so ties are
+// resolved deterministically
+func sortOldestFirst(routes []routeInfo) {
+	sort.SliceStable(routes, func(i, j int) bool {
+		ti, tj := routes[i].GetCreationTimestamp(), routes[j].GetCreationTimestamp()
+		if !ti.Equal(&tj) {
+			return ti.Before(&tj)
+		}
+		return client.ObjectKeyFromObject(routes[i]).String() < client.ObjectKeyFromObject(routes[j]).String()
+	})
+}
+
+// setInfrastructureLabels sets labels on the gateway's infrastructure, so the resources provisioned
+// for the gateway get them. They take precedence over labels propagated from the routes.
+func setInfrastructureLabels(gateway *gatewayv1.Gateway, labels map[string]string) {
+	if len(labels) == 0 {
+		return
+	}
+	if gateway.Spec.Infrastructure == nil {
+		gateway.Spec.Infrastructure = &gatewayv1.GatewayInfrastructure{}
+	}
+	if gateway.Spec.Infrastructure.Labels == nil {
+		gateway.Spec.Infrastructure.Labels = make(map[gatewayv1.LabelKey]gatewayv1.LabelValue, len(labels))
+	}
+	for key, value := range labels {
+		gateway.Spec.Infrastructure.Labels[gatewayv1.LabelKey(key)] = gatewayv1.LabelValue(value)
+	}
+}
+
+// setPropagatedMetadata adds the propagated labels and annotations to the gateway and its
+// infrastructure, so they also end up on the resources provisioned for the gateway
+func setPropagatedMetadata(gateway *gatewayv1.Gateway, labels, annotations map[string]string) {
+	if len(labels) == 0 && len(annotations) == 0 {
+		return
+	}
+	if gateway.Labels == nil {
+		gateway.Labels = make(map[string]string)
+	}
+	if gateway.Annotations == nil {
+		gateway.Annotations = make(map[string]string)
+	}
+	if gateway.Spec.Infrastructure == nil {
+		gateway.Spec.Infrastructure = &gatewayv1.GatewayInfrastructure{}
+	}
+	infrastructure := gateway.Spec.Infrastructure
+
+	for key, value := range labels {
+		gateway.Labels[key] = value
+		if infrastructure.Labels == nil {
+			infrastructure.Labels = make(map[gatewayv1.LabelKey]gatewayv1.LabelValue)
+		}
+		infrastructure.Labels[gatewayv1.LabelKey(key)] = gatewayv1.LabelValue(value)
+	}
+	for key, value := range annotations {
+		// The operator's own annotations are never overridden by routes
+		if _, exists := gateway.Annotations[key]; !exists {
+			gateway.Annotations[key] = value
+		}
+		if infrastructure.Annotations == nil {
+			infrastructure.Annotations = make(map[gatewayv1.AnnotationKey]gatewayv1.AnnotationValue)
+		}
+		if _, exists := infrastructure.Annotations[gatewayv1.AnnotationKey(key)]; !exists {
+			infrastructure.Annotations[gatewayv1.AnnotationKey(key)] = gatewayv1.AnnotationValue(value)
+		}
+	}
+}
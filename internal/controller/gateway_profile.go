@@ -0,0 +1,63 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	operatorv1alpha1 "github.com/NorskHelsenett/gatewayapi-operator/api/v1alpha1"
+)
+
+// +kubebuilder:rbac:groups=gatewayapi-operator.vitistack.io,resources=gatewayprofiles,verbs=get;list;watch
+
+// getProfile returns the GatewayProfile a route references with the profile annotation, or nil for
+// routes without one. Clusters without the GatewayProfile CRD have no profiles.
+func (r *GatewayManager) getProfile(ctx context.Context, route client.Object) (*operatorv1alpha1.GatewayProfile, error) {
+	name := route.GetAnnotations()[AnnotationProfile]
+	if name == "" {
+		return nil, nil
+	}
+	var profile operatorv1alpha1.GatewayProfile
+	if err := r.Get(ctx, client.ObjectKey{Name: name}, &profile); err != nil {
+		if meta.IsNoMatchError(err) {
+			return nil, errors.NewNotFound(operatorv1alpha1.GroupVersion.WithResource("gatewayprofiles").GroupResource(), name)
+		}
+		return nil, err
+	}
+	return &profile, nil
+}
+
+// routeProfile returns the settings of the GatewayProfile a route references, or nil when it
+// references none. A profile that can't be read is logged and ignored here, ensureProfile keeps
+// routes referencing a missing profile from getting a Gateway.
+func (r *GatewayManager) routeProfile(ctx context.Context, route client.Object) *operatorv1alpha1.GatewayProfileSpec {
+	profile, err := r.getProfile(ctx, route)
+	if err != nil {
+		logf.FromContext(ctx).Error(err, "Failed to get GatewayProfile, using the defaults", "route", route.GetName(),
+			"namespace", route.GetNamespace(), "profile", route.GetAnnotations()[AnnotationProfile])
+		return nil
+	}
+	if profile == nil {
+		return nil
+	}
+	return &profile.Spec
+}
+
+// ensureProfile checks that the GatewayProfile a route references exists, since the route would
+// otherwise silently get the default settings. A warning event is emitted on the route otherwise,
+// and the returned error makes the route be retried with backoff.
+func (r *GatewayManager) ensureProfile(ctx context.Context, route client.Object) error {
+	_, err := r.getProfile(ctx, route)
+	if !errors.IsNotFound(err) {
+		return err
+	}
+	name := route.GetAnnotations()[AnnotationProfile]
+	logf.FromContext(ctx).Info("GatewayProfile of route does not exist", "route", route.GetName(), "profile", name)
+	r.Recorder.Eventf(route, corev1.EventTypeWarning, "GatewayProfileNotFound", "GatewayProfile %s does not exist", name)
+	return fmt.Errorf("GatewayProfile %s does not exist", name)
+}
@@ -0,0 +1,75 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// programmedRequeueInterval is how often a route is checked again while its Gateway isn't programmed yet
+const programmedRequeueInterval = 15 * time.Second
+
+// gatewayProgrammed returns the Programmed condition of a gateway, or nil when the Gateway
+// implementation hasn't reported it for the gateway's current generation yet
+func gatewayProgrammed(gateway *gatewayv1.Gateway) *metav1.Condition {
+	condition := meta.FindStatusCondition(gateway.Status.Conditions, string(gatewayv1.GatewayConditionProgrammed))
+	if condition == nil || condition.ObservedGeneration != gateway.Generation {
+		return nil
+	}
+	return condition
+}
+
+// ensureGatewayProgrammed checks that the Gateway implementation programmed the route's Gateway.
+// While it is pending the returned duration tells when to check again. A Gateway that is rejected,
+// e.g. for an unknown class or because no address could be assigned, or that is still not
+// programmed after GatewayProgrammedTimeout, fails the route with a GatewayNotProgrammed warning
+// event, so the route isn't reported as served by a Gateway that doesn't work.
+func (r *GatewayManager) ensureGatewayProgrammed(
+	ctx context.Context,
+	route client.Object,
+	gatewayName, gatewayNamespace string,
+) (time.Duration, error) {
+	if r.GatewayProgrammedTimeout == 0 {
+		return 0, nil
+	}
+	log := logf.FromContext(ctx)
+
+	// The Gateway may not be in the cache yet, or wait for its certificates before it is created
+	var gateway gatewayv1.Gateway
+	if err := r.Get(ctx, client.ObjectKey{Name: gatewayName, Namespace: gatewayNamespace}, &gateway); err != nil {
+		return programmedRequeueInterval, client.IgnoreNotFound(err)
+	}
+	if !isManagedGateway(&gateway) {
+		return 0, nil
+	}
+
+	programmed := gatewayProgrammed(&gateway)
+	if programmed != nil && programmed.Status == metav1.ConditionTrue {
+		return 0, nil
+	}
+
+	since := gateway.CreationTimestamp.Time
+	reason, message := "Pending", "the Gateway implementation hasn't programmed it yet"
+	if programmed != nil {
+		since, reason, message = programmed.LastTransitionTime.Time, programmed.Reason, programmed.Message
+	}
+	if reason == string(gatewayv1.GatewayReasonPending) || programmed == nil {
+		if waited := time.Since(since); waited < r.GatewayProgrammedTimeout {
+			log.V(1).Info("Waiting for Gateway to be programmed", "gateway", gatewayName, "namespace", gatewayNamespace, "waited", waited)
+			return programmedRequeueInterval, nil
+		}
+	}
+
+	err := fmt.Errorf("gateway %s/%s is not programmed: %s: %s", gatewayNamespace, gatewayName, reason, message)
+	log.Error(err, "Gateway is not programmed", "gateway", gatewayName, "namespace", gatewayNamespace)
+	r.Recorder.Eventf(route, corev1.EventTypeWarning, "GatewayNotProgrammed",
+		"Gateway %s/%s is not programmed (%s): %s", gatewayNamespace, gatewayName, reason, message)
+	return 0, err
+}
@@ -0,0 +1,122 @@
+package controller
+
+import (
+	"context"
+	"slices"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// gatewaySettings are the Gateway settings a route asks for. Routes sharing a Gateway must agree on them.
+type gatewaySettings struct {
+	IPAMZone     string
+	Issuer       issuerRef
+	GatewayClass gatewayv1.ObjectName
+	Address      string
+	IPFamily     IPFamily
+}
+
+// +kubebuilder:rbac:groups="",resources=namespaces,verbs=get;list;watch
+
+// namespaceDefaults returns the annotations of a route's namespace, where platform admins set the
+// default IPAM zone and cluster issuer of the namespace's routes with the same annotations routes use
+func (r *GatewayManager) namespaceDefaults(ctx context.Context, namespace string) map[string]string {
+	var ns corev1.Namespace
+	if err := r.Get(ctx, client.ObjectKey{Name: namespace}, &ns); err != nil {
+		logf.FromContext(ctx).Error(err, "Failed to get namespace defaults, using the operator defaults", "namespace", namespace)
+		return nil
+	}
+	return ns.Annotations
+}
+
+// routeSecretNamespace returns the namespace the certificate secrets of a route's listeners are in:
+// the namespace named by the route's tls-secret-namespace annotation, if it is one of the allowed
+// namespaces, or by its namespace's annotation. Otherwise the secrets are in the gateway's
+// namespace, except on the shared gateway where the route's namespace holds the secrets for its
+// hostnames.
+func (r *GatewayManager) routeSecretNamespace(ctx context.Context, route client.Object, gatewayNamespace string) string {
+	if namespace := route.GetAnnotations()[AnnotationTLSSecretNamespace]; namespace != "" {
+		if namespace == route.GetNamespace() || slices.Contains(r.TLSSecretNamespaces, namespace) {
+			return namespace
+		}
+		logf.FromContext(ctx).Info("Route asks for certificate secrets in a namespace that isn't allowed, ignoring it",
+			"route", route.GetName(), "namespace", route.GetNamespace(), "secretNamespace", namespace)
+	}
+	if namespace := r.namespaceDefaults(ctx, route.GetNamespace())[AnnotationTLSSecretNamespace]; namespace != "" {
+		return namespace
+	}
+	if r.isSharedGateway(gatewayNamespace) {
+		return route.GetNamespace()
+	}
+	return gatewayNamespace
+}
+
+// routeIPAMZone returns the IPAM zone a route asks for, falling back to its GatewayProfile, the
+// default of its namespace and then to the global default
+func (r *GatewayManager) routeIPAMZone(ctx context.Context, route client.Object) string {
+	if zone := route.GetAnnotations()[AnnotationIPAMZone]; zone != "" {
+		return zone
+	}
+	if profile := r.routeProfile(ctx, route); profile != nil && profile.IPAMZone != "" {
+		return profile.IPAMZone
+	}
+	if zone := r.namespaceDefaults(ctx, route.GetNamespace())[AnnotationIPAMZone]; zone != "" {
+		return zone
+	}
+	return r.config().IPAMZone
+}
+
+// gatewaySettingsFor resolves the Gateway settings a route asks for from its annotations, falling
+// back to its GatewayProfile, then to the defaults of its namespace for the IPAM zone and cluster
+// issuer, then to the operator defaults. The GatewayClass is taken from the route or its profile,
+// then from the class mapped to the route's IPAM zone, then from the default class.
+func (r *GatewayManager) gatewaySettingsFor(ctx context.Context, route client.Object) gatewaySettings {
+	log := logf.FromContext(ctx)
+	annotations := route.GetAnnotations()
+	profile := r.routeProfile(ctx, route)
+
+	// Get IPAM zone from annotation or use default
+	ipamZone := r.routeIPAMZone(ctx, route)
+	if annotations[AnnotationIPAMZone] == "" {
+		log.Info("No IPAM zone annotation found, using default", "ipamZone", ipamZone)
+	}
+
+	// Get issuer from annotation or use default
+	issuer := r.routeIssuer(ctx, route)
+	if annotations[AnnotationClusterIssuer] == "" {
+		log.Info("No cluster issuer annotation found, using default", "issuer", issuer.String())
+	}
+
+	gatewayClass := annotations[AnnotationGatewayClass]
+	if gatewayClass == "" && profile != nil {
+		gatewayClass = profile.GatewayClassName
+	}
+	if gatewayClass == "" {
+		gatewayClass = r.ZoneGatewayClasses[ipamZone]
+	}
+	if gatewayClass == "" {
+		gatewayClass = r.config().GatewayClass
+	}
+
+	ipFamily := IPFamily(annotations[AnnotationIPFamily])
+	if ipFamily == "" && profile != nil {
+		ipFamily = IPFamily(profile.IPFamily)
+	}
+	if ipFamily == "" {
+		ipFamily = r.DefaultIPFamily
+	}
+	if ipFamily == "" {
+		ipFamily = IPFamilyIPv4
+	}
+
+	return gatewaySettings{
+		IPAMZone:     ipamZone,
+		Issuer:       issuer,
+		GatewayClass: gatewayv1.ObjectName(gatewayClass),
+		Address:      annotations[AnnotationAddress],
+		IPFamily:     ipFamily,
+	}
+}
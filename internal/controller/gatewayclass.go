@@ -0,0 +1,41 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// +kubebuilder:rbac:groups=gateway.networking.k8s.io,resources=gatewayclasses,verbs=get;list;watch
+
+// ensureGatewayClassAccepted checks that a GatewayClass exists and has been accepted by its
+// controller before a Gateway is created for it, since a Gateway of a missing or rejected class
+// is never programmed. A warning event is emitted on the route otherwise, and the returned error
+// makes the route be retried with backoff.
+func (r *GatewayManager) ensureGatewayClassAccepted(ctx context.Context, route client.Object, className gatewayv1.ObjectName) error {
+	log := logf.FromContext(ctx)
+
+	var gatewayClass gatewayv1.GatewayClass
+	if err := r.Get(ctx, client.ObjectKey{Name: string(className)}, &gatewayClass); err != nil {
+		if client.IgnoreNotFound(err) != nil {
+			return err
+		}
+		log.Info("GatewayClass does not exist, not creating Gateway", "gatewayClass", className)
+		r.Recorder.Eventf(route, corev1.EventTypeWarning, "GatewayClassNotFound",
+			"GatewayClass %s does not exist, the Gateway is created once it exists", className)
+		return fmt.Errorf("GatewayClass %s does not exist", className)
+	}
+
+	if !meta.IsStatusConditionTrue(gatewayClass.Status.Conditions, string(gatewayv1.GatewayClassConditionStatusAccepted)) {
+		log.Info("GatewayClass is not accepted, not creating Gateway", "gatewayClass", className)
+		r.Recorder.Eventf(route, corev1.EventTypeWarning, "GatewayClassNotAccepted",
+			"GatewayClass %s is not accepted by its controller, the Gateway is created once it is", className)
+		return fmt.Errorf("GatewayClass %s is not accepted", className)
+	}
+	return nil
+}
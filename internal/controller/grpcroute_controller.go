@@ -0,0 +1,44 @@
+package controller
+
+import (
+	"context"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// GRPCRouteReconciler reconciles a GRPCRoute object
+type GRPCRouteReconciler struct {
+	*GatewayManager
+}
+
+// +kubebuilder:rbac:groups=gateway.networking.k8s.io,resources=grpcroutes,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=gateway.networking.k8s.io,resources=grpcroutes/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=gateway.networking.k8s.io,resources=grpcroutes/finalizers,verbs=update
+
+// Reconcile ensures the Gateway referenced by a GRPCRoute has HTTPS listeners for its hostnames.
+// GRPCRoutes share Gateways and listeners with HTTPRoutes.
+func (r *GRPCRouteReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	// Fetch the GRPCRoute
+	var grpcRoute gatewayv1.GRPCRoute
+	if err := r.Get(ctx, req.NamespacedName, &grpcRoute); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	return r.reconcileRoute(ctx, &grpcRoute, grpcRoute.Spec.ParentRefs)
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *GRPCRouteReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&gatewayv1.GRPCRoute{}, builder.WithPredicates(routeChanged)).
+		Watches(&gatewayv1.Gateway{}, handler.EnqueueRequestsFromMapFunc(r.routesForDeletedGateway(&gatewayv1.GRPCRouteList{})),
+			builder.WithPredicates(gatewayDeleted)).
+		WatchesRawSource(r.startupSource("GRPCRoute")).
+		Named("grpcroute").
+		WithOptions(r.controllerOptions()).
+		Complete(r)
+}
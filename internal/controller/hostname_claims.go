@@ -0,0 +1,258 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	operatorv1alpha1 "github.com/NorskHelsenett/gatewayapi-operator/api/v1alpha1"
+)
+
+// +kubebuilder:rbac:groups=gatewayapi-operator.vitistack.io,resources=hostnameclaims,verbs=get;list;watch;create;update;delete
+
+// HostnameCollisionPolicy decides what happens to a route asking for a hostname claimed by another
+// namespace
+type HostnameCollisionPolicy string
+
+const (
+	// HostnameCollisionPolicyReject rejects the route, the hostname stays with the claiming namespace
+	HostnameCollisionPolicyReject HostnameCollisionPolicy = "Reject"
+
+	// HostnameCollisionPolicyAllowList adds the route's namespace to the allowed namespaces of the
+	// claim, so both namespaces serve the hostname. Claims created by platform admins are kept as
+	// they are and the route is rejected.
+	HostnameCollisionPolicyAllowList HostnameCollisionPolicy = "AllowList"
+)
+
+// IsValid reports whether the policy is one of the known hostname collision policies
+func (p HostnameCollisionPolicy) IsValid() bool {
+	switch p {
+	case HostnameCollisionPolicyReject, HostnameCollisionPolicyAllowList:
+		return true
+	}
+	return false
+}
+
+// hostnameClaimName returns the name of the HostnameClaim of a hostname. Wildcard hostnames are
+// named like their listeners, since * isn't allowed in object names.
+func hostnameClaimName(hostname string) string {
+	name, _ := listenerHostname(hostname)
+	return name
+}
+
+// ensureHostnameClaims claims the hostnames of a route for its namespace, so routes in other
+// namespaces can't serve them, even from other Gateways. The first namespace to claim a hostname
+// keeps it until none of its routes use the hostname anymore. A route asking for a hostname
+// claimed by another namespace is handled by the hostname collision policy, see hostnameCollision.
+func (r *GatewayManager) ensureHostnameClaims(ctx context.Context, route client.Object) error {
+	log := logf.FromContext(ctx)
+	info, ok := newRouteInfo(route)
+	if !ok {
+		return nil
+	}
+
+	for _, hostname := range info.Hostnames {
+		if hostname == "" {
+			continue
+		}
+		name := hostnameClaimName(string(hostname))
+
+		var claim operatorv1alpha1.HostnameClaim
+		err := r.Get(ctx, client.ObjectKey{Name: name}, &claim)
+		if errors.IsNotFound(err) {
+			claim = operatorv1alpha1.HostnameClaim{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:   name,
+					Labels: map[string]string{managedByLabel: managedByValue},
+				},
+				Spec: operatorv1alpha1.HostnameClaimSpec{
+					Hostname:  string(hostname),
+					Namespace: route.GetNamespace(),
+				},
+			}
+			err = r.Create(ctx, &claim)
+			if err == nil {
+				log.Info("Claimed hostname", "hostname", hostname, "namespace", route.GetNamespace())
+				continue
+			}
+			// Another namespace claimed the hostname first, the cache doesn't have the claim yet
+			if errors.IsAlreadyExists(err) {
+				err = r.APIReader.Get(ctx, client.ObjectKey{Name: name}, &claim)
+			}
+		}
+		if err != nil {
+			return err
+		}
+
+		if !claimAllows(&claim, route.GetNamespace()) {
+			if err := r.hostnameCollision(ctx, route, &claim); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// claimAllows reports whether the routes of a namespace may use the hostname of a claim
+func claimAllows(claim *operatorv1alpha1.HostnameClaim, namespace string) bool {
+	return claim.Spec.Namespace == namespace || slices.Contains(claim.Spec.AllowedNamespaces, namespace)
+}
+
+// hostnameCollision applies the hostname collision policy to a route asking for a hostname claimed
+// by another namespace. With AllowList the route's namespace is added to the allowed namespaces of
+// a claim the operator created, otherwise the route is rejected. The route and the oldest route of
+// the claiming namespace using the hostname both get an event telling the decision.
+func (r *GatewayManager) hostnameCollision(ctx context.Context, route client.Object, claim *operatorv1alpha1.HostnameClaim) error {
+	log := logf.FromContext(ctx)
+	hostname := claim.Spec.Hostname
+
+	owner, err := r.hostnameOwnerRoute(ctx, claim.Spec.Namespace, hostname)
+	if err != nil {
+		return err
+	}
+
+	if r.HostnameCollisionPolicy == HostnameCollisionPolicyAllowList && claim.Labels[managedByLabel] == managedByValue {
+		claim.Spec.AllowedNamespaces = append(claim.Spec.AllowedNamespaces, route.GetNamespace())
+		if err := r.Update(ctx, claim); err != nil {
+			return err
+		}
+		log.Info("Allowed claimed hostname for another namespace", "hostname", hostname,
+			"claimNamespace", claim.Spec.Namespace, "namespace", route.GetNamespace())
+		r.Recorder.Eventf(route, corev1.EventTypeNormal, "HostnameShared",
+			"Hostname %s is claimed by namespace %s, namespace %s was added to the namespaces allowed to use it",
+			hostname, claim.Spec.Namespace, route.GetNamespace())
+		if owner != nil {
+			r.Recorder.Eventf(owner, corev1.EventTypeNormal, "HostnameShared",
+				"Hostname %s claimed by this namespace is now also used by route %s/%s",
+				hostname, route.GetNamespace(), route.GetName())
+		}
+		return nil
+	}
+
+	err = errors.NewBadRequest(fmt.Sprintf("hostname %s is claimed by namespace %s", hostname, claim.Spec.Namespace))
+	log.Error(err, "Hostname claimed by another namespace", "route", route.GetName(), "hostname", hostname)
+	r.Recorder.Eventf(route, corev1.EventTypeWarning, "HostnameClaimed",
+		"Hostname %s is claimed by namespace %s", hostname, claim.Spec.Namespace)
+	if owner != nil {
+		r.Recorder.Eventf(owner, corev1.EventTypeWarning, "HostnameCollision",
+			"Route %s/%s asks for hostname %s claimed by this namespace and was rejected",
+			route.GetNamespace(), route.GetName(), hostname)
+	}
+	return err
+}
+
+// hostnameOwnerRoute returns the oldest route of a namespace using a hostname, or nil when no
+// route uses it, e.g. for a hostname reserved by platform admins
+func (r *GatewayManager) hostnameOwnerRoute(ctx context.Context, namespace, hostname string) (client.Object, error) {
+	routes, err := r.listRoutes(ctx, client.InNamespace(namespace))
+	if err != nil {
+		return nil, err
+	}
+	sortOldestFirst(routes)
+	for _, route := range routes {
+		if route.Enabled && route.GetDeletionTimestamp().IsZero() && slices.Contains(route.Hostnames, gatewayv1.Hostname(hostname)) {
+			return route.Object, nil
+		}
+	}
+	return nil, nil
+}
+
+// releaseHostnameClaims deletes the HostnameClaims of a namespace that none of its routes use
+// anymore, so the hostnames are free for other namespaces. A claim with allowed namespaces is
+// handed over to the first of them instead, and a namespace that stopped using a hostname it was
+// allowed to use is removed from the claim. Only claims created by the operator are released,
+// claims created by platform admins to reserve hostnames are kept.
+func (r *GatewayManager) releaseHostnameClaims(ctx context.Context, namespace string) error {
+	log := logf.FromContext(ctx)
+
+	routes, err := r.listRoutes(ctx, client.InNamespace(namespace))
+	if err != nil {
+		return err
+	}
+	used := make(map[string]bool)
+	for _, route := range routes {
+		if !route.Enabled || !route.GetDeletionTimestamp().IsZero() {
+			continue
+		}
+		for _, hostname := range route.Hostnames {
+			used[string(hostname)] = true
+		}
+	}
+
+	var claims operatorv1alpha1.HostnameClaimList
+	if err := r.List(ctx, &claims, client.MatchingLabels{managedByLabel: managedByValue}); err != nil {
+		return err
+	}
+	for i := range claims.Items {
+		claim := &claims.Items[i]
+		if !claimAllows(claim, namespace) || used[claim.Spec.Hostname] {
+			continue
+		}
+		switch {
+		case claim.Spec.Namespace != namespace:
+			claim.Spec.AllowedNamespaces = slices.DeleteFunc(claim.Spec.AllowedNamespaces, func(allowed string) bool {
+				return allowed == namespace
+			})
+			if err := r.Update(ctx, claim); client.IgnoreNotFound(err) != nil {
+				return err
+			}
+			log.Info("Removed namespace from hostname claim", "hostname", claim.Spec.Hostname, "namespace", namespace)
+		case len(claim.Spec.AllowedNamespaces) > 0:
+			claim.Spec.Namespace = claim.Spec.AllowedNamespaces[0]
+			claim.Spec.AllowedNamespaces = claim.Spec.AllowedNamespaces[1:]
+			if err := r.Update(ctx, claim); client.IgnoreNotFound(err) != nil {
+				return err
+			}
+			log.Info("Handed hostname claim over", "hostname", claim.Spec.Hostname, "previousNamespace", namespace,
+				"namespace", claim.Spec.Namespace)
+		default:
+			if err := r.Delete(ctx, claim); client.IgnoreNotFound(err) != nil {
+				return err
+			}
+			log.Info("Released hostname claim", "hostname", claim.Spec.Hostname, "namespace", namespace)
+		}
+	}
+	return nil
+}
+
+// hostnameClaimOwners returns the namespaces allowed to use each claimed hostname, the claiming
+// namespace first, or nil when hostname claims are disabled
+func (r *GatewayManager) hostnameClaimOwners(ctx context.Context) (map[string][]string, error) {
+	if !r.EnableHostnameClaims {
+		return nil, nil
+	}
+	var claims operatorv1alpha1.HostnameClaimList
+	if err := r.List(ctx, &claims); err != nil {
+		return nil, err
+	}
+	owners := make(map[string][]string, len(claims.Items))
+	for _, claim := range claims.Items {
+		owners[claim.Spec.Hostname] = append([]string{claim.Spec.Namespace}, claim.Spec.AllowedNamespaces...)
+	}
+	return owners, nil
+}
+
+// withoutClaimedHostnames drops the hostnames of a route claimed by another namespace that doesn't
+// allow the route's namespace, so a route that was rejected for them doesn't get listeners for
+// them either. Hostnames that aren't claimed yet are kept, the route reconciler claims them.
+func withoutClaimedHostnames(route routeInfo, owners map[string][]string) routeInfo {
+	if owners == nil {
+		return route
+	}
+	hostnames := make([]gatewayv1.Hostname, 0, len(route.Hostnames))
+	for _, hostname := range route.Hostnames {
+		if allowed, claimed := owners[string(hostname)]; claimed && !slices.Contains(allowed, route.GetNamespace()) {
+			continue
+		}
+		hostnames = append(hostnames, hostname)
+	}
+	route.Hostnames = hostnames
+	return route
+}
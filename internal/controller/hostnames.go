@@ -0,0 +1,123 @@
+package controller
+
+import (
+	"fmt"
+	"net"
+	"slices"
+	"strings"
+
+	"golang.org/x/net/idna"
+	"k8s.io/apimachinery/pkg/util/validation"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// httpRouteHostnames returns the hostnames of an HTTPRoute. Routes without spec.hostnames
+// can opt in to deriving them from exact Host header matches in their rules.
+func httpRouteHostnames(route *gatewayv1.HTTPRoute) []gatewayv1.Hostname {
+	if len(route.Spec.Hostnames) > 0 || route.Annotations[AnnotationHostnameFallback] != hostnameFallbackMatchRules {
+		return route.Spec.Hostnames
+	}
+
+	var hostnames []gatewayv1.Hostname
+	for _, rule := range route.Spec.Rules {
+		for _, match := range rule.Matches {
+			for _, header := range match.Headers {
+				if header.Type != nil && *header.Type != gatewayv1.HeaderMatchExact {
+					continue
+				}
+				hostnames = appendHostHeader(hostnames, string(header.Name), header.Value)
+			}
+		}
+	}
+	return hostnames
+}
+
+// grpcRouteHostnames returns the hostnames of a GRPCRoute. Routes without spec.hostnames
+// can opt in to deriving them from exact Host header matches in their rules.
+func grpcRouteHostnames(route *gatewayv1.GRPCRoute) []gatewayv1.Hostname {
+	if len(route.Spec.Hostnames) > 0 || route.Annotations[AnnotationHostnameFallback] != hostnameFallbackMatchRules {
+		return route.Spec.Hostnames
+	}
+
+	var hostnames []gatewayv1.Hostname
+	for _, rule := range route.Spec.Rules {
+		for _, match := range rule.Matches {
+			for _, header := range match.Headers {
+				if header.Type != nil && *header.Type != gatewayv1.GRPCHeaderMatchExact {
+					continue
+				}
+				hostnames = appendHostHeader(hostnames, string(header.Name), header.Value)
+			}
+		}
+	}
+	return hostnames
+}
+
+// appendHostHeader appends the hostname from a Host (or :authority) header match value,
+// without any port, unless the header is another header or the hostname is already present
+func appendHostHeader(hostnames []gatewayv1.Hostname, name, value string) []gatewayv1.Hostname {
+	if !strings.EqualFold(name, "Host") && !strings.EqualFold(name, ":authority") {
+		return hostnames
+	}
+	if host, _, err := net.SplitHostPort(value); err == nil {
+		value = host
+	}
+	hostname := gatewayv1.Hostname(strings.ToLower(value))
+	if hostname == "" || slices.Contains(hostnames, hostname) {
+		return hostnames
+	}
+	return append(hostnames, hostname)
+}
+
+// normalizeHostname converts a hostname to the form listeners are created for: internationalized
+// domain names are converted to punycode, the case is normalized to lower case and a trailing dot
+// is removed. Hostnames that aren't valid RFC 1123 DNS names afterwards, optionally with a leading
+// wildcard label, are rejected.
+func normalizeHostname(hostname string) (gatewayv1.Hostname, error) {
+	domain, wildcard := strings.CutPrefix(strings.TrimSuffix(hostname, "."), "*.")
+	ascii, err := idna.Lookup.ToASCII(domain)
+	if err != nil {
+		return "", fmt.Errorf("hostname %q is not a valid domain name: %w", hostname, err)
+	}
+	if wildcard {
+		ascii = "*." + ascii
+		if errs := validation.IsWildcardDNS1123Subdomain(ascii); len(errs) > 0 {
+			return "", fmt.Errorf("hostname %q is not valid: %s", hostname, strings.Join(errs, ", "))
+		}
+	} else if errs := validation.IsDNS1123Subdomain(ascii); len(errs) > 0 {
+		return "", fmt.Errorf("hostname %q is not valid: %s", hostname, strings.Join(errs, ", "))
+	}
+	return gatewayv1.Hostname(ascii), nil
+}
+
+// normalizeHostnames normalizes the hostnames of a route, dropping duplicates. It also returns the
+// invalid hostnames, which are left out.
+func normalizeHostnames(hostnames []gatewayv1.Hostname) ([]gatewayv1.Hostname, []string) {
+	if len(hostnames) == 0 {
+		return hostnames, nil
+	}
+	normalized := make([]gatewayv1.Hostname, 0, len(hostnames))
+	var invalid []string
+	for _, hostname := range hostnames {
+		hn, err := normalizeHostname(string(hostname))
+		if err != nil {
+			invalid = append(invalid, string(hostname))
+			continue
+		}
+		if !slices.Contains(normalized, hn) {
+			normalized = append(normalized, hn)
+		}
+	}
+	return normalized, invalid
+}
+
+// wildcardCovers reports whether a wildcard hostname like *.apps.example.com covers a hostname.
+// Only a single label is matched, since that is all a wildcard certificate is valid for.
+func wildcardCovers(wildcard, hostname string) bool {
+	suffix, ok := strings.CutPrefix(wildcard, "*")
+	if !ok || hostname == wildcard {
+		return false
+	}
+	label, found := strings.CutSuffix(hostname, suffix)
+	return found && label != "" && !strings.Contains(label, ".") && label != "*"
+}
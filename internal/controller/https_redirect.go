@@ -0,0 +1,152 @@
+package controller
+
+import (
+	"context"
+	"maps"
+	"slices"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// redirectRouteSuffix is the suffix of the name of the HTTPRoute redirecting a gateway's HTTP
+// listeners to HTTPS
+const redirectRouteSuffix = "-https-redirect"
+
+// withHTTPListeners adds the port 80 listeners to the gateway's listeners: an HTTP listener for
+// the hostname of every HTTPS listener when HTTPS redirects are enabled, and the HTTP-01 listeners
+// given by http01. Redirect listeners only accept routes from the gateway's own namespace, where
+// the operator keeps the HTTPRoute doing the redirect. A hostname with both gets one listener
+// admitting the namespaces of both. Hostnames a route serves plain HTTP for with the protocol
+// annotation keep the route's listener. It also returns the names of the added listeners.
+func (r *GatewayManager) withHTTPListeners(
+	gatewayNamespace string,
+	listeners []gatewayv1.Listener,
+	http01 []gatewayv1.Listener,
+) ([]gatewayv1.Listener, []gatewayv1.SectionName) {
+	httpListeners := make(map[gatewayv1.SectionName]gatewayv1.Listener)
+	if r.EnableHTTPSRedirect {
+		for _, listener := range listeners {
+			if listener.Protocol != gatewayv1.HTTPSProtocolType || listener.Hostname == nil {
+				continue
+			}
+			name, _ := listenerHostname(string(*listener.Hostname))
+			listenerName := r.sectionName(httpListenerPrefix + name)
+			httpListeners[listenerName] = gatewayv1.Listener{
+				Name:          listenerName,
+				Protocol:      gatewayv1.HTTPProtocolType,
+				Port:          r.config().HTTPPort,
+				Hostname:      listener.Hostname,
+				AllowedRoutes: namespaceAllowedRoutes(gatewayNamespace),
+			}
+		}
+	}
+	for _, listener := range http01 {
+		if existing, exists := httpListeners[listener.Name]; exists {
+			listener = mergeAllowedNamespaces(existing, listener)
+		}
+		httpListeners[listener.Name] = listener
+	}
+
+	var added []gatewayv1.SectionName
+	for _, name := range slices.Sorted(maps.Keys(httpListeners)) {
+		if slices.ContainsFunc(listeners, func(listener gatewayv1.Listener) bool { return listener.Name == name }) {
+			continue
+		}
+		listeners = append(listeners, httpListeners[name])
+		added = append(added, name)
+	}
+	return listeners, added
+}
+
+// syncRedirectRoute applies the HTTPRoute redirecting the HTTP listeners added by withHTTPListeners
+// to HTTPS with a 301, and deletes it when there are none or redirects are disabled. HTTP-01
+// solver routes match the exact challenge paths, so they take precedence over the redirect.
+func (r *GatewayManager) syncRedirectRoute(
+	ctx context.Context,
+	gateway *gatewayv1.Gateway,
+	listeners []gatewayv1.Listener,
+	httpListeners []gatewayv1.SectionName,
+) error {
+	log := logf.FromContext(ctx)
+	name := gateway.Name + redirectRouteSuffix
+
+	var parentRefs []gatewayv1.ParentReference
+	var hostnames []gatewayv1.Hostname
+	for i, listener := range listeners {
+		if !r.EnableHTTPSRedirect || !slices.Contains(httpListeners, listener.Name) {
+			continue
+		}
+		sectionName := listener.Name
+		parentRef := gatewayv1.ParentReference{
+			Name:        gatewayv1.ObjectName(gateway.Name),
+			SectionName: &sectionName,
+		}
+		// The listeners are spread over ListenerSets in order, the route attaches to the one
+		// holding the listener
+		if r.EnableListenerSets {
+			parentRef.Group = (*gatewayv1.Group)(ptr(listenerSetGVK.Group))
+			parentRef.Kind = (*gatewayv1.Kind)(ptr(listenerSetGVK.Kind))
+			parentRef.Name = gatewayv1.ObjectName(listenerSetName(gateway.Name, i/maxListenersPerListenerSet+1))
+		}
+		parentRefs = append(parentRefs, parentRef)
+		hostnames = append(hostnames, *listener.Hostname)
+	}
+
+	if len(parentRefs) == 0 {
+		var route gatewayv1.HTTPRoute
+		if err := r.Get(ctx, client.ObjectKey{Name: name, Namespace: gateway.Namespace}, &route); err != nil {
+			return client.IgnoreNotFound(err)
+		}
+		if !metav1.IsControlledBy(&route, gateway) {
+			return nil
+		}
+		if err := r.Delete(ctx, &route); client.IgnoreNotFound(err) != nil {
+			return err
+		}
+		log.Info("Deleted HTTPS redirect HTTPRoute", "httpRoute", name, "namespace", gateway.Namespace)
+		return nil
+	}
+
+	statusCode := 301
+	route := &gatewayv1.HTTPRoute{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: gatewayv1.GroupVersion.String(),
+			Kind:       "HTTPRoute",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: gateway.Namespace,
+			Labels:    map[string]string{managedByLabel: managedByValue},
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(gateway, gatewayv1.SchemeGroupVersion.WithKind("Gateway")),
+			},
+		},
+		Spec: gatewayv1.HTTPRouteSpec{
+			CommonRouteSpec: gatewayv1.CommonRouteSpec{
+				ParentRefs: parentRefs,
+			},
+			Hostnames: hostnames,
+			Rules: []gatewayv1.HTTPRouteRule{
+				{
+					Filters: []gatewayv1.HTTPRouteFilter{
+						{
+							Type: gatewayv1.HTTPRouteFilterRequestRedirect,
+							RequestRedirect: &gatewayv1.HTTPRequestRedirectFilter{
+								Scheme:     ptr("https"),
+								StatusCode: &statusCode,
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	if err := r.Patch(ctx, route, client.Apply, client.ForceOwnership, client.FieldOwner(fieldManager)); err != nil {
+		return err
+	}
+	log.Info("Applied HTTPS redirect HTTPRoute", "httpRoute", name, "namespace", gateway.Namespace, "hostnames", len(hostnames))
+	return nil
+}
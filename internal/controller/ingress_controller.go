@@ -0,0 +1,339 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// ingressLabel holds the UID of the Ingress an HTTPRoute was generated from. Ingress names can be
+// longer than label values, so routes are found by their controller reference.
+const ingressLabel = "gatewayapi-operator.vitistack.io/ingress"
+
+// maxHTTPRouteRules is the number of rules an HTTPRoute has room for
+const maxHTTPRouteRules = 16
+
+// ingressAnnotationPrefixes are the prefixes of the Ingress annotations copied to the HTTPRoutes
+// generated from it, so the routes get the operator settings of the Ingress
+var ingressAnnotationPrefixes = []string{"gatewayapi-operator.vitistack.io/", "ipam.vitistack.io/"}
+
+// IngressReconciler generates HTTPRoutes from the Ingresses opted in with the adopt-ingress
+// annotation, one per host of the Ingress rules, so teams can move from an ingress controller to
+// the operator's Gateways without rewriting their manifests. The routes are owned by the Ingress,
+// follow its changes and are removed with it or when the annotation is removed. The Ingress
+// itself is left alone, so the ingress controller keeps serving it until DNS points at the Gateway.
+type IngressReconciler struct {
+	*GatewayManager
+}
+
+// +kubebuilder:rbac:groups=networking.k8s.io,resources=ingresses,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=services,verbs=get
+
+// Reconcile applies the HTTPRoutes of an Ingress and deletes the ones it doesn't generate anymore
+func (r *IngressReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := logf.FromContext(ctx)
+
+	if shuttingDown(ctx) {
+		return ctrl.Result{}, nil
+	}
+
+	// Ingresses are spread over operator instances by namespace, like routes without parent references
+	if !r.gatewayInShard("", req.Namespace) {
+		log.V(1).Info("Skipping Ingress - handled by another operator shard", "name", req.Name, "namespace", req.Namespace)
+		return ctrl.Result{}, nil
+	}
+	if !r.inNamespaceScope(ctx, req.Namespace) {
+		log.V(1).Info("Skipping Ingress - namespace not managed by the operator", "name", req.Name, "namespace", req.Namespace)
+		return ctrl.Result{}, nil
+	}
+
+	var ingress networkingv1.Ingress
+	if err := r.Get(ctx, req.NamespacedName, &ingress); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	// The routes of a deleted Ingress are garbage collected with it
+	if !ingress.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, nil
+	}
+
+	var routes []*gatewayv1.HTTPRoute
+	if ingress.Annotations[AnnotationAdoptIngress] == "true" {
+		var err error
+		if routes, err = r.ingressRoutes(ctx, &ingress); err != nil {
+			log.Error(err, "Failed to generate HTTPRoutes from Ingress", "ingress", ingress.Name)
+			r.Recorder.Eventf(&ingress, corev1.EventTypeWarning, "IngressNotAdopted",
+				"Failed to generate HTTPRoutes from the Ingress: %v", err)
+			// An Ingress the operator can't convert only changes with the Ingress, which triggers a new reconcile
+			if errors.IsBadRequest(err) {
+				return ctrl.Result{}, nil
+			}
+			return ctrl.Result{}, err
+		}
+	}
+
+	var managed gatewayv1.HTTPRouteList
+	if err := r.List(ctx, &managed, client.InNamespace(ingress.Namespace),
+		client.MatchingLabels{managedByLabel: managedByValue}); err != nil {
+		return ctrl.Result{}, err
+	}
+	var existing []gatewayv1.HTTPRoute
+	for _, route := range managed.Items {
+		if metav1.IsControlledBy(&route, &ingress) {
+			existing = append(existing, route)
+		}
+	}
+
+	if r.dryRunFor(&ingress) {
+		return ctrl.Result{}, r.planIngress(ctx, &ingress, routes, existing)
+	}
+
+	wanted := make(map[string]bool, len(routes))
+	var created []string
+	for _, route := range routes {
+		wanted[route.Name] = true
+		if !slices.ContainsFunc(existing, func(existing gatewayv1.HTTPRoute) bool { return existing.Name == route.Name }) {
+			created = append(created, route.Name)
+		}
+		if err := r.Patch(ctx, route, client.Apply, client.ForceOwnership, client.FieldOwner(fieldManager)); err != nil {
+			log.Error(err, "Failed to apply HTTPRoute", "httpRoute", route.Name)
+			return ctrl.Result{}, err
+		}
+		log.V(1).Info("Applied HTTPRoute for Ingress", "httpRoute", route.Name, "hostnames", route.Spec.Hostnames)
+	}
+	if len(created) > 0 {
+		log.Info("Adopted Ingress", "ingress", ingress.Name, "httpRoutes", created)
+		r.Recorder.Eventf(&ingress, corev1.EventTypeNormal, "IngressAdopted",
+			"Generated HTTPRoutes %s from the Ingress", strings.Join(created, ", "))
+	}
+
+	for i := range existing {
+		route := &existing[i]
+		if wanted[route.Name] {
+			continue
+		}
+		if err := r.Delete(ctx, route); client.IgnoreNotFound(err) != nil {
+			return ctrl.Result{}, err
+		}
+		log.Info("Deleted HTTPRoute of Ingress", "httpRoute", route.Name, "ingress", ingress.Name)
+	}
+	return ctrl.Result{}, nil
+}
+
+// planIngress reports the HTTPRoutes an Ingress would get applied and deleted in the log and a DryRun
+// event on the Ingress, given the existing routes generated from it. Nothing is written.
+func (r *IngressReconciler) planIngress(
+	ctx context.Context,
+	ingress *networkingv1.Ingress,
+	routes []*gatewayv1.HTTPRoute,
+	existing []gatewayv1.HTTPRoute,
+) error {
+	log := logf.FromContext(ctx)
+
+	applied := make([]string, 0, len(routes))
+	for _, route := range routes {
+		applied = append(applied, route.Name)
+	}
+	var deleted []string
+	for _, route := range existing {
+		if !slices.Contains(applied, route.Name) {
+			deleted = append(deleted, route.Name)
+		}
+	}
+	if len(applied) == 0 && len(deleted) == 0 {
+		return nil
+	}
+
+	log.Info("Dry run: planned HTTPRoute changes for Ingress", "ingress", ingress.Name, "apply", applied, "delete", deleted)
+	r.Recorder.Eventf(ingress, corev1.EventTypeNormal, "DryRun",
+		"Would apply HTTPRoutes [%s] and delete HTTPRoutes [%s]", strings.Join(applied, ", "), strings.Join(deleted, ", "))
+	return nil
+}
+
+// ingressRoutes converts an Ingress into HTTPRoutes: a route for every host of its rules, named
+// after the Ingress and the host, with a rule for every path. The default backend serves the
+// requests no path matches. Rules without a host end up in a route without hostnames named like
+// the Ingress. The routes reference the Gateway of the ingress-gateway annotation, or the
+// namespace's standard Gateway, and get the operator annotations of the Ingress, with the
+// cert-manager issuer annotations translated to the operator's.
+func (r *IngressReconciler) ingressRoutes(ctx context.Context, ingress *networkingv1.Ingress) ([]*gatewayv1.HTTPRoute, error) {
+	gatewayName, gatewayNamespace, exists := r.defaultGateway(ctx, ingress.Namespace)
+	if value := ingress.Annotations[AnnotationIngressGateway]; value != "" {
+		gatewayName, gatewayNamespace, exists = value, ingress.Namespace, true
+		if namespace, name, found := strings.Cut(value, "/"); found {
+			gatewayName, gatewayNamespace = name, namespace
+		}
+	}
+	if !exists {
+		return nil, errors.NewBadRequest(fmt.Sprintf("no Gateway for the HTTPRoutes, set the %s annotation or a default Gateway for the namespace",
+			AnnotationIngressGateway))
+	}
+	parentRef := gatewayv1.ParentReference{Name: gatewayv1.ObjectName(gatewayName)}
+	if gatewayNamespace != ingress.Namespace {
+		parentRef.Namespace = (*gatewayv1.Namespace)(&gatewayNamespace)
+	}
+
+	annotations := map[string]string{AnnotationUseHttprouteOperator: "true"}
+	for key, value := range ingress.Annotations {
+		if key == AnnotationAdoptIngress || key == AnnotationIngressGateway {
+			continue
+		}
+		if slices.ContainsFunc(ingressAnnotationPrefixes, func(prefix string) bool { return strings.HasPrefix(key, prefix) }) {
+			annotations[key] = value
+		}
+	}
+	if _, exists := annotations[AnnotationClusterIssuer]; !exists {
+		if name := ingress.Annotations[clusterIssuerAnnotation]; name != "" {
+			annotations[AnnotationClusterIssuer] = name
+		} else if name := ingress.Annotations[issuerAnnotation]; name != "" {
+			annotations[AnnotationClusterIssuer] = name
+			annotations[AnnotationIssuerKind] = issuerKindIssuer
+		}
+	}
+
+	var defaultRule *gatewayv1.HTTPRouteRule
+	if ingress.Spec.DefaultBackend != nil {
+		backendRef, err := r.ingressBackendRef(ctx, ingress.Namespace, *ingress.Spec.DefaultBackend)
+		if err != nil {
+			return nil, err
+		}
+		defaultRule = &gatewayv1.HTTPRouteRule{BackendRefs: []gatewayv1.HTTPBackendRef{backendRef}}
+	}
+
+	rules := make(map[string][]gatewayv1.HTTPRouteRule)
+	for _, ingressRule := range ingress.Spec.Rules {
+		if _, exists := rules[ingressRule.Host]; !exists {
+			rules[ingressRule.Host] = nil
+		}
+		if ingressRule.HTTP == nil {
+			continue
+		}
+		for _, path := range ingressRule.HTTP.Paths {
+			backendRef, err := r.ingressBackendRef(ctx, ingress.Namespace, path.Backend)
+			if err != nil {
+				return nil, err
+			}
+			pathType, value := gatewayv1.PathMatchPathPrefix, "/"
+			if path.PathType != nil && *path.PathType == networkingv1.PathTypeExact {
+				pathType = gatewayv1.PathMatchExact
+			}
+			if path.Path != "" {
+				value = path.Path
+			}
+			match := gatewayv1.HTTPPathMatch{Type: &pathType, Value: &value}
+			rules[ingressRule.Host] = append(rules[ingressRule.Host], gatewayv1.HTTPRouteRule{
+				Matches:     []gatewayv1.HTTPRouteMatch{{Path: &match}},
+				BackendRefs: []gatewayv1.HTTPBackendRef{backendRef},
+			})
+		}
+	}
+	if len(rules) == 0 && defaultRule != nil {
+		rules[""] = nil
+	}
+
+	routes := make([]*gatewayv1.HTTPRoute, 0, len(rules))
+	for _, host := range slices.Sorted(maps.Keys(rules)) {
+		hostRules := rules[host]
+		if defaultRule != nil {
+			hostRules = append(hostRules, *defaultRule)
+		}
+		if len(hostRules) == 0 {
+			continue
+		}
+		if len(hostRules) > maxHTTPRouteRules {
+			return nil, errors.NewBadRequest(fmt.Sprintf("host %q has %d paths, an HTTPRoute has room for %d rules",
+				host, len(hostRules), maxHTTPRouteRules))
+		}
+
+		name := ingress.Name
+		var hostnames []gatewayv1.Hostname
+		if host != "" {
+			hostname, _ := listenerHostname(host)
+			name += "-" + hostname
+			hostnames = []gatewayv1.Hostname{gatewayv1.Hostname(host)}
+		}
+		routes = append(routes, &gatewayv1.HTTPRoute{
+			TypeMeta: metav1.TypeMeta{
+				APIVersion: gatewayv1.GroupVersion.String(),
+				Kind:       "HTTPRoute",
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: ingress.Namespace,
+				Labels: map[string]string{
+					managedByLabel: managedByValue,
+					ingressLabel:   string(ingress.UID),
+				},
+				Annotations: annotations,
+				OwnerReferences: []metav1.OwnerReference{
+					*metav1.NewControllerRef(ingress, networkingv1.SchemeGroupVersion.WithKind("Ingress")),
+				},
+			},
+			Spec: gatewayv1.HTTPRouteSpec{
+				CommonRouteSpec: gatewayv1.CommonRouteSpec{
+					ParentRefs: []gatewayv1.ParentReference{parentRef},
+				},
+				Hostnames: hostnames,
+				Rules:     hostRules,
+			},
+		})
+	}
+	return routes, nil
+}
+
+// ingressBackendRef converts an Ingress backend into an HTTPRoute backend reference. Service ports
+// given by name are looked up in the Service, since backend references only take port numbers.
+// Services are read from the API server, so the operator doesn't cache all Services of the cluster.
+// Resource backends have no HTTPRoute equivalent.
+func (r *IngressReconciler) ingressBackendRef(ctx context.Context, namespace string, backend networkingv1.IngressBackend) (gatewayv1.HTTPBackendRef, error) {
+	if backend.Service == nil {
+		return gatewayv1.HTTPBackendRef{}, errors.NewBadRequest("resource backends are not supported, only Service backends")
+	}
+
+	port := gatewayv1.PortNumber(backend.Service.Port.Number)
+	if backend.Service.Port.Name != "" {
+		var service corev1.Service
+		if err := r.APIReader.Get(ctx, client.ObjectKey{Name: backend.Service.Name, Namespace: namespace}, &service); err != nil {
+			return gatewayv1.HTTPBackendRef{}, fmt.Errorf("failed to look up port %s of Service %s: %w",
+				backend.Service.Port.Name, backend.Service.Name, err)
+		}
+		index := slices.IndexFunc(service.Spec.Ports, func(servicePort corev1.ServicePort) bool {
+			return servicePort.Name == backend.Service.Port.Name
+		})
+		if index < 0 {
+			return gatewayv1.HTTPBackendRef{}, errors.NewBadRequest(fmt.Sprintf("Service %s has no port named %s",
+				backend.Service.Name, backend.Service.Port.Name))
+		}
+		port = gatewayv1.PortNumber(service.Spec.Ports[index].Port)
+	}
+
+	return gatewayv1.HTTPBackendRef{
+		BackendRef: gatewayv1.BackendRef{
+			BackendObjectReference: gatewayv1.BackendObjectReference{
+				Name: gatewayv1.ObjectName(backend.Service.Name),
+				Port: &port,
+			},
+		},
+	}, nil
+}
+
+// SetupWithManager sets up the controller with the Manager. The HTTPRoutes of an Ingress are
+// watched too, so routes changed or deleted by hand are repaired.
+func (r *IngressReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&networkingv1.Ingress{}).
+		Owns(&gatewayv1.HTTPRoute{}).
+		Named("ingress").
+		WithOptions(r.controllerOptions()).
+		Complete(r)
+}
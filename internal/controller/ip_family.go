@@ -0,0 +1,77 @@
+package controller
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// IPFamily selects the address families requested for a Gateway
+type IPFamily string
+
+const (
+	// IPFamilyIPv4 requests an IPv4 address only
+	IPFamilyIPv4 IPFamily = "IPv4"
+
+	// IPFamilyIPv6 requests an IPv6 address only
+	IPFamilyIPv6 IPFamily = "IPv6"
+
+	// IPFamilyDualStack requests both an IPv4 and an IPv6 address
+	IPFamilyDualStack IPFamily = "DualStack"
+)
+
+// IsValid reports whether the family is one of the known IP families
+func (f IPFamily) IsValid() bool {
+	switch f {
+	case IPFamilyIPv4, IPFamilyIPv6, IPFamilyDualStack:
+		return true
+	}
+	return false
+}
+
+// validateIPFamily checks that the IP family a route asks for is valid and supported by its IPAM
+// zone. A zone supports the families of its address ranges, zones without ranges are not checked.
+func (r *GatewayManager) validateIPFamily(settings gatewaySettings) error {
+	if !settings.IPFamily.IsValid() {
+		return errors.NewBadRequest(fmt.Sprintf("Route IP family '%s' is invalid, must be one of %s, %s or %s",
+			settings.IPFamily, IPFamilyIPv4, IPFamilyIPv6, IPFamilyDualStack))
+	}
+
+	ranges := r.ZoneAddressRanges[settings.IPAMZone]
+	if len(ranges) == 0 {
+		return nil
+	}
+	var hasIPv4, hasIPv6 bool
+	for _, prefix := range ranges {
+		if prefix.Addr().Is4() {
+			hasIPv4 = true
+		} else {
+			hasIPv6 = true
+		}
+	}
+
+	supported := true
+	switch settings.IPFamily {
+	case IPFamilyIPv4:
+		supported = hasIPv4
+	case IPFamilyIPv6:
+		supported = hasIPv6
+	case IPFamilyDualStack:
+		supported = hasIPv4 && hasIPv6
+	}
+	if !supported {
+		return errors.NewBadRequest(fmt.Sprintf("Route IP family '%s' is not supported by IPAM zone '%s'",
+			settings.IPFamily, settings.IPAMZone))
+	}
+	return nil
+}
+
+// gatewayIPFamily returns the IP family requested on the gateway's infrastructure, if any
+func gatewayIPFamily(gateway *gatewayv1.Gateway) (IPFamily, bool) {
+	if gateway.Spec.Infrastructure == nil {
+		return "", false
+	}
+	family, exists := gateway.Spec.Infrastructure.Annotations[ipFamilyInfrastructureAnnotation]
+	return IPFamily(family), exists
+}
@@ -0,0 +1,181 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// +kubebuilder:rbac:groups=ipam.cluster.x-k8s.io,resources=ipaddressclaims,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=ipam.cluster.x-k8s.io,resources=ipaddresses,verbs=get;list;watch
+
+// ipAddressClaimSuffix is the suffix of the name of the IPAddressClaim of a gateway's address
+const ipAddressClaimSuffix = "-address"
+
+// ipAddressClaimGatewayLabel holds the name of the gateway an IPAddressClaim is for, so claims made
+// for gateways that were never created can be released, see releaseOrphanedClaims
+const ipAddressClaimGatewayLabel = "gatewayapi-operator.vitistack.io/gateway"
+
+// addressRequeueInterval is how often a gateway waiting for its IPAM address is checked again
+const addressRequeueInterval = 10 * time.Second
+
+// errAddressPending tells that the IPAM provider hasn't allocated the address of a gateway yet
+var errAddressPending = errors.New("waiting for the IPAM address")
+
+// ipAddressClaimGVK and ipAddressGVK are the IPAM claim and the address allocated for it
+var (
+	ipAddressClaimGVK = schema.GroupVersionKind{
+		Group:   "ipam.cluster.x-k8s.io",
+		Version: "v1beta1",
+		Kind:    "IPAddressClaim",
+	}
+	ipAddressGVK = schema.GroupVersionKind{
+		Group:   "ipam.cluster.x-k8s.io",
+		Version: "v1beta1",
+		Kind:    "IPAddress",
+	}
+)
+
+// claimAddress claims an address for a gateway from the IPAM pool of its zone, and returns it once
+// the IPAM provider allocated it. Zones without a pool return no address. The IPAddressClaim is
+// owned by the gateway once it exists, so the address is released when the gateway is deleted;
+// pass a nil gateway while it is being created. While the address isn't allocated yet the error
+// is errAddressPending, which callers wait on rather than fail.
+func (r *GatewayManager) claimAddress(
+	ctx context.Context,
+	gatewayName, gatewayNamespace, zone string,
+	gateway *gatewayv1.Gateway,
+) (string, error) {
+	pool, exists := r.ZoneIPPools[zone]
+	if !exists {
+		return "", nil
+	}
+	name := gatewayName + ipAddressClaimSuffix
+
+	claim := &unstructured.Unstructured{}
+	claim.SetGroupVersionKind(ipAddressClaimGVK)
+	claim.SetName(name)
+	claim.SetNamespace(gatewayNamespace)
+	claim.SetLabels(map[string]string{managedByLabel: managedByValue, ipAddressClaimGatewayLabel: gatewayName})
+	if gateway != nil {
+		claim.SetOwnerReferences([]metav1.OwnerReference{
+			*metav1.NewControllerRef(gateway, gatewayv1.SchemeGroupVersion.WithKind("Gateway")),
+		})
+	}
+	poolRef := map[string]any{
+		"kind": pool.Kind,
+		"name": pool.Name,
+	}
+	if pool.APIGroup != nil {
+		poolRef["apiGroup"] = *pool.APIGroup
+	}
+	claim.Object["spec"] = map[string]any{"poolRef": poolRef}
+	if err := r.Patch(ctx, claim, client.Apply, client.ForceOwnership, client.FieldOwner(fieldManager)); err != nil {
+		return "", err
+	}
+
+	address, err := r.claimedAddress(ctx, claim)
+	if err != nil || address != "" {
+		return address, err
+	}
+	logf.FromContext(ctx).Info("Waiting for the IPAM address of Gateway", "gateway", gatewayName,
+		"namespace", gatewayNamespace, "zone", zone, "ipAddressClaim", name)
+	return "", fmt.Errorf("%w: IPAddressClaim %s/%s in zone %s has no address yet", errAddressPending, gatewayNamespace, name, zone)
+}
+
+// releaseOrphanedClaims deletes the IPAddressClaims made for gateways that don't exist and no
+// route references anymore, e.g. when the route was deleted while its gateway waited for the
+// address. Claims of existing gateways are owned by them and released with them.
+func (r *GatewayManager) releaseOrphanedClaims(ctx context.Context) error {
+	log := logf.FromContext(ctx)
+
+	var claims unstructured.UnstructuredList
+	claims.SetGroupVersionKind(ipAddressClaimGVK.GroupVersion().WithKind(ipAddressClaimGVK.Kind + "List"))
+	if err := r.List(ctx, &claims, client.MatchingLabels{managedByLabel: managedByValue}); err != nil {
+		return err
+	}
+	for i := range claims.Items {
+		claim := &claims.Items[i]
+		gatewayName := claim.GetLabels()[ipAddressClaimGatewayLabel]
+		if gatewayName == "" || metav1.GetControllerOf(claim) != nil || !r.gatewayInShard(gatewayName, claim.GetNamespace()) {
+			continue
+		}
+
+		var gateway gatewayv1.Gateway
+		if err := r.Get(ctx, client.ObjectKey{Name: gatewayName, Namespace: claim.GetNamespace()}, &gateway); client.IgnoreNotFound(err) != nil {
+			return err
+		} else if err == nil {
+			continue
+		}
+		owners, err := r.gatewayOwners(ctx, gatewayName, claim.GetNamespace())
+		if err != nil {
+			return err
+		}
+		if len(owners) > 0 {
+			continue
+		}
+
+		if r.DryRun {
+			log.Info("Dry run: would release IPAddressClaim of a Gateway that was never created", "ipAddressClaim", claim.GetName(),
+				"namespace", claim.GetNamespace(), "gateway", gatewayName)
+			continue
+		}
+		if err := r.Delete(ctx, claim); client.IgnoreNotFound(err) != nil {
+			return err
+		}
+		r.audit(ctx, auditActionDelete, ipAddressClaimGVK.Kind, claim)
+		log.Info("Released IPAddressClaim of a Gateway that was never created", "ipAddressClaim", claim.GetName(),
+			"namespace", claim.GetNamespace(), "gateway", gatewayName)
+	}
+	return nil
+}
+
+// claimedAddress returns the address allocated for an IPAddressClaim, or no address while the IPAM
+// provider hasn't allocated one
+func (r *GatewayManager) claimedAddress(ctx context.Context, claim *unstructured.Unstructured) (string, error) {
+	addressName, _, _ := unstructured.NestedString(claim.Object, "status", "addressRef", "name")
+	if addressName == "" {
+		return "", nil
+	}
+	address := &unstructured.Unstructured{}
+	address.SetGroupVersionKind(ipAddressGVK)
+	if err := r.Get(ctx, client.ObjectKey{Name: addressName, Namespace: claim.GetNamespace()}, address); err != nil {
+		return "", client.IgnoreNotFound(err)
+	}
+	value, _, _ := unstructured.NestedString(address.Object, "spec", "address")
+	return value, nil
+}
+
+// gatewayAddressClaim returns the IPAddressClaim of a gateway and the address allocated for it, for
+// the ManagedGateway status, or nothing for gateways without a claim
+func (r *GatewayManager) gatewayAddressClaim(ctx context.Context, gateway *gatewayv1.Gateway) (string, string, error) {
+	if len(r.ZoneIPPools) == 0 {
+		return "", "", nil
+	}
+	claim := &unstructured.Unstructured{}
+	claim.SetGroupVersionKind(ipAddressClaimGVK)
+	if err := r.Get(ctx, client.ObjectKey{Name: gateway.Name + ipAddressClaimSuffix, Namespace: gateway.Namespace}, claim); err != nil {
+		return "", "", client.IgnoreNotFound(err)
+	}
+	if !metav1.IsControlledBy(claim, gateway) {
+		return "", "", nil
+	}
+	address, err := r.claimedAddress(ctx, claim)
+	return claim.GetName(), address, err
+}
+
+// gatewayZone returns the IPAM zone of an existing gateway
+func gatewayZone(gateway *gatewayv1.Gateway) string {
+	if gateway.Spec.Infrastructure == nil {
+		return ""
+	}
+	return string(gateway.Spec.Infrastructure.Annotations[AnnotationIPAMZone])
+}
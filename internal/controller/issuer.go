@@ -0,0 +1,137 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// +kubebuilder:rbac:groups=cert-manager.io,resources=clusterissuers;issuers,verbs=get;list;watch
+
+const (
+	// issuerKindClusterIssuer is the kind of cluster wide cert-manager issuers
+	issuerKindClusterIssuer = "ClusterIssuer"
+
+	// issuerKindIssuer is the kind of namespaced cert-manager issuers
+	issuerKindIssuer = "Issuer"
+)
+
+// issuerRef refers to a cert-manager issuer of either kind
+type issuerRef struct {
+	Name string
+	Kind string
+}
+
+// String formats the issuer for logs, events and errors
+func (i issuerRef) String() string {
+	return i.Kind + " " + i.Name
+}
+
+// issuerAnnotationKey returns the gateway annotation holding its issuer. When the operator creates
+// the Certificates, cert-manager must not see its own annotations on the Gateway, or it would
+// request the same certificates.
+func (r *GatewayManager) issuerAnnotationKey(kind string) string {
+	switch {
+	case r.CertificateMode == CertificateModeCertificate:
+		return AnnotationClusterIssuer
+	case kind == issuerKindIssuer:
+		return issuerAnnotation
+	}
+	return clusterIssuerAnnotation
+}
+
+// setGatewayIssuer records the issuer in the gateway annotations. The kind is only recorded
+// separately when the annotation key doesn't tell it.
+func (r *GatewayManager) setGatewayIssuer(annotations map[string]string, issuer issuerRef) {
+	annotations[r.issuerAnnotationKey(issuer.Kind)] = issuer.Name
+	if r.CertificateMode == CertificateModeCertificate && issuer.Kind == issuerKindIssuer {
+		annotations[AnnotationIssuerKind] = issuerKindIssuer
+	}
+}
+
+// gatewayIssuer returns the issuer of a gateway, whichever certificate mode it was created in
+func gatewayIssuer(gateway *gatewayv1.Gateway) issuerRef {
+	if name, exists := gateway.Annotations[clusterIssuerAnnotation]; exists {
+		return issuerRef{Name: name, Kind: issuerKindClusterIssuer}
+	}
+	if name, exists := gateway.Annotations[issuerAnnotation]; exists {
+		return issuerRef{Name: name, Kind: issuerKindIssuer}
+	}
+	issuer := issuerRef{Name: gateway.Annotations[AnnotationClusterIssuer], Kind: issuerKindClusterIssuer}
+	if gateway.Annotations[AnnotationIssuerKind] == issuerKindIssuer {
+		issuer.Kind = issuerKindIssuer
+	}
+	return issuer
+}
+
+// routeIssuer returns the issuer a route asks for, falling back to the defaults of its namespace
+// and then to the global default
+func (r *GatewayManager) routeIssuer(ctx context.Context, route client.Object) issuerRef {
+	annotations := route.GetAnnotations()
+	defaults := r.namespaceDefaults(ctx, route.GetNamespace())
+
+	issuer := issuerRef{Name: annotations[AnnotationClusterIssuer], Kind: annotations[AnnotationIssuerKind]}
+	if profile := r.routeProfile(ctx, route); issuer.Name == "" && profile != nil && profile.Issuer != nil {
+		issuer = issuerRef{Name: profile.Issuer.Name, Kind: profile.Issuer.Kind}
+	}
+	if issuer.Name == "" {
+		issuer.Name = defaults[AnnotationClusterIssuer]
+	}
+	if issuer.Name == "" {
+		issuer.Name = r.config().ClusterIssuer
+	}
+	if issuer.Kind == "" {
+		issuer.Kind = defaults[AnnotationIssuerKind]
+	}
+	if issuer.Kind != issuerKindIssuer {
+		issuer.Kind = issuerKindClusterIssuer
+	}
+	return issuer
+}
+
+// ensureIssuerReady checks that the issuer a route asks for exists and is Ready, since the
+// listeners of its Gateway would never get certificates otherwise. Issuers are looked up in the
+// namespace the certificates are requested in. A warning event is emitted on the route otherwise,
+// and the returned error makes the route be retried with backoff. Clusters without the
+// cert-manager CRDs are not checked.
+func (r *GatewayManager) ensureIssuerReady(ctx context.Context, route client.Object, issuerRef issuerRef, namespace string) error {
+	log := logf.FromContext(ctx)
+
+	issuer := &unstructured.Unstructured{}
+	issuer.SetGroupVersionKind(certificateGVK.GroupVersion().WithKind(issuerRef.Kind))
+	key := client.ObjectKey{Name: issuerRef.Name}
+	if issuerRef.Kind == issuerKindIssuer {
+		key.Namespace = namespace
+	}
+	if err := r.Get(ctx, key, issuer); err != nil {
+		if meta.IsNoMatchError(err) {
+			log.V(1).Info("cert-manager CRDs are not installed, not checking the issuer", "issuer", issuerRef.String())
+			return nil
+		}
+		if client.IgnoreNotFound(err) != nil {
+			return err
+		}
+		log.Info("Issuer does not exist", "issuer", issuerRef.String(), "namespace", key.Namespace)
+		r.Recorder.Eventf(route, corev1.EventTypeWarning, issuerRef.Kind+"NotFound",
+			"%s does not exist, the route is retried once it exists", issuerRef)
+		return fmt.Errorf("%s does not exist", issuerRef)
+	}
+
+	conditions, _, _ := unstructured.NestedSlice(issuer.Object, "status", "conditions")
+	for _, item := range conditions {
+		condition, ok := item.(map[string]any)
+		if ok && condition["type"] == "Ready" && condition["status"] == "True" {
+			return nil
+		}
+	}
+	log.Info("Issuer is not ready", "issuer", issuerRef.String(), "namespace", key.Namespace)
+	r.Recorder.Eventf(route, corev1.EventTypeWarning, issuerRef.Kind+"NotReady",
+		"%s is not Ready, the route is retried once it is", issuerRef)
+	return fmt.Errorf("%s is not ready", issuerRef)
+}
@@ -0,0 +1,134 @@
+package controller
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// Annotations cert-manager records the issuer of a certificate secret with
+const (
+	secretIssuerNameAnnotation = "cert-manager.io/issuer-name"
+	secretIssuerKindAnnotation = "cert-manager.io/issuer-kind"
+)
+
+// issuerRotationAgreed reports whether every route owning the gateway that needs certificates asks
+// for the issuer, so the gateway's certificates can be reissued by it without leaving a route
+// behind with the issuer it asked for
+func (r *GatewayManager) issuerRotationAgreed(ctx context.Context, gateway *gatewayv1.Gateway, issuer issuerRef) (bool, error) {
+	routes, err := r.gatewayOwners(ctx, gateway.Name, gateway.Namespace)
+	if err != nil {
+		return false, err
+	}
+	agreed := false
+	for _, route := range routes {
+		if servesPlainHTTP(route.Object) {
+			continue
+		}
+		if r.routeIssuer(ctx, route.Object) != issuer {
+			return false, nil
+		}
+		agreed = true
+	}
+	return agreed, nil
+}
+
+// rotateGatewayIssuer switches the gateway to another issuer. cert-manager reissues the
+// certificates of its listeners with the new issuer, and the rotation is followed by
+// checkIssuerRotation until all of them were reissued.
+func (r *GatewayManager) rotateGatewayIssuer(ctx context.Context, route client.Object, gateway *gatewayv1.Gateway, issuer issuerRef) error {
+	log := logf.FromContext(ctx)
+	previous := gatewayIssuer(gateway)
+
+	patch := client.MergeFrom(gateway.DeepCopy())
+	delete(gateway.Annotations, clusterIssuerAnnotation)
+	delete(gateway.Annotations, issuerAnnotation)
+	r.setGatewayIssuer(gateway.Annotations, issuer)
+	gateway.Annotations[issuerRotationAnnotationKey] = previous.String()
+	if err := r.Patch(ctx, gateway, patch, client.FieldOwner(fieldManager)); err != nil {
+		return err
+	}
+	r.expectGateway(gateway)
+	r.audit(ctx, auditActionUpdate, "Gateway", gateway, "previousIssuer", previous.String(), "issuer", issuer.String())
+
+	log.Info("Rotating Gateway issuer", "gateway", gateway.Name, "namespace", gateway.Namespace,
+		"previousIssuer", previous.String(), "issuer", issuer.String())
+	r.Recorder.Eventf(gateway, corev1.EventTypeNormal, "IssuerRotationStarted",
+		"Rotating the issuer from %s to %s, all its routes ask for it, the certificates are reissued", previous, issuer)
+	r.Recorder.Eventf(route, corev1.EventTypeNormal, "IssuerRotationStarted",
+		"Rotating the issuer of Gateway %s/%s from %s to %s, the certificates are reissued", gateway.Namespace, gateway.Name,
+		previous, issuer)
+	return nil
+}
+
+// issuerRotationStatus is the progress of an issuer rotation reported for a Gateway
+type issuerRotationStatus struct {
+	issuer          issuerRef
+	reissued, total int
+}
+
+// checkIssuerRotation reports the progress of an issuer rotation of the gateway with an event when
+// it changes, and ends it once the certificate secrets of all its listeners were issued by the new
+// issuer. Secrets are read without the cache, since only their metadata is cached.
+func (r *GatewayManager) checkIssuerRotation(ctx context.Context, gateway *gatewayv1.Gateway) error {
+	previous, rotating := gateway.Annotations[issuerRotationAnnotationKey]
+	if !rotating {
+		return nil
+	}
+	log := logf.FromContext(ctx)
+	issuer := gatewayIssuer(gateway)
+
+	total, reissued := 0, 0
+	for _, listener := range gateway.Spec.Listeners {
+		if listener.TLS == nil || len(listener.TLS.CertificateRefs) == 0 {
+			continue
+		}
+		ref := listener.TLS.CertificateRefs[0]
+		key := client.ObjectKey{Name: string(ref.Name), Namespace: gateway.Namespace}
+		if ref.Namespace != nil {
+			key.Namespace = string(*ref.Namespace)
+		}
+		total++
+
+		var secret corev1.Secret
+		if err := r.APIReader.Get(ctx, key, &secret); err != nil {
+			if client.IgnoreNotFound(err) != nil {
+				return err
+			}
+			continue
+		}
+		if secret.Annotations[secretIssuerNameAnnotation] == issuer.Name && secret.Annotations[secretIssuerKindAnnotation] == issuer.Kind {
+			reissued++
+		}
+	}
+
+	key := client.ObjectKeyFromObject(gateway)
+	if reissued < total {
+		status := issuerRotationStatus{issuer: issuer, reissued: reissued, total: total}
+		if reported, exists := r.issuerRotationProgress.Swap(key, status); exists && reported == status {
+			log.V(1).Info("Waiting for certificates to be reissued", "gateway", gateway.Name, "issuer", issuer.String(),
+				"reissued", reissued, "total", total)
+			return nil
+		}
+		log.Info("Waiting for certificates to be reissued", "gateway", gateway.Name, "issuer", issuer.String(),
+			"reissued", reissued, "total", total)
+		r.Recorder.Eventf(gateway, corev1.EventTypeNormal, "IssuerRotationProgress",
+			"%d of %d certificates reissued by %s", reissued, total, issuer)
+		return nil
+	}
+	r.issuerRotationProgress.Delete(key)
+
+	patch := client.MergeFrom(gateway.DeepCopy())
+	delete(gateway.Annotations, issuerRotationAnnotationKey)
+	if err := r.Patch(ctx, gateway, patch, client.FieldOwner(fieldManager)); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	log.Info("Rotated Gateway issuer", "gateway", gateway.Name, "namespace", gateway.Namespace,
+		"previousIssuer", previous, "issuer", issuer.String())
+	r.Recorder.Eventf(gateway, corev1.EventTypeNormal, "IssuerRotated",
+		"All %d certificates were reissued by %s, replacing %s", total, issuer, previous)
+	return nil
+}
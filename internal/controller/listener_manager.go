@@ -2,97 +2,452 @@ package controller
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
 
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
 )
 
-// collectListenersForGateway gathers all hostnames from HTTPRoutes referencing the gateway
-// and creates HTTPS listeners for each hostname
-func (r *HTTPRouteReconciler) collectListenersForGateway(
+// routeContribution is a route referencing a gateway, with the names of the gateway's listeners
+// created for it
+type routeContribution struct {
+	routeInfo
+	Listeners []gatewayv1.SectionName
+}
+
+// collectListenersForGateway gathers all hostnames from routes referencing the gateway
+// and creates a listener for each hostname matching the kind of route requesting it.
+// It also returns the number of routes referencing the gateway, which can be non-zero
+// even without listeners when all routes attach to listeners the operator doesn't manage.
+// Routes in dry-run mode only contribute listeners when they are among the planned routes.
+func (r *GatewayManager) collectListenersForGateway(
 	ctx context.Context,
 	gatewayName, gatewayNamespace string,
-) ([]gatewayv1.Listener, error) {
+	planned ...client.Object,
+) ([]gatewayv1.Listener, int, error) {
+	listeners, contributions, err := r.collectRouteListeners(ctx, gatewayName, gatewayNamespace, planned...)
+	return listeners, len(contributions), err
+}
+
+// collectRouteListeners is collectListenersForGateway returning which listeners every route
+// referencing the gateway contributes. Hostname listeners served by a wildcard listener are
+// attributed to the wildcard listener.
+func (r *GatewayManager) collectRouteListeners(
+	ctx context.Context,
+	gatewayName, gatewayNamespace string,
+	planned ...client.Object,
+) ([]gatewayv1.Listener, []routeContribution, error) {
 	log := logf.FromContext(ctx)
 
-	// List all HTTPRoutes that reference this gateway
-	// Use a bypass cache to ensure we get the latest state and avoid race conditions
-	httpRouteList := &gatewayv1.HTTPRouteList{}
-	listOpts := []client.ListOption{}
-	// Bypass cache to get the most up-to-date list
-	if err := r.List(ctx, httpRouteList, listOpts...); err != nil {
-		return nil, err
+	// List all routes that reference this gateway
+	routes, err := r.listGatewayRoutes(ctx, gatewayName, gatewayNamespace)
+	if err != nil {
+		return nil, nil, err
 	}
 
-	// Collect unique hostnames from HTTPRoutes that reference this Gateway
-	hostnameSet := make(map[string]bool)
-	routeCount := 0
+	// Collect unique listeners from routes that reference this Gateway
+	listenerSet := make(map[gatewayv1.SectionName]gatewayv1.Listener)
+	pinned := make(map[gatewayv1.SectionName]bool)
+	tcpOwners := tcpPortOwners(routes, gatewayName, gatewayNamespace)
+	claimOwners, err := r.hostnameClaimOwners(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	overQuota := make(map[string]map[types.UID]int)
+	var contributions []routeContribution
 	skippedCount := 0
 
-	for _, route := range httpRouteList.Items {
-		// Skip routes being deleted or not enabled for the operator
-		if !route.DeletionTimestamp.IsZero() {
-			log.V(1).Info("Skipping route being deleted", "route", route.Name, "namespace", route.Namespace)
+	for _, route := range routes {
+		// Skip routes being deleted or not enabled for the operator. The deletion of a paused
+		// route is on hold, so it keeps its listeners until it is unpaused
+		if !route.GetDeletionTimestamp().IsZero() && !isPaused(route) {
+			log.V(1).Info("Skipping route being deleted", "kind", route.Kind, "route", route.GetName(), "namespace", route.GetNamespace())
 			skippedCount++
 			continue
 		}
-		if route.Annotations[AnnotationUseHttprouteOperator] != "true" {
+		if !route.Enabled {
+			skippedCount++
+			continue
+		}
+		if !r.DryRun && route.GetAnnotations()[AnnotationDryRun] == "true" && !slices.ContainsFunc(planned, func(obj client.Object) bool {
+			return obj.GetUID() == route.GetUID()
+		}) {
+			log.V(1).Info("Skipping route in dry-run mode", "kind", route.Kind, "route", route.GetName(), "namespace", route.GetNamespace())
 			skippedCount++
 			continue
 		}
 
 		// Check if this route references our gateway
-		for _, parentRef := range route.Spec.ParentRefs {
-			refName := string(parentRef.Name)
-			refNamespace := gatewayNamespace
-			if parentRef.Namespace != nil {
-				refNamespace = string(*parentRef.Namespace)
+		parentRefs := route.parentRefsFor(gatewayName, gatewayNamespace)
+		if len(parentRefs) == 0 {
+			continue
+		}
+
+		// Routes using an IPAM zone their namespace may not use get no listeners
+		if r.zoneRejection(ctx, route) != "" {
+			log.V(1).Info("Skipping route using a zone its namespace may not use", "kind", route.Kind, "route", route.GetName(), "namespace", route.GetNamespace())
+			skippedCount++
+			continue
+		}
+
+		// Routes exceeding the listener quota of their namespace get no listeners on shared gateways
+		if gatewayNamespace != route.GetNamespace() {
+			over, checked := overQuota[route.GetNamespace()]
+			if !checked {
+				if over, err = r.routesOverQuota(ctx, route.GetNamespace()); err != nil {
+					return nil, nil, err
+				}
+				overQuota[route.GetNamespace()] = over
+			}
+			if _, exceeded := over[route.GetUID()]; exceeded {
+				log.V(1).Info("Skipping route exceeding its namespace's listener quota", "kind", route.Kind, "route", route.GetName(), "namespace", route.GetNamespace())
+				skippedCount++
+				continue
 			}
+		}
+
+		// TCPRoutes only get a listener once they own their allocated port
+		if route.Kind == "TCPRoute" {
+			port, ok := tcpPortForRoute(route)
+			if !ok || tcpOwners[port] != client.ObjectKeyFromObject(route) {
+				log.V(1).Info("Skipping TCPRoute without an owned port", "route", route.GetName(), "namespace", route.GetNamespace())
+				skippedCount++
+				continue
+			}
+		}
 
-			if refName == gatewayName && refNamespace == gatewayNamespace {
-				routeCount++
-				// Collect all hostnames from this route
-				for _, hostname := range route.Spec.Hostnames {
-					hostnameSet[string(hostname)] = true
-					log.V(1).Info("Collected hostname", "hostname", hostname, "route", route.Name, "gateway", gatewayName)
+		route = withoutClaimedHostnames(r.withoutDisallowedHostnames(route), claimOwners)
+		contribution := routeContribution{routeInfo: route}
+		for _, parentRef := range parentRefs {
+			if parentRef.SectionName != nil {
+				pinned[*parentRef.SectionName] = true
+			}
+			for _, listener := range r.listenersForParentRef(ctx, route, parentRef, gatewayNamespace) {
+				if existing, exists := listenerSet[listener.Name]; exists {
+					listener = mergeAllowedNamespaces(existing, listener)
 				}
-				break
+				listenerSet[listener.Name] = listener
+				contribution.Listeners = append(contribution.Listeners, listener.Name)
+				log.V(1).Info("Collected listener", "listener", listener.Name, "kind", route.Kind, "route", route.GetName(), "gateway", gatewayName)
 			}
 		}
+		contributions = append(contributions, contribution)
 	}
 
-	// Create HTTPS listeners for all collected hostnames
-	listeners := make([]gatewayv1.Listener, 0, len(hostnameSet))
-	for hostname := range hostnameSet {
-		listener := r.createHTTPSListener(hostname, gatewayNamespace)
-		listeners = append(listeners, listener)
+	// TLS listeners can't share a hostname and port with an HTTPS listener, the HTTPS listener is kept
+	for _, name := range tlsListenerClashes(listenerSet) {
+		log.Info("Skipping TLS listener clashing with an HTTPS listener", "gateway", gatewayName, "listener", name)
+		delete(listenerSet, name)
+		for i := range contributions {
+			contributions[i].Listeners = slices.DeleteFunc(contributions[i].Listeners, func(listener gatewayv1.SectionName) bool {
+				return listener == name
+			})
+		}
+	}
+
+	// Drop hostname listeners already served by a wildcard listener and its certificate
+	collected := maps.Clone(listenerSet)
+	consolidateWildcardListeners(listenerSet, pinned)
+	for i := range contributions {
+		contributions[i].Listeners = consolidatedListenerNames(contributions[i].Listeners, collected, listenerSet)
 	}
 
+	// Sort the collected listeners by name so the listener order is stable
+	listeners := make([]gatewayv1.Listener, 0, len(listenerSet))
+	for _, name := range slices.Sorted(maps.Keys(listenerSet)) {
+		listeners = append(listeners, listenerSet[name])
+	}
+
+	// With the per-gateway certificate strategy all listeners share the gateway's certificate
+	r.useGatewaySecret(gatewayName, gatewayNamespace, listeners)
+
 	log.Info("Collected listeners for Gateway",
 		"gateway", gatewayName,
 		"listeners", len(listeners),
-		"activeRoutes", routeCount,
+		"activeRoutes", len(contributions),
 		"skippedRoutes", skippedCount,
-		"totalRoutes", len(httpRouteList.Items))
-	return listeners, nil
+		"totalRoutes", len(routes))
+	return listeners, contributions, nil
 }
 
-// createHTTPSListener creates an HTTPS listener for a hostname with TLS configuration
-func (r *HTTPRouteReconciler) createHTTPSListener(
-	hostname string,
+// consolidatedListenerNames maps the names of collected listeners to the listeners left after
+// wildcard consolidation, replacing hostname listeners by the wildcard listener serving them
+func consolidatedListenerNames(
+	names []gatewayv1.SectionName,
+	collected, consolidated map[gatewayv1.SectionName]gatewayv1.Listener,
+) []gatewayv1.SectionName {
+	var result []gatewayv1.SectionName
+	for _, name := range names {
+		if _, kept := consolidated[name]; !kept {
+			listener := collected[name]
+			for wildcardName, wildcard := range consolidated {
+				if listener.Hostname != nil && wildcard.Hostname != nil && wildcard.Protocol == listener.Protocol &&
+					wildcard.Port == listener.Port && wildcardCovers(string(*wildcard.Hostname), string(*listener.Hostname)) {
+					name = wildcardName
+					break
+				}
+			}
+		}
+		if !slices.Contains(result, name) {
+			result = append(result, name)
+		}
+	}
+	return result
+}
+
+// listenersForRoute creates the listeners a route needs. TLSRoutes get a TLS listener and
+// HTTPRoutes and GRPCRoutes an HTTPS listener per hostname, or a plain HTTP listener with the
+// protocol annotation, TCPRoutes a TCP listener on their port.
+func (r *GatewayManager) listenersForRoute(
+	ctx context.Context,
+	route routeInfo,
 	gatewayNamespace string,
-) gatewayv1.Listener {
-	// Use hostname as the listener section name
-	listenerName := gatewayv1.SectionName(hostname)
+) []gatewayv1.Listener {
+	if route.Kind == "TCPRoute" {
+		port, ok := tcpPortForRoute(route)
+		if !ok {
+			return nil
+		}
+		return []gatewayv1.Listener{r.createTCPListener(port)}
+	}
+
+	// Routes without hostnames can opt in to a catch-all listener, represented by an empty hostname
+	hostnames := route.Hostnames
+	if len(hostnames) == 0 && route.GetAnnotations()[AnnotationHostnameFallback] == hostnameFallbackWildcard {
+		hostnames = []gatewayv1.Hostname{""}
+	}
+
+	shared := r.isSharedGateway(gatewayNamespace)
+	certNamespace := r.routeSecretNamespace(ctx, route, gatewayNamespace)
+
+	// Hostnames under the wildcard domain of the route's zone share its wildcard listener
+	if domain := r.ZoneWildcardDomains[r.routeIPAMZone(ctx, route)]; domain != "" {
+		wildcard := gatewayv1.Hostname("*." + domain)
+		consolidated := make([]gatewayv1.Hostname, 0, len(hostnames))
+		for _, hostname := range hostnames {
+			if wildcardCovers(string(wildcard), string(hostname)) {
+				hostname = wildcard
+			}
+			if !slices.Contains(consolidated, hostname) {
+				consolidated = append(consolidated, hostname)
+			}
+		}
+		hostnames = consolidated
+	}
+
+	tlsOptions := r.tlsOptionsFor(ctx, route)
+	listeners := make([]gatewayv1.Listener, 0, len(hostnames))
+	for _, hostname := range hostnames {
+		secretName := r.tlsSecretName(string(hostname))
+		if existing := route.GetAnnotations()[AnnotationTLSSecretName]; existing != "" {
+			secretName = existing
+		}
+
+		var listener gatewayv1.Listener
+		switch {
+		case route.Kind == "TLSRoute":
+			mode := gatewayv1.TLSModeTerminate
+			if route.GetAnnotations()[AnnotationTLSMode] == tlsModePassthrough {
+				mode = gatewayv1.TLSModePassthrough
+			}
+			listener = r.createTLSListener(string(hostname), certNamespace, secretName, mode)
+		case servesPlainHTTP(route.Object):
+			listener = r.createHTTPListener(string(hostname))
+		default:
+			listener = r.createHTTPSListener(string(hostname), certNamespace, secretName)
+		}
+		if listener.TLS != nil {
+			listener.TLS.Options = tlsOptions
+		}
+		if port, ok, err := listenerPortForRoute(route); ok && err == nil && port != listener.Port {
+			listener.Port = port
+			listener.Name = r.sectionName(fmt.Sprintf("%s-%d", listener.Name, port))
+		}
+
+		// Listeners on the shared gateway only admit routes from the namespaces requesting them
+		if shared {
+			listener.AllowedRoutes = namespaceAllowedRoutes(route.GetNamespace())
+		}
+		listeners = append(listeners, listener)
+	}
+	return listeners
+}
+
+// namespaceAllowedRoutes restricts a listener to routes from the given namespaces
+func namespaceAllowedRoutes(namespaces ...string) *gatewayv1.AllowedRoutes {
+	fromSelector := gatewayv1.NamespacesFromSelector
+	return &gatewayv1.AllowedRoutes{
+		Namespaces: &gatewayv1.RouteNamespaces{
+			From: &fromSelector,
+			Selector: &metav1.LabelSelector{
+				MatchExpressions: []metav1.LabelSelectorRequirement{
+					{
+						Key:      corev1.LabelMetadataName,
+						Operator: metav1.LabelSelectorOpIn,
+						Values:   namespaces,
+					},
+				},
+			},
+		},
+	}
+}
+
+// mergeAllowedNamespaces returns the listener with the namespaces allowed by an existing listener
+// of the same name added to its namespace selector, so every requesting namespace is admitted, and
+// with the certificate references of both, since each namespace brings its own certificate
+func mergeAllowedNamespaces(existing, listener gatewayv1.Listener) gatewayv1.Listener {
+	existingNamespaces := selectedNamespaces(existing.AllowedRoutes)
+	namespaces := selectedNamespaces(listener.AllowedRoutes)
+	if existingNamespaces == nil || namespaces == nil {
+		return listener
+	}
+
+	for _, namespace := range existingNamespaces {
+		if !slices.Contains(namespaces, namespace) {
+			namespaces = append(namespaces, namespace)
+		}
+	}
+	slices.Sort(namespaces)
+
+	// Keep the existing listener so its first certificate reference is stable
+	existing.AllowedRoutes = namespaceAllowedRoutes(namespaces...)
+	if existing.TLS != nil && listener.TLS != nil {
+		tls := existing.TLS.DeepCopy()
+		for _, ref := range listener.TLS.CertificateRefs {
+			if !slices.ContainsFunc(tls.CertificateRefs, func(existing gatewayv1.SecretObjectReference) bool {
+				return reflect.DeepEqual(existing, ref)
+			}) {
+				tls.CertificateRefs = append(tls.CertificateRefs, ref)
+			}
+		}
+		existing.TLS = tls
+	}
+	return existing
+}
+
+// selectedNamespaces returns the namespaces selected by name in allowed routes, or nil if
+// the allowed routes don't select namespaces by name
+func selectedNamespaces(allowedRoutes *gatewayv1.AllowedRoutes) []string {
+	if allowedRoutes == nil || allowedRoutes.Namespaces == nil || allowedRoutes.Namespaces.Selector == nil {
+		return nil
+	}
+	for _, expression := range allowedRoutes.Namespaces.Selector.MatchExpressions {
+		if expression.Key == corev1.LabelMetadataName && expression.Operator == metav1.LabelSelectorOpIn {
+			return slices.Clone(expression.Values)
+		}
+	}
+	return nil
+}
+
+// consolidateWildcardListeners removes listeners whose hostname is covered by a wildcard
+// listener with the same protocol, port and TLS mode, so the wildcard listener and its
+// certificate are reused. Listeners pinned by a parentRef section name are kept.
+func consolidateWildcardListeners(listenerSet map[gatewayv1.SectionName]gatewayv1.Listener, pinned map[gatewayv1.SectionName]bool) {
+	var wildcards []gatewayv1.Listener
+	for _, listener := range listenerSet {
+		if listener.Hostname != nil && strings.HasPrefix(string(*listener.Hostname), "*.") {
+			wildcards = append(wildcards, listener)
+		}
+	}
+
+	for name, listener := range listenerSet {
+		if listener.Hostname == nil || pinned[name] {
+			continue
+		}
+		for _, wildcard := range wildcards {
+			if wildcard.Protocol != listener.Protocol || wildcard.Port != listener.Port || !sameTLSMode(wildcard.TLS, listener.TLS) {
+				continue
+			}
+			if wildcardCovers(string(*wildcard.Hostname), string(*listener.Hostname)) {
+				delete(listenerSet, name)
+				break
+			}
+		}
+	}
+}
+
+// sameTLSMode reports whether two listener TLS configs use the same TLS mode
+func sameTLSMode(a, b *gatewayv1.GatewayTLSConfig) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	if a.Mode == nil || b.Mode == nil {
+		return a.Mode == b.Mode
+	}
+	return *a.Mode == *b.Mode
+}
+
+// listenersForParentRef creates the listeners a route needs for one of its parent references.
+// A port on the parent reference moves hostname listeners to that port, and a section name
+// limits the result to the listener with that name. A section name the operator doesn't
+// generate refers to a listener managed outside the operator, so nothing is created for it.
+func (r *GatewayManager) listenersForParentRef(
+	ctx context.Context,
+	route routeInfo,
+	parentRef gatewayv1.ParentReference,
+	gatewayNamespace string,
+) []gatewayv1.Listener {
+	listeners := r.listenersForRoute(ctx, route, gatewayNamespace)
+
+	result := make([]gatewayv1.Listener, 0, len(listeners))
+	for _, listener := range listeners {
+		if parentRef.Port != nil && *parentRef.Port != listener.Port {
+			// TCP listeners are bound to their allocated port
+			if listener.Protocol == gatewayv1.TCPProtocolType {
+				continue
+			}
+			listener.Port = *parentRef.Port
+			listener.Name = r.sectionName(fmt.Sprintf("%s-%d", listener.Name, listener.Port))
+		}
+		// Section names of listeners renamed by sanitized naming still match until they are migrated
+		if parentRef.SectionName != nil && *parentRef.SectionName != listener.Name && r.sectionName(string(*parentRef.SectionName)) != listener.Name {
+			continue
+		}
+		result = append(result, listener)
+	}
+	return result
+}
+
+// listenerHostname returns the section name base and hostname for a listener.
+// An empty hostname creates a catch-all listener without a hostname. Section names can't
+// contain "*", so wildcard hostnames like *.apps.example.com are named wildcard.apps.example.com.
+func listenerHostname(hostname string) (string, *gatewayv1.Hostname) {
+	if hostname == "" {
+		return wildcardListenerName, nil
+	}
 	hn := gatewayv1.Hostname(hostname)
+	if domain, ok := strings.CutPrefix(hostname, "*."); ok {
+		return wildcardListenerName + "." + domain, &hn
+	}
+	return hostname, &hn
+}
 
-	// Construct TLS certificate secret name
-	certSecretName := hostname + tlsCertSuffix
+// tlsSecretName returns the name of the certificate secret for a listener hostname from the TLS
+// secret template. Listeners without a hostname use the secret of the wildcard listener.
+func (r *GatewayManager) tlsSecretName(hostname string) string {
+	name, _ := listenerHostname(hostname)
+	return strings.ReplaceAll(r.config().TLSSecretTemplate, hostnameTemplatePlaceholder, name)
+}
 
-	// Certificate is in the gateway's namespace
-	certNamespace := gatewayv1.Namespace(gatewayNamespace)
+// createHTTPSListener creates an HTTPS listener for a hostname with TLS configuration
+func (r *GatewayManager) createHTTPSListener(
+	hostname string,
+	certNamespace string,
+	certSecretName string,
+) gatewayv1.Listener {
+	// Use hostname as the listener section name
+	name, hn := listenerHostname(hostname)
+	listenerName := r.sectionName(name)
 
 	terminate := gatewayv1.TLSModeTerminate
 	fromAll := gatewayv1.NamespacesFromAll
@@ -100,8 +455,8 @@ func (r *HTTPRouteReconciler) createHTTPSListener(
 	return gatewayv1.Listener{
 		Name:     listenerName,
 		Protocol: gatewayv1.HTTPSProtocolType,
-		Port:     httpsPort,
-		Hostname: &hn,
+		Port:     r.config().HTTPSPort,
+		Hostname: hn,
 		AllowedRoutes: &gatewayv1.AllowedRoutes{
 			Namespaces: &gatewayv1.RouteNamespaces{
 				From: &fromAll,
@@ -114,61 +469,266 @@ func (r *HTTPRouteReconciler) createHTTPSListener(
 					Group:     (*gatewayv1.Group)(ptr("")),
 					Kind:      (*gatewayv1.Kind)(ptr("Secret")),
 					Name:      gatewayv1.ObjectName(certSecretName),
-					Namespace: &certNamespace,
+					Namespace: (*gatewayv1.Namespace)(&certNamespace),
 				},
 			},
 		},
 	}
 }
 
-// updateGatewayListeners updates the gateway's listeners based on all HTTPRoutes referencing it
-func (r *HTTPRouteReconciler) updateGatewayListeners(
+// servesPlainHTTP reports whether a route asks for plain HTTP listeners with the protocol
+// annotation. Only HTTPRoutes and GRPCRoutes can be served over plain HTTP.
+func servesPlainHTTP(route client.Object) bool {
+	switch route.(type) {
+	case *gatewayv1.HTTPRoute, *gatewayv1.GRPCRoute:
+		return route.GetAnnotations()[AnnotationProtocol] == protocolHTTP
+	}
+	return false
+}
+
+// createHTTPListener creates a plain HTTP listener for a hostname, for routes that don't need TLS
+func (r *GatewayManager) createHTTPListener(hostname string) gatewayv1.Listener {
+	// Prefix the section name so it doesn't collide with an HTTPS listener for the same hostname
+	name, hn := listenerHostname(hostname)
+	listenerName := r.sectionName(httpListenerPrefix + name)
+	fromAll := gatewayv1.NamespacesFromAll
+
+	return gatewayv1.Listener{
+		Name:     listenerName,
+		Protocol: gatewayv1.HTTPProtocolType,
+		Port:     r.config().HTTPPort,
+		Hostname: hn,
+		AllowedRoutes: &gatewayv1.AllowedRoutes{
+			Namespaces: &gatewayv1.RouteNamespaces{
+				From: &fromAll,
+			},
+		},
+	}
+}
+
+// createTLSListener creates a TLS listener for a hostname. In passthrough mode the TLS
+// connection is forwarded to the backend untouched and no certificate is referenced.
+func (r *GatewayManager) createTLSListener(
+	hostname string,
+	certNamespace string,
+	certSecretName string,
+	mode gatewayv1.TLSModeType,
+) gatewayv1.Listener {
+	// Prefix the section name so it doesn't collide with an HTTPS listener for the same hostname
+	name, hn := listenerHostname(hostname)
+	listenerName := r.sectionName(tlsListenerPrefix + name)
+	fromAll := gatewayv1.NamespacesFromAll
+
+	tlsConfig := &gatewayv1.GatewayTLSConfig{
+		Mode: &mode,
+	}
+	if mode == gatewayv1.TLSModeTerminate {
+		tlsConfig.CertificateRefs = []gatewayv1.SecretObjectReference{
+			{
+				Group:     (*gatewayv1.Group)(ptr("")),
+				Kind:      (*gatewayv1.Kind)(ptr("Secret")),
+				Name:      gatewayv1.ObjectName(certSecretName),
+				Namespace: (*gatewayv1.Namespace)(&certNamespace),
+			},
+		}
+	}
+
+	return gatewayv1.Listener{
+		Name:     listenerName,
+		Protocol: gatewayv1.TLSProtocolType,
+		Port:     r.config().HTTPSPort,
+		Hostname: hn,
+		AllowedRoutes: &gatewayv1.AllowedRoutes{
+			Namespaces: &gatewayv1.RouteNamespaces{
+				From: &fromAll,
+			},
+		},
+		TLS: tlsConfig,
+	}
+}
+
+// createTCPListener creates a TCP listener on a port allocated to a TCPRoute
+func (r *GatewayManager) createTCPListener(port gatewayv1.PortNumber) gatewayv1.Listener {
+	fromAll := gatewayv1.NamespacesFromAll
+
+	return gatewayv1.Listener{
+		Name:     gatewayv1.SectionName(fmt.Sprintf("%s%d", tcpListenerPrefix, port)),
+		Protocol: gatewayv1.TCPProtocolType,
+		Port:     port,
+		AllowedRoutes: &gatewayv1.AllowedRoutes{
+			Namespaces: &gatewayv1.RouteNamespaces{
+				From: &fromAll,
+			},
+		},
+	}
+}
+
+// updateGatewayListeners updates the gateway's listeners based on all routes referencing it.
+// When no routes reference the gateway anymore its deletion policy is applied, and the returned
+// duration tells when to check again for a gateway waiting for its deletion TTL.
+func (r *GatewayManager) updateGatewayListeners(
 	ctx context.Context,
 	gateway *gatewayv1.Gateway,
 	gatewayNamespace string,
-) error {
+) (time.Duration, error) {
 	log := logf.FromContext(ctx)
 
 	gatewayName := gateway.Name
 
-	// Collect listeners from all HTTPRoutes referencing this gateway
-	newListeners, err := r.collectListenersForGateway(ctx, gatewayName, gatewayNamespace)
+	// Never change or delete a Gateway the operator doesn't manage
+	if !isManagedGateway(gateway) {
+		log.Info("Gateway is not managed by the operator, skipping update", "gateway", gatewayName, "namespace", gatewayNamespace)
+		return 0, nil
+	}
+	if isPaused(gateway) {
+		log.Info("Gateway reconciliation is paused, skipping update", "gateway", gatewayName, "namespace", gatewayNamespace)
+		return 0, nil
+	}
+
+	// Collect listeners from all routes referencing this gateway
+	newListeners, routeCount, err := r.collectListenersForGateway(ctx, gatewayName, gatewayNamespace)
+	if err != nil {
+		return 0, err
+	}
+
+	// Listeners managed by other tools or users stay as they are, the operator doesn't
+	// take over or duplicate them
+	newListeners, dropped := withoutForeignConflicts(newListeners, foreignListeners(gateway))
+	if len(dropped) > 0 {
+		log.Info("Skipping listeners that conflict with listeners managed outside the operator",
+			"gateway", gatewayName, "namespace", gatewayNamespace, "listeners", dropped)
+	}
+
+	// Allow the gateway to reference certificates in other namespaces, and remove
+	// grants no longer needed
+	if err := r.syncSecretReferenceGrants(ctx, gatewayName, gatewayNamespace, newListeners); err != nil {
+		return 0, err
+	}
+
+	// Request the certificates of the listeners, and remove Certificates no longer needed
+	if err := r.syncCertificates(ctx, gatewayName, gatewayNamespace, gatewayIssuer(gateway), newListeners); err != nil {
+		return 0, err
+	}
+
+	// If no routes reference the gateway anymore, apply its deletion policy
+	if routeCount == 0 {
+		return r.handleEmptyGateway(ctx, gateway)
+	}
+	if err := r.clearGatewayEmpty(ctx, gateway); err != nil {
+		return 0, err
+	}
+
+	// HTTP-01 challenges need their listeners before the certificates can be issued
+	http01, err := r.http01Listeners(ctx, gatewayName, gatewayNamespace, gatewayIssuer(gateway), newListeners)
 	if err != nil {
-		return err
+		return 0, err
+	}
+
+	// New listeners wait for their certificate, check again until it is issued
+	var requeueAfter time.Duration
+	current, err := r.currentListeners(ctx, gateway)
+	if err != nil {
+		return 0, err
+	}
+	newListeners, pending, err := r.withReadyCertificates(ctx, gatewayName, gatewayNamespace, current, newListeners)
+	if err != nil {
+		return 0, err
+	}
+	if len(pending) > 0 {
+		log.Info("Waiting for certificates before adding listeners", "gateway", gatewayName, "namespace", gatewayNamespace, "listeners", pending)
+		r.Recorder.Eventf(gateway, corev1.EventTypeNormal, "CertificatePending",
+			"Waiting for the certificates of listeners %v to be Ready before adding them", pending)
+		requeueAfter = certificateRequeueInterval
+	}
+	newListeners, httpListeners := r.withHTTPListeners(gatewayNamespace, newListeners, http01)
+	if r.exceedsListenerLimit(ctx, gateway, gatewayName, gatewayNamespace, newListeners) {
+		return requeueAfter, nil
 	}
 
-	// If no listeners remain, delete the gateway
+	// Routes only attach to listeners managed outside the operator, leave the listeners alone
 	if len(newListeners) == 0 {
-		log.Info("No HTTPRoutes reference this gateway anymore, deleting it", "gateway", gatewayName, "namespace", gateway.Namespace)
-		if err := r.Delete(ctx, gateway); err != nil {
-			return err
+		log.Info("No operator managed listeners for gateway, skipping update", "gateway", gatewayName, "routes", routeCount)
+		return requeueAfter, nil
+	}
+
+	if listenersDrifted(gateway, newListeners) {
+		log.Info("Gateway listeners differ from the routes referencing it, updating them", "gateway", gatewayName, "namespace", gatewayNamespace)
+	}
+
+	// Labels and annotations propagated from the routes
+	labels, annotations, err := r.propagatedMetadata(ctx, gatewayName, gatewayNamespace)
+	if err != nil {
+		return 0, err
+	}
+
+	// Use Server-Side Apply to update listeners. Listeners are a map keyed by name, so the apply
+	// only adds, changes and removes the listeners owned by the operator's field manager.
+	patch := r.gatewayForApply(gateway, newListeners)
+	setPropagatedMetadata(patch, labels, annotations)
+	infraLabels, err := r.infrastructureLabels(ctx, gatewayName, gatewayNamespace)
+	if err != nil {
+		return 0, err
+	}
+	setInfrastructureLabels(patch, infraLabels)
+	envoyProxy, err := r.envoyProxySpec(ctx, patch)
+	if err != nil {
+		return 0, err
+	}
+	setEnvoyProxyRef(patch, envoyProxy)
+	address, err := r.requestedAddress(ctx, gatewayName, gatewayNamespace)
+	if err != nil {
+		return 0, err
+	}
+	if address == "" {
+		// Listeners are updated while the IPAM provider allocates the address, which is set once it is
+		if address, err = r.claimAddress(ctx, gatewayName, gatewayNamespace, gatewayZone(gateway), gateway); errors.Is(err, errAddressPending) {
+			if requeueAfter == 0 || requeueAfter > addressRequeueInterval {
+				requeueAfter = addressRequeueInterval
+			}
+		} else if err != nil {
+			return 0, err
 		}
-		log.Info("Deleted gateway", "gateway", gatewayName)
-		return nil
 	}
+	setGatewayAddress(patch, address)
 
-	// Use Server-Side Apply to update listeners
-	// Include gatewayClassName since it's a required field, but we take it from the existing gateway
-	patch := &gatewayv1.Gateway{
-		TypeMeta: metav1.TypeMeta{
-			APIVersion: "gateway.networking.k8s.io/v1",
-			Kind:       "Gateway",
-		},
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      gatewayName,
-			Namespace: gatewayNamespace,
-		},
-		Spec: gatewayv1.GatewaySpec{
-			GatewayClassName: gateway.Spec.GatewayClassName,
-			Listeners:        newListeners,
-		},
+	if r.EnableListenerSets {
+		if err := r.applyGatewayListenerSets(ctx, gateway, patch, newListeners); err != nil {
+			return 0, err
+		}
+		return requeueAfter, r.syncRedirectRoute(ctx, gateway, newListeners, httpListeners)
+	}
+
+	// Nothing to apply when the operator's listeners and metadata are already in place, e.g. when
+	// a route changed without changing its hostnames
+	if appliedUnchanged(gateway, patch) {
+		log.V(1).Info("Gateway is up to date, skipping apply", "gateway", gatewayName, "namespace", gatewayNamespace)
+		return requeueAfter, r.syncRedirectRoute(ctx, gateway, newListeners, httpListeners)
 	}
 
-	err = r.Patch(ctx, patch, client.Apply, client.ForceOwnership, client.FieldOwner("gatewayapi-operator"))
+	added, changed, removed := listenerChanges(gateway, newListeners)
+	r.checkListenerDNS(ctx, gateway, added)
+	err = r.Patch(ctx, patch, client.Apply, client.ForceOwnership, client.FieldOwner(fieldManager))
 	if err != nil {
-		return err
+		return 0, err
+	}
+	r.expectGateway(patch)
+	if patch.ResourceVersion != gateway.ResourceVersion {
+		r.audit(ctx, auditActionUpdate, "Gateway", patch,
+			"added", listenerNames(added), "changed", listenerNames(changed), "removed", listenerNames(removed))
+	}
+	if len(added) > 0 {
+		r.Recorder.Eventf(gateway, corev1.EventTypeNormal, "ListenersAdded", "Added listeners %s", listenerNames(added))
+	}
+	if len(changed) > 0 {
+		r.Recorder.Eventf(gateway, corev1.EventTypeNormal, "ListenersUpdated", "Updated listeners %s", listenerNames(changed))
+	}
+	if len(removed) > 0 {
+		r.Recorder.Eventf(gateway, corev1.EventTypeNormal, "ListenersRemoved", "Removed listeners %s", listenerNames(removed))
+	}
+	if err := r.syncRedirectRoute(ctx, gateway, newListeners, httpListeners); err != nil {
+		return 0, err
 	}
 
 	log.Info("Updated Gateway listeners", "gateway", gatewayName, "listeners", len(newListeners))
-	return nil
+	return requeueAfter, nil
 }
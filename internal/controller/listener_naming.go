@@ -0,0 +1,144 @@
+package controller
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"slices"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// ListenerNaming selects how listener section names are derived from hostnames
+type ListenerNaming string
+
+const (
+	// ListenerNamingHostname names listeners after their hostname as is
+	ListenerNamingHostname ListenerNaming = "hostname"
+
+	// ListenerNamingSanitized sanitizes listener names into valid DNS labels of at most
+	// maxSectionNameLength characters, shortening longer names with a hash suffix
+	ListenerNamingSanitized ListenerNaming = "sanitized"
+)
+
+// maxSectionNameLength is the length sanitized listener names are kept within. Gateway API allows
+// longer section names, but Gateway implementations derive resource names and labels from them.
+const maxSectionNameLength = 63
+
+// sectionNameHashLength is the number of hex characters of the hash suffix of shortened names
+const sectionNameHashLength = 8
+
+// IsValid reports whether the naming is one of the known listener namings
+func (n ListenerNaming) IsValid() bool {
+	switch n {
+	case ListenerNamingHostname, ListenerNamingSanitized:
+		return true
+	}
+	return false
+}
+
+// sectionName returns the section name of a listener from its name base, e.g. a hostname with a
+// listener prefix or port suffix. With sanitized naming characters a section name can't have are
+// replaced with dashes, and names that had to be changed or are too long are cut short and get a
+// hash of the original name as suffix, so different names don't collide and a name always maps to
+// the same section name. Names that are already valid and short enough are kept.
+func (r *GatewayManager) sectionName(name string) gatewayv1.SectionName {
+	if r.ListenerNaming != ListenerNamingSanitized {
+		return gatewayv1.SectionName(name)
+	}
+
+	sanitized := strings.Map(func(c rune) rune {
+		if c >= 'a' && c <= 'z' || c >= '0' && c <= '9' || c == '-' || c == '.' {
+			return c
+		}
+		return '-'
+	}, strings.ToLower(name))
+	sanitized = strings.Trim(sanitized, "-.")
+	if sanitized == name && len(sanitized) <= maxSectionNameLength {
+		return gatewayv1.SectionName(name)
+	}
+
+	hash := sha256.Sum256([]byte(name))
+	suffix := hex.EncodeToString(hash[:])[:sectionNameHashLength]
+	if maxLength := maxSectionNameLength - len(suffix) - 1; len(sanitized) > maxLength {
+		sanitized = sanitized[:maxLength]
+	}
+	sanitized = strings.TrimRight(sanitized, "-.")
+	if sanitized == "" {
+		return gatewayv1.SectionName(suffix)
+	}
+	return gatewayv1.SectionName(sanitized + "-" + suffix)
+}
+
+// ensureSectionNames migrates the section names of a route's parent references to sanitized
+// naming. Section names of the operator's listeners that sanitizing changes are rewritten to the
+// new listener names, so the route stays attached when its listener is renamed. Section names of
+// listeners managed by others are left alone. It returns true when the route may be reconciled as
+// is, and false when its parent references were patched, which triggers a new reconciliation.
+func (r *GatewayManager) ensureSectionNames(
+	ctx context.Context,
+	route client.Object,
+	parentRefs []gatewayv1.ParentReference,
+) (bool, error) {
+	if r.ListenerNaming != ListenerNamingSanitized {
+		return true, nil
+	}
+	log := logf.FromContext(ctx)
+
+	migrated := make([]gatewayv1.ParentReference, 0, len(parentRefs))
+	changed := false
+	for _, parentRef := range parentRefs {
+		if parentRef.SectionName == nil || (parentRef.Kind != nil && *parentRef.Kind != "Gateway") {
+			migrated = append(migrated, parentRef)
+			continue
+		}
+		name := r.sectionName(string(*parentRef.SectionName))
+		if name == *parentRef.SectionName {
+			migrated = append(migrated, parentRef)
+			continue
+		}
+
+		gatewayName, gatewayNamespace := parentGateway(route.GetNamespace(), parentRef)
+		var gateway gatewayv1.Gateway
+		if err := r.Get(ctx, client.ObjectKey{Name: gatewayName, Namespace: gatewayNamespace}, &gateway); client.IgnoreNotFound(err) != nil {
+			return false, err
+		}
+		if slices.ContainsFunc(foreignListeners(&gateway), func(listener gatewayv1.Listener) bool {
+			return listener.Name == *parentRef.SectionName
+		}) {
+			migrated = append(migrated, parentRef)
+			continue
+		}
+
+		log.Info("Migrating section name to sanitized listener name", "route", route.GetName(), "namespace", route.GetNamespace(),
+			"sectionName", *parentRef.SectionName, "listener", name)
+		r.Recorder.Eventf(route, corev1.EventTypeNormal, "SectionNameMigrated",
+			"Section name %s of Gateway %s/%s was renamed to %s", *parentRef.SectionName, gatewayNamespace, gatewayName, name)
+		parentRef.SectionName = &name
+		migrated = append(migrated, parentRef)
+		changed = true
+	}
+	if !changed {
+		return true, nil
+	}
+
+	patch, err := json.Marshal(map[string]any{
+		"spec": map[string]any{
+			"parentRefs": migrated,
+		},
+	})
+	if err != nil {
+		return false, err
+	}
+	if err := r.Patch(ctx, route, client.RawPatch(types.MergePatchType, patch)); err != nil {
+		log.Error(err, "Failed to migrate section names", "route", route.GetName())
+		return false, err
+	}
+	return false, nil
+}
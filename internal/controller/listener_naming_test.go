@@ -0,0 +1,125 @@
+package controller
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// sectionNameHash returns the hash suffix sanitized naming gives a name
+func sectionNameHash(name string) string {
+	hash := sha256.Sum256([]byte(name))
+	return hex.EncodeToString(hash[:])[:sectionNameHashLength]
+}
+
+func TestSectionName(t *testing.T) {
+	long := "app." + strings.Repeat("a", 70) + ".example.com"
+	tests := []struct {
+		name   string
+		naming ListenerNaming
+		input  string
+		want   string
+	}{
+		{name: "hostname naming keeps names", naming: ListenerNamingHostname, input: "*.Example.com", want: "*.Example.com"},
+		{name: "valid name is kept", naming: ListenerNamingSanitized, input: "app.example.com", want: "app.example.com"},
+		{name: "wildcard", naming: ListenerNamingSanitized, input: "*.example.com", want: "example.com-" + sectionNameHash("*.example.com")},
+		{name: "upper case", naming: ListenerNamingSanitized, input: "App.example.com", want: "app.example.com-" + sectionNameHash("App.example.com")},
+		{name: "port suffix", naming: ListenerNamingSanitized, input: "tcp-db.example.com:5432", want: "tcp-db.example.com-5432-" + sectionNameHash("tcp-db.example.com:5432")},
+		{name: "long name", naming: ListenerNamingSanitized, input: long, want: long[:54] + "-" + sectionNameHash(long)},
+		{name: "nothing valid", naming: ListenerNamingSanitized, input: "*", want: sectionNameHash("*")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &GatewayManager{ListenerNaming: tt.naming}
+			got := string(r.sectionName(tt.input))
+			if tt.naming == ListenerNamingSanitized && len(got) > maxSectionNameLength {
+				t.Errorf("sectionName(%q) = %q, longer than %d characters", tt.input, got, maxSectionNameLength)
+			}
+			if got != tt.want {
+				t.Errorf("sectionName(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEnsureSectionNames(t *testing.T) {
+	gateway := gatewayv1.Kind("Gateway")
+	listenerSet := gatewayv1.Kind("XListenerSet")
+	tests := []struct {
+		name        string
+		naming      ListenerNaming
+		sectionName gatewayv1.SectionName
+		kind        *gatewayv1.Kind
+		foreign     bool
+		wantOK      bool
+		want        string
+	}{
+		{name: "hostname naming", naming: ListenerNamingHostname, sectionName: "*.example.com", wantOK: true, want: "*.example.com"},
+		{name: "valid section name", naming: ListenerNamingSanitized, sectionName: "app.example.com", wantOK: true, want: "app.example.com"},
+		{name: "migrated", naming: ListenerNamingSanitized, sectionName: "*.example.com", kind: &gateway, want: "example.com-" + sectionNameHash("*.example.com")},
+		{name: "foreign listener", naming: ListenerNamingSanitized, sectionName: "*.example.com", foreign: true, wantOK: true, want: "*.example.com"},
+		{name: "not a gateway", naming: ListenerNamingSanitized, sectionName: "*.example.com", kind: &listenerSet, wantOK: true, want: "*.example.com"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scheme := runtime.NewScheme()
+			if err := gatewayv1.Install(scheme); err != nil {
+				t.Fatal(err)
+			}
+			gw := &gatewayv1.Gateway{ObjectMeta: metav1.ObjectMeta{Name: "gateway", Namespace: "team"}}
+			if tt.foreign {
+				gw.Spec.Listeners = []gatewayv1.Listener{{Name: tt.sectionName, Protocol: gatewayv1.HTTPSProtocolType, Port: httpsPort}}
+				gw.ManagedFields = []metav1.ManagedFieldsEntry{{
+					Manager:    "kubectl",
+					APIVersion: gatewayv1.GroupVersion.String(),
+					Operation:  metav1.ManagedFieldsOperationApply,
+					FieldsType: "FieldsV1",
+					FieldsV1:   &metav1.FieldsV1{Raw: []byte(`{"f:spec":{"f:listeners":{"k:{\"name\":\"` + string(tt.sectionName) + `\"}":{}}}}`)},
+				}}
+			}
+			sectionName := tt.sectionName
+			route := &gatewayv1.HTTPRoute{
+				ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "team"},
+				Spec: gatewayv1.HTTPRouteSpec{CommonRouteSpec: gatewayv1.CommonRouteSpec{
+					ParentRefs: []gatewayv1.ParentReference{{Name: "gateway", Kind: tt.kind, SectionName: &sectionName}},
+				}},
+			}
+			// The fake client manages the managed fields itself, so the Gateway is served as is
+			c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(route).WithInterceptorFuncs(interceptor.Funcs{
+				Get: func(ctx context.Context, c client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+					if gateway, ok := obj.(*gatewayv1.Gateway); ok {
+						gw.DeepCopyInto(gateway)
+						return nil
+					}
+					return c.Get(ctx, key, obj, opts...)
+				},
+			}).Build()
+
+			r := &GatewayManager{Client: c, Scheme: scheme, Recorder: record.NewFakeRecorder(10), ListenerNaming: tt.naming}
+			ok, err := r.ensureSectionNames(context.Background(), route, route.Spec.ParentRefs)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if ok != tt.wantOK {
+				t.Errorf("ensureSectionNames() = %v, want %v", ok, tt.wantOK)
+			}
+			var got gatewayv1.HTTPRoute
+			if err := c.Get(context.Background(), client.ObjectKeyFromObject(route), &got); err != nil {
+				t.Fatal(err)
+			}
+			if name := string(*got.Spec.ParentRefs[0].SectionName); name != tt.want {
+				t.Errorf("section name = %q, want %q", name, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,135 @@
+package controller
+
+import (
+	"encoding/json"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// listenerOwners maps every listener on the gateway to the field managers owning it,
+// read from the gateway's managed fields
+func listenerOwners(gateway *gatewayv1.Gateway) map[gatewayv1.SectionName][]string {
+	owners := make(map[gatewayv1.SectionName][]string)
+	for _, entry := range gateway.ManagedFields {
+		if entry.FieldsV1 == nil {
+			continue
+		}
+		var fields map[string]map[string]map[string]any
+		if err := json.Unmarshal(entry.FieldsV1.Raw, &fields); err != nil {
+			continue
+		}
+		for key := range fields["f:spec"]["f:listeners"] {
+			// Listeners are a map keyed by name, e.g. k:{"name":"example.com"}
+			if !strings.HasPrefix(key, "k:") {
+				continue
+			}
+			var listenerKey struct {
+				Name gatewayv1.SectionName `json:"name"`
+			}
+			if err := json.Unmarshal([]byte(strings.TrimPrefix(key, "k:")), &listenerKey); err != nil {
+				continue
+			}
+			owners[listenerKey.Name] = append(owners[listenerKey.Name], entry.Manager)
+		}
+	}
+	return owners
+}
+
+// appliedListeners returns the names of the gateway's listeners applied by the operator
+func appliedListeners(gateway *gatewayv1.Gateway) map[gatewayv1.SectionName]bool {
+	applied := make(map[gatewayv1.SectionName]bool)
+	for _, entry := range gateway.ManagedFields {
+		if entry.Manager != fieldManager || entry.Operation != metav1.ManagedFieldsOperationApply {
+			continue
+		}
+		single := *gateway
+		single.ManagedFields = []metav1.ManagedFieldsEntry{entry}
+		for name := range listenerOwners(&single) {
+			applied[name] = true
+		}
+	}
+	return applied
+}
+
+// foreignListeners returns the gateway's listeners managed by other tools or users. A listener is
+// foreign when the operator has never applied it, so listeners the operator created or took over
+// earlier are still the operator's, even when other field managers share them.
+func foreignListeners(gateway *gatewayv1.Gateway) []gatewayv1.Listener {
+	applied := appliedListeners(gateway)
+
+	foreign := make([]gatewayv1.Listener, 0)
+	owners := listenerOwners(gateway)
+	for _, listener := range gateway.Spec.Listeners {
+		if len(owners[listener.Name]) > 0 && !applied[listener.Name] {
+			foreign = append(foreign, listener)
+		}
+	}
+	return foreign
+}
+
+// withoutForeignConflicts drops the operator's listeners that would replace or clash with a foreign
+// listener: listeners with the same name, and listeners with the same port, protocol and hostname.
+// It returns the remaining listeners and the names of the dropped ones.
+func withoutForeignConflicts(
+	listeners []gatewayv1.Listener,
+	foreign []gatewayv1.Listener,
+) ([]gatewayv1.Listener, []gatewayv1.SectionName) {
+	if len(foreign) == 0 {
+		return listeners, nil
+	}
+
+	kept := make([]gatewayv1.Listener, 0, len(listeners))
+	dropped := make([]gatewayv1.SectionName, 0)
+	for _, listener := range listeners {
+		conflict := false
+		for _, other := range foreign {
+			if listener.Name == other.Name ||
+				(listener.Port == other.Port && listener.Protocol == other.Protocol && sameHostname(listener.Hostname, other.Hostname)) {
+				conflict = true
+				break
+			}
+		}
+		if conflict {
+			dropped = append(dropped, listener.Name)
+			continue
+		}
+		kept = append(kept, listener)
+	}
+	return kept, dropped
+}
+
+// sameHostname reports whether two listener hostnames are equal, treating a missing hostname as a catch-all
+func sameHostname(a, b *gatewayv1.Hostname) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
+
+// listenersDrifted reports whether the gateway's listeners differ from the desired listeners in
+// name, port, protocol or hostname, or the gateway has listeners the operator applied that are
+// no longer desired. Fields defaulted by the API server are not compared.
+func listenersDrifted(gateway *gatewayv1.Gateway, desired []gatewayv1.Listener) bool {
+	current := make(map[gatewayv1.SectionName]gatewayv1.Listener, len(gateway.Spec.Listeners))
+	for _, listener := range gateway.Spec.Listeners {
+		current[listener.Name] = listener
+	}
+
+	wanted := make(map[gatewayv1.SectionName]bool, len(desired))
+	for _, listener := range desired {
+		wanted[listener.Name] = true
+		existing, exists := current[listener.Name]
+		if !exists || existing.Port != listener.Port || existing.Protocol != listener.Protocol ||
+			!sameHostname(existing.Hostname, listener.Hostname) {
+			return true
+		}
+	}
+	for name := range appliedListeners(gateway) {
+		if _, exists := current[name]; exists && !wanted[name] {
+			return true
+		}
+	}
+	return false
+}
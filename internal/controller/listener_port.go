@@ -0,0 +1,176 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// listenerPortForRoute returns the listener port a route asks for with the listener-port annotation.
+// It reports whether the annotation is set, and fails for values that aren't a port number.
+func listenerPortForRoute(route client.Object) (gatewayv1.PortNumber, bool, error) {
+	value, exists := route.GetAnnotations()[AnnotationListenerPort]
+	if !exists {
+		return 0, false, nil
+	}
+	port, err := strconv.ParseInt(value, 10, 32)
+	if err != nil || port < 1 || port > 65535 {
+		return 0, true, fmt.Errorf("listener port %q is not a port number", value)
+	}
+	return gatewayv1.PortNumber(port), true, nil
+}
+
+// compatibleProtocols reports whether listeners with the two protocols can share a port. HTTPS and
+// TLS listeners are told apart by SNI, other protocols can only share a port with themselves.
+func compatibleProtocols(a, b gatewayv1.ProtocolType) bool {
+	tls := func(protocol gatewayv1.ProtocolType) bool {
+		return protocol == gatewayv1.HTTPSProtocolType || protocol == gatewayv1.TLSProtocolType
+	}
+	return a == b || tls(a) && tls(b)
+}
+
+// ensureListenerPort validates the listener port a route asks for with the listener-port annotation.
+// The port must be the default port of the route's listeners or in the configured range, and the
+// route's listeners must not collide with the listeners on the gateway or the ports claimed by
+// TCPRoutes: listeners sharing a port need compatible protocols, and a hostname can only have one
+// listener per port. A warning event is emitted on the route when the port is rejected.
+func (r *GatewayManager) ensureListenerPort(
+	ctx context.Context,
+	route client.Object,
+	gatewayName, gatewayNamespace string,
+) error {
+	log := logf.FromContext(ctx)
+
+	port, requested, err := listenerPortForRoute(route)
+	if !requested {
+		return nil
+	}
+	if err != nil {
+		r.Recorder.Eventf(route, corev1.EventTypeWarning, "ListenerPortInvalid", "%v", err)
+		return errors.NewBadRequest(err.Error())
+	}
+	if config := r.config(); port != config.HTTPSPort && port != config.HTTPPort && (port < r.ListenerPortRangeStart || port > r.ListenerPortRangeEnd) {
+		err := errors.NewBadRequest(fmt.Sprintf("listener port %d is not in the allowed range %d-%d",
+			port, r.ListenerPortRangeStart, r.ListenerPortRangeEnd))
+		log.Error(err, "Listener port not allowed", "route", route.GetName(), "port", port)
+		r.Recorder.Eventf(route, corev1.EventTypeWarning, "ListenerPortNotAllowed", "%v", err)
+		return err
+	}
+
+	info, ok := newRouteInfo(route)
+	if !ok {
+		return nil
+	}
+	routes, err := r.listGatewayRoutes(ctx, gatewayName, gatewayNamespace)
+	if err != nil {
+		return err
+	}
+	var gateway gatewayv1.Gateway
+	if err := r.Get(ctx, client.ObjectKey{Name: gatewayName, Namespace: gatewayNamespace}, &gateway); client.IgnoreNotFound(err) != nil {
+		return err
+	}
+
+	current, err := r.currentListeners(ctx, &gateway)
+	if err != nil {
+		return err
+	}
+
+	conflict := func(reason string) error {
+		err := errors.NewBadRequest(fmt.Sprintf("listener port %d on Gateway %s/%s conflicts: %s", port, gatewayNamespace, gatewayName, reason))
+		log.Error(err, "Listener port conflict", "route", route.GetName(), "port", port)
+		r.Recorder.Eventf(route, corev1.EventTypeWarning, "ListenerPortConflict", "%v", err)
+		return err
+	}
+
+	if owner, taken := tcpPortOwners(routes, gatewayName, gatewayNamespace)[port]; taken {
+		return conflict(fmt.Sprintf("the port is used by TCPRoute %s", owner))
+	}
+	for _, listener := range r.listenersForRoute(ctx, info, gatewayNamespace) {
+		for _, existing := range current {
+			if existing.Port != port || existing.Name == listener.Name {
+				continue
+			}
+			if !compatibleProtocols(existing.Protocol, listener.Protocol) {
+				return conflict(fmt.Sprintf("listener %s uses the port with protocol %s", existing.Name, existing.Protocol))
+			}
+			if (existing.Hostname == nil) == (listener.Hostname == nil) &&
+				(existing.Hostname == nil || *existing.Hostname == *listener.Hostname) {
+				return conflict(fmt.Sprintf("listener %s already serves the hostname of listener %s on the port", existing.Name, listener.Name))
+			}
+		}
+	}
+	return nil
+}
+
+// listenerConflictRequeueInterval is how often a TLSRoute rejected for the HTTPS listener of its
+// hostname is checked again, in case the HTTPS listener was removed
+const listenerConflictRequeueInterval = 5 * time.Minute
+
+// tlsListenerClashes returns the TLS listeners with the hostname and port of an HTTPS listener.
+// Envoy can't tell them apart by SNI, so the HTTPS listener is kept, the TLS listener dropped and
+// the TLSRoute asking for it rejected, see ensureTLSListeners.
+func tlsListenerClashes(listeners map[gatewayv1.SectionName]gatewayv1.Listener) []gatewayv1.SectionName {
+	var clashes []gatewayv1.SectionName
+	for name, listener := range listeners {
+		if listener.Protocol != gatewayv1.TLSProtocolType {
+			continue
+		}
+		for _, other := range listeners {
+			if other.Protocol == gatewayv1.HTTPSProtocolType && other.Port == listener.Port &&
+				sameHostname(other.Hostname, listener.Hostname) {
+				clashes = append(clashes, name)
+				break
+			}
+		}
+	}
+	return clashes
+}
+
+// ensureTLSListeners rejects a TLSRoute whose TLS listeners have the hostname and port of an HTTPS
+// listener on the gateway, which is kept instead. The route gets a TLSListenerConflict warning event
+// and GatewayEnsured=False with reason Rejected. It returns false when the route was rejected.
+func (r *GatewayManager) ensureTLSListeners(
+	ctx context.Context,
+	route client.Object,
+	gatewayName, gatewayNamespace string,
+) (bool, error) {
+	info, ok := newRouteInfo(route)
+	if !ok || info.Kind != "TLSRoute" {
+		return true, nil
+	}
+
+	var gateway gatewayv1.Gateway
+	if err := r.Get(ctx, client.ObjectKey{Name: gatewayName, Namespace: gatewayNamespace}, &gateway); err != nil {
+		// A Gateway that doesn't exist yet has no listeners to clash with, the route creates it
+		if errors.IsNotFound(err) {
+			return true, nil
+		}
+		return false, err
+	}
+	current, err := r.currentListeners(ctx, &gateway)
+	if err != nil {
+		return false, err
+	}
+	for _, listener := range r.listenersForRoute(ctx, info, gatewayNamespace) {
+		for _, existing := range current {
+			if existing.Protocol != gatewayv1.HTTPSProtocolType || existing.Port != listener.Port ||
+				!sameHostname(existing.Hostname, listener.Hostname) {
+				continue
+			}
+			message := fmt.Sprintf("HTTPS listener %s on Gateway %s/%s already serves the hostname of TLS listener %s on port %d",
+				existing.Name, gatewayNamespace, gatewayName, listener.Name, listener.Port)
+			logf.FromContext(ctx).Info("Rejecting TLSRoute clashing with an HTTPS listener", "route", route.GetName(),
+				"namespace", route.GetNamespace(), "reason", message)
+			r.Recorder.Eventf(route, corev1.EventTypeWarning, "TLSListenerConflict", "Rejected: %s", message)
+			return false, r.syncRouteStatus(ctx, route, gatewayName, gatewayNamespace, errors.NewBadRequest(message))
+		}
+	}
+	return true, nil
+}
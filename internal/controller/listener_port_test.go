@@ -0,0 +1,41 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+)
+
+// TestEnsureTLSListenersMissingGateway checks that a TLSRoute whose Gateway doesn't exist yet is
+// let through, so it can create the Gateway
+func TestEnsureTLSListenersMissingGateway(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := gatewayv1.Install(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := gatewayv1alpha2.Install(scheme); err != nil {
+		t.Fatal(err)
+	}
+	route := &gatewayv1alpha2.TLSRoute{
+		TypeMeta:   metav1.TypeMeta{APIVersion: gatewayv1alpha2.GroupVersion.String(), Kind: "TLSRoute"},
+		ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "team"},
+		Spec: gatewayv1alpha2.TLSRouteSpec{
+			CommonRouteSpec: gatewayv1.CommonRouteSpec{ParentRefs: []gatewayv1.ParentReference{{Name: "gateway"}}},
+			Hostnames:       []gatewayv1.Hostname{"app.example.com"},
+		},
+	}
+
+	r := &GatewayManager{Client: fake.NewClientBuilder().WithScheme(scheme).Build(), Scheme: scheme}
+	ok, err := r.ensureTLSListeners(context.Background(), route, "gateway", "team")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ok {
+		t.Error("ensureTLSListeners = false, want true for a Gateway that doesn't exist yet")
+	}
+}
@@ -0,0 +1,100 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// listenerQuota returns how many hostnames the routes of a namespace may add to Gateways in other
+// namespaces: the quota annotation of the namespace, or the operator default. Zero is unlimited.
+func (r *GatewayManager) listenerQuota(ctx context.Context, namespace string) int {
+	if value, exists := r.namespaceDefaults(ctx, namespace)[AnnotationListenerQuota]; exists {
+		quota, err := strconv.Atoi(value)
+		if err == nil && quota >= 0 {
+			return quota
+		}
+		logf.FromContext(ctx).Info("Invalid listener quota annotation on namespace, using the default",
+			"namespace", namespace, "value", value)
+	}
+	return r.config().NamespaceListenerQuota
+}
+
+// onSharedGateway reports whether any of the route's parent references point to a Gateway in
+// another namespace
+func onSharedGateway(route routeInfo) bool {
+	for _, parentRef := range route.ParentRefs {
+		if _, namespace := parentGateway(route.GetNamespace(), parentRef); namespace != route.GetNamespace() {
+			return true
+		}
+	}
+	return false
+}
+
+// routesOverQuota returns the routes of a namespace whose hostnames don't fit in its listener
+// quota on Gateways in other namespaces. Routes are counted oldest first, so routes that fit keep
+// their listeners when a newer route would exceed the quota. A hostname counts once, however many
+// routes of the namespace use it.
+func (r *GatewayManager) routesOverQuota(ctx context.Context, namespace string) (map[types.UID]int, error) {
+	quota := r.listenerQuota(ctx, namespace)
+	if quota == 0 {
+		return nil, nil
+	}
+	routes, err := r.listRoutes(ctx, client.InNamespace(namespace))
+	if err != nil {
+		return nil, err
+	}
+	sortOldestFirst(routes)
+
+	counted := make(map[gatewayv1.Hostname]bool)
+	over := make(map[types.UID]int)
+	for _, route := range routes {
+		if !route.Enabled || !route.GetDeletionTimestamp().IsZero() || !onSharedGateway(route) {
+			continue
+		}
+		var added []gatewayv1.Hostname
+		for _, hostname := range r.withoutDisallowedHostnames(route).Hostnames {
+			if !counted[hostname] && !slices.Contains(added, hostname) {
+				added = append(added, hostname)
+			}
+		}
+		if len(counted)+len(added) > quota {
+			over[route.GetUID()] = quota
+			continue
+		}
+		for _, hostname := range added {
+			counted[hostname] = true
+		}
+	}
+	return over, nil
+}
+
+// ensureListenerQuota rejects a route attaching to a Gateway in another namespace when its
+// hostnames would exceed the listener quota of its namespace, with a warning event on the route
+func (r *GatewayManager) ensureListenerQuota(ctx context.Context, route client.Object, gatewayNamespace string) error {
+	if gatewayNamespace == route.GetNamespace() {
+		return nil
+	}
+	over, err := r.routesOverQuota(ctx, route.GetNamespace())
+	if err != nil {
+		return err
+	}
+	quota, exceeded := over[route.GetUID()]
+	if !exceeded {
+		return nil
+	}
+
+	err = errors.NewBadRequest(fmt.Sprintf("the hostnames of the route exceed the quota of %d hostnames namespace %s may add to shared Gateways",
+		quota, route.GetNamespace()))
+	logf.FromContext(ctx).Error(err, "Listener quota exceeded", "route", route.GetName(), "namespace", route.GetNamespace())
+	r.Recorder.Eventf(route, corev1.EventTypeWarning, "ListenerQuotaExceeded", "%v", err)
+	return err
+}
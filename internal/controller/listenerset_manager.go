@@ -0,0 +1,219 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// +kubebuilder:rbac:groups=gateway.networking.x-k8s.io,resources=xlistenersets,verbs=get;list;watch;create;update;patch;delete
+
+// listenerSetGVK is the experimental ListenerSet kind. It is not part of the Gateway API version
+// the operator is built against, so ListenerSets are handled as unstructured objects.
+var listenerSetGVK = schema.GroupVersionKind{
+	Group:   "gateway.networking.x-k8s.io",
+	Version: "v1alpha1",
+	Kind:    "XListenerSet",
+}
+
+// maxListenersPerListenerSet is the maximum number of listeners in a ListenerSet
+const maxListenersPerListenerSet = 64
+
+// listenerSetParentListenerName is the name of the listener kept on a Gateway whose listeners are
+// in ListenerSets, since a Gateway must have at least one listener of its own
+const listenerSetParentListenerName = "listenerset-parent"
+
+// listenerSetParentHostname is the hostname of the parent listener. The .invalid top-level domain
+// never resolves, so no route is served by the listener.
+const listenerSetParentHostname = "listenerset-parent.invalid"
+
+// listenerSetName returns the name of the n-th ListenerSet holding a gateway's listeners
+func listenerSetName(gatewayName string, n int) string {
+	return fmt.Sprintf("%s-listeners-%d", gatewayName, n)
+}
+
+// allowListenerSets sets spec.allowedListeners on an unstructured Gateway so that ListenerSets
+// in the gateway's namespace may attach to it, and replaces its listeners by the parent listener
+func (r *GatewayManager) allowListenerSets(gateway *unstructured.Unstructured) error {
+	hostname := gatewayv1.Hostname(listenerSetParentHostname)
+	parent, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&gatewayv1.Listener{
+		Name:          listenerSetParentListenerName,
+		Protocol:      gatewayv1.HTTPProtocolType,
+		Port:          r.config().HTTPPort,
+		Hostname:      &hostname,
+		AllowedRoutes: namespaceAllowedRoutes(gateway.GetNamespace()),
+	})
+	if err != nil {
+		return err
+	}
+	if err := unstructured.SetNestedSlice(gateway.Object, []any{parent}, "spec", "listeners"); err != nil {
+		return err
+	}
+	return unstructured.SetNestedField(gateway.Object, "Same", "spec", "allowedListeners", "namespaces", "from")
+}
+
+// createGatewayWithListenerSets creates a Gateway with only the parent listener, that accepts
+// ListenerSets, and puts the listeners into ListenerSets attached to it
+func (r *GatewayManager) createGatewayWithListenerSets(
+	ctx context.Context,
+	gateway *gatewayv1.Gateway,
+	listeners []gatewayv1.Listener,
+) error {
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(gateway)
+	if err != nil {
+		return err
+	}
+	obj := &unstructured.Unstructured{Object: content}
+	obj.SetGroupVersionKind(gatewayv1.SchemeGroupVersion.WithKind("Gateway"))
+	unstructured.RemoveNestedField(obj.Object, "status")
+	if err := r.allowListenerSets(obj); err != nil {
+		return err
+	}
+	if err := r.Create(ctx, obj); err != nil {
+		return err
+	}
+	r.audit(ctx, auditActionCreate, "Gateway", obj, "added", listenerNames(listeners))
+
+	gateway.UID = obj.GetUID()
+	return r.syncListenerSets(ctx, gateway, listeners)
+}
+
+// applyGatewayListenerSets applies the gateway patch with only the parent listener, making the
+// Gateway accept ListenerSets, and moves the listeners into ListenerSets attached to it. Listeners
+// previously applied to the Gateway by the operator are dropped from it by the same apply.
+func (r *GatewayManager) applyGatewayListenerSets(
+	ctx context.Context,
+	gateway *gatewayv1.Gateway,
+	patch *gatewayv1.Gateway,
+	listeners []gatewayv1.Listener,
+) error {
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(patch)
+	if err != nil {
+		return err
+	}
+	obj := &unstructured.Unstructured{Object: content}
+	unstructured.RemoveNestedField(obj.Object, "status")
+	if err := r.allowListenerSets(obj); err != nil {
+		return err
+	}
+	if err := r.Patch(ctx, obj, client.Apply, client.ForceOwnership, client.FieldOwner(fieldManager)); err != nil {
+		return err
+	}
+	if obj.GetResourceVersion() != gateway.ResourceVersion {
+		r.audit(ctx, auditActionUpdate, "Gateway", obj)
+	}
+
+	return r.syncListenerSets(ctx, gateway, listeners)
+}
+
+// syncListenerSets spreads a gateway's listeners over as many ListenerSets as needed, in the
+// gateway's namespace, and deletes the gateway's ListenerSets that are no longer needed
+func (r *GatewayManager) syncListenerSets(
+	ctx context.Context,
+	gateway *gatewayv1.Gateway,
+	listeners []gatewayv1.Listener,
+) error {
+	log := logf.FromContext(ctx)
+
+	desired := make(map[string]bool)
+	for start, n := 0, 1; start < len(listeners); start, n = start+maxListenersPerListenerSet, n+1 {
+		end := min(start+maxListenersPerListenerSet, len(listeners))
+		name := listenerSetName(gateway.Name, n)
+		desired[name] = true
+
+		entries := make([]any, 0, end-start)
+		for _, listener := range listeners[start:end] {
+			entry, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&listener)
+			if err != nil {
+				return err
+			}
+			entries = append(entries, entry)
+		}
+
+		listenerSet := &unstructured.Unstructured{}
+		listenerSet.SetGroupVersionKind(listenerSetGVK)
+		listenerSet.SetName(name)
+		listenerSet.SetNamespace(gateway.Namespace)
+		listenerSet.SetLabels(map[string]string{managedByLabel: managedByValue})
+		listenerSet.SetOwnerReferences([]metav1.OwnerReference{
+			*metav1.NewControllerRef(gateway, gatewayv1.SchemeGroupVersion.WithKind("Gateway")),
+		})
+		listenerSet.Object["spec"] = map[string]any{
+			"parentRef": map[string]any{
+				"group": gatewayv1.GroupName,
+				"kind":  "Gateway",
+				"name":  gateway.Name,
+			},
+			"listeners": entries,
+		}
+
+		if err := r.Patch(ctx, listenerSet, client.Apply, client.ForceOwnership, client.FieldOwner(fieldManager)); err != nil {
+			return err
+		}
+	}
+
+	var listenerSets unstructured.UnstructuredList
+	listenerSets.SetGroupVersionKind(listenerSetGVK.GroupVersion().WithKind(listenerSetGVK.Kind + "List"))
+	if err := r.List(ctx, &listenerSets, client.InNamespace(gateway.Namespace), client.MatchingLabels{managedByLabel: managedByValue}); err != nil {
+		return err
+	}
+	for i := range listenerSets.Items {
+		listenerSet := &listenerSets.Items[i]
+		if desired[listenerSet.GetName()] || !metav1.IsControlledBy(listenerSet, gateway) {
+			continue
+		}
+		if err := r.Delete(ctx, listenerSet); client.IgnoreNotFound(err) != nil {
+			return err
+		}
+		r.audit(ctx, auditActionDelete, listenerSetGVK.Kind, listenerSet)
+		log.Info("Deleted unused ListenerSet", "listenerSet", client.ObjectKeyFromObject(listenerSet).String())
+	}
+
+	log.Info("Updated Gateway ListenerSets", "gateway", gateway.Name, "listenerSets", len(desired), "listeners", len(listeners))
+	return nil
+}
+
+// currentListeners returns the listeners a gateway serves: its own listeners, and in ListenerSet
+// mode the listeners of the ListenerSets attached to it as well
+func (r *GatewayManager) currentListeners(ctx context.Context, gateway *gatewayv1.Gateway) ([]gatewayv1.Listener, error) {
+	if !r.EnableListenerSets {
+		return gateway.Spec.Listeners, nil
+	}
+
+	var listenerSets unstructured.UnstructuredList
+	listenerSets.SetGroupVersionKind(listenerSetGVK.GroupVersion().WithKind(listenerSetGVK.Kind + "List"))
+	if err := r.List(ctx, &listenerSets, client.InNamespace(gateway.Namespace), client.MatchingLabels{managedByLabel: managedByValue}); err != nil {
+		return nil, err
+	}
+	listeners := slices.Clone(gateway.Spec.Listeners)
+	for i := range listenerSets.Items {
+		listenerSet := &listenerSets.Items[i]
+		if !metav1.IsControlledBy(listenerSet, gateway) {
+			continue
+		}
+		entries, _, err := unstructured.NestedSlice(listenerSet.Object, "spec", "listeners")
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range entries {
+			content, ok := entry.(map[string]any)
+			if !ok {
+				continue
+			}
+			var listener gatewayv1.Listener
+			if err := runtime.DefaultUnstructuredConverter.FromUnstructured(content, &listener); err != nil {
+				return nil, err
+			}
+			listeners = append(listeners, listener)
+		}
+	}
+	return listeners, nil
+}
@@ -0,0 +1,186 @@
+package controller
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"sync/atomic"
+
+	"github.com/go-logr/logr"
+	"github.com/go-logr/zapr"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// LogFormat selects how log entries are encoded
+type LogFormat string
+
+const (
+	// LogFormatConsole writes human readable log lines
+	LogFormatConsole LogFormat = "console"
+
+	// LogFormatJSON writes a JSON object per log entry, for log collectors
+	LogFormatJSON LogFormat = "json"
+)
+
+// IsValid reports whether the log format is known
+func (f LogFormat) IsValid() bool {
+	switch f {
+	case LogFormatConsole, LogFormatJSON:
+		return true
+	}
+	return false
+}
+
+// parseLogLevel parses a log level: debug, info or error, or a verbosity where 0 is info and
+// higher numbers are more verbose, matching the V levels of the operator's logs
+func parseLogLevel(value string) (zapcore.Level, error) {
+	switch value {
+	case "debug":
+		return zapcore.DebugLevel, nil
+	case "info":
+		return zapcore.InfoLevel, nil
+	case "error":
+		return zapcore.ErrorLevel, nil
+	}
+	verbosity, err := strconv.Atoi(value)
+	if err != nil || verbosity < 0 || verbosity > 127 {
+		return 0, fmt.Errorf("%q is not debug, info, error or a verbosity from 0 to 127", value)
+	}
+	return zapcore.Level(-verbosity), nil
+}
+
+// parseControllerLogLevels parses comma separated controller=level pairs. Controllers are named as
+// in the controller field of their log entries, e.g. gateway or httproute, other loggers by name.
+func parseControllerLogLevels(value string) (map[string]zapcore.Level, error) {
+	levels := make(map[string]zapcore.Level)
+	for _, pair := range strings.Split(value, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		name, level, found := strings.Cut(pair, "=")
+		if !found || name == "" {
+			return nil, fmt.Errorf("%q is not a controller=level pair", pair)
+		}
+		parsed, err := parseLogLevel(strings.TrimSpace(level))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		levels[strings.TrimSpace(name)] = parsed
+	}
+	return levels, nil
+}
+
+// logState is the log configuration in effect
+type logState struct {
+	core        zapcore.Core
+	level       zapcore.Level
+	controllers map[string]zapcore.Level
+	min         zapcore.Level
+}
+
+// levelFor returns the level of a controller, or of a logger by name, falling back to the global level
+func (s *logState) levelFor(controller, loggerName string) zapcore.Level {
+	if level, exists := s.controllers[controller]; exists && controller != "" {
+		return level
+	}
+	if level, exists := s.controllers[loggerName]; exists && loggerName != "" {
+		return level
+	}
+	return s.level
+}
+
+// DynamicLogger is the operator's logger, whose format and levels can be changed while it runs,
+// so debugging e.g. the hostname collection of the Gateway controller doesn't need a redeploy
+// with a higher verbosity. Levels can be set for all logs and for single controllers.
+type DynamicLogger struct {
+	state atomic.Pointer[logState]
+}
+
+// NewDynamicLogger returns a logger writing to stderr with the format and levels, see Configure
+func NewDynamicLogger(format LogFormat, level, controllerLevels string) (*DynamicLogger, error) {
+	d := &DynamicLogger{}
+	if err := d.Configure(format, level, controllerLevels); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// Configure changes the format and levels of the logger. controllerLevels are comma separated
+// controller=level pairs overriding the level for single controllers.
+func (d *DynamicLogger) Configure(format LogFormat, level, controllerLevels string) error {
+	state := &logState{}
+	var err error
+	if state.level, err = parseLogLevel(level); err != nil {
+		return err
+	}
+	if state.controllers, err = parseControllerLogLevels(controllerLevels); err != nil {
+		return err
+	}
+	state.min = state.level
+	for _, level := range state.controllers {
+		state.min = min(state.min, level)
+	}
+
+	var encoder zapcore.Encoder
+	switch format {
+	case LogFormatJSON:
+		config := zap.NewProductionEncoderConfig()
+		config.EncodeTime = zapcore.ISO8601TimeEncoder
+		encoder = zapcore.NewJSONEncoder(config)
+	case LogFormatConsole:
+		encoder = zapcore.NewConsoleEncoder(zap.NewDevelopmentEncoderConfig())
+	default:
+		return fmt.Errorf("log format %q must be %s or %s", format, LogFormatConsole, LogFormatJSON)
+	}
+	state.core = zapcore.NewCore(encoder, zapcore.Lock(os.Stderr), zap.LevelEnablerFunc(func(zapcore.Level) bool { return true }))
+
+	d.state.Store(state)
+	return nil
+}
+
+// Logger returns a logr.Logger writing through the dynamic logger
+func (d *DynamicLogger) Logger() logr.Logger {
+	return zapr.NewLogger(zap.New(&dynamicCore{logger: d}, zap.AddCaller(), zap.ErrorOutput(zapcore.Lock(os.Stderr))))
+}
+
+// dynamicCore filters entries by the level of the controller logging them, and writes them with
+// the core of the configuration in effect
+type dynamicCore struct {
+	logger     *DynamicLogger
+	controller string
+	fields     []zapcore.Field
+}
+
+func (c *dynamicCore) Enabled(level zapcore.Level) bool {
+	return level >= c.logger.state.Load().min
+}
+
+func (c *dynamicCore) With(fields []zapcore.Field) zapcore.Core {
+	clone := *c
+	clone.fields = append(slices.Clip(c.fields), fields...)
+	for _, field := range fields {
+		if field.Key == "controller" && field.Type == zapcore.StringType {
+			clone.controller = field.String
+		}
+	}
+	return &clone
+}
+
+func (c *dynamicCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if entry.Level >= c.logger.state.Load().levelFor(c.controller, entry.LoggerName) {
+		return checked.AddCore(entry, c)
+	}
+	return checked
+}
+
+func (c *dynamicCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	return c.logger.state.Load().core.Write(entry, append(slices.Clip(c.fields), fields...))
+}
+
+func (c *dynamicCore) Sync() error {
+	return c.logger.state.Load().core.Sync()
+}
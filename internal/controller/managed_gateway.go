@@ -0,0 +1,111 @@
+package controller
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	operatorv1alpha1 "github.com/NorskHelsenett/gatewayapi-operator/api/v1alpha1"
+)
+
+// +kubebuilder:rbac:groups=gatewayapi-operator.vitistack.io,resources=managedgateways,verbs=get;list;watch;create;update;patch
+// +kubebuilder:rbac:groups=gatewayapi-operator.vitistack.io,resources=managedgateways/status,verbs=get;update;patch
+
+// syncManagedGateway records the routes contributing to a managed gateway and the outcome of its
+// last sync in the ManagedGateway of the same name, so admins have a single object to inspect.
+// syncErr is the error the sync failed with, or nil.
+func (r *GatewayManager) syncManagedGateway(ctx context.Context, gateway *gatewayv1.Gateway, syncErr error) error {
+	listeners, contributions, err := r.collectRouteListeners(ctx, gateway.Name, gateway.Namespace)
+	if err != nil {
+		return err
+	}
+
+	var existing operatorv1alpha1.ManagedGateway
+	if err := r.Get(ctx, client.ObjectKeyFromObject(gateway), &existing); client.IgnoreNotFound(err) != nil {
+		return err
+	}
+
+	typeMeta := metav1.TypeMeta{
+		APIVersion: operatorv1alpha1.GroupVersion.String(),
+		Kind:       "ManagedGateway",
+	}
+	managed := &operatorv1alpha1.ManagedGateway{
+		TypeMeta: typeMeta,
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      gateway.Name,
+			Namespace: gateway.Namespace,
+			Labels:    map[string]string{managedByLabel: managedByValue},
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(gateway, gatewayv1.SchemeGroupVersion.WithKind("Gateway")),
+			},
+		},
+	}
+	if err := r.Patch(ctx, managed, client.Apply, client.ForceOwnership, client.FieldOwner(fieldManager)); err != nil {
+		return err
+	}
+
+	addressClaim, address, err := r.gatewayAddressClaim(ctx, gateway)
+	if err != nil {
+		return err
+	}
+
+	status := operatorv1alpha1.ManagedGatewayStatus{
+		AddressClaim:       addressClaim,
+		Address:            address,
+		Listeners:          int32(len(listeners)),
+		ObservedGeneration: gateway.Generation,
+		LastSyncTime:       &metav1.Time{Time: time.Now()},
+		Conditions:         existing.Status.Conditions,
+	}
+	// Routes are listed by kind, namespace and name so the status only changes with the routes
+	slices.SortFunc(contributions, func(a, b routeContribution) int {
+		return cmp.Or(cmp.Compare(a.Kind, b.Kind), cmp.Compare(a.GetNamespace(), b.GetNamespace()), cmp.Compare(a.GetName(), b.GetName()))
+	})
+	for _, contribution := range contributions {
+		route := operatorv1alpha1.ManagedGatewayRoute{
+			Kind:      contribution.Kind,
+			Namespace: contribution.GetNamespace(),
+			Name:      contribution.GetName(),
+		}
+		for _, hostname := range contribution.Hostnames {
+			route.Hostnames = append(route.Hostnames, string(hostname))
+		}
+		for _, listener := range contribution.Listeners {
+			route.Listeners = append(route.Listeners, string(listener))
+		}
+		status.Routes = append(status.Routes, route)
+	}
+
+	condition := metav1.Condition{
+		Type:               operatorv1alpha1.ManagedGatewayConditionSynced,
+		Status:             metav1.ConditionTrue,
+		Reason:             "Synced",
+		Message:            "The Gateway's listeners are in sync with its routes",
+		ObservedGeneration: gateway.Generation,
+	}
+	switch {
+	case syncErr != nil:
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "SyncFailed"
+		condition.Message = syncErr.Error()
+	case isPaused(gateway):
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "Paused"
+		condition.Message = "Reconciliation of the Gateway is paused"
+	}
+	meta.SetStatusCondition(&status.Conditions, condition)
+
+	// The status is applied separately, metadata is ignored by the status subresource
+	managed = &operatorv1alpha1.ManagedGateway{
+		TypeMeta:   typeMeta,
+		ObjectMeta: metav1.ObjectMeta{Name: gateway.Name, Namespace: gateway.Namespace},
+		Status:     status,
+	}
+	return r.Status().Patch(ctx, managed, client.Apply, client.ForceOwnership, client.FieldOwner(fieldManager))
+}
@@ -0,0 +1,67 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// namespaceGatewayName returns the name of the Gateway provisioned for a namespace
+// in gateway-per-namespace mode
+func (r *GatewayManager) namespaceGatewayName(namespace string) string {
+	return strings.ReplaceAll(r.NamespaceGatewayTemplate, namespaceTemplatePlaceholder, namespace)
+}
+
+// ensureNamespaceParentRefs enforces gateway-per-namespace mode for a route. Routes without
+// parent references get one pointing to their namespace's Gateway, and parent references to
+// any other Gateway are rejected. It returns true when the route may be reconciled as is.
+func (r *GatewayManager) ensureNamespaceParentRefs(
+	ctx context.Context,
+	route client.Object,
+	parentRefs []gatewayv1.ParentReference,
+) (bool, error) {
+	log := logf.FromContext(ctx)
+	gatewayName := r.namespaceGatewayName(route.GetNamespace())
+
+	if len(parentRefs) == 0 {
+		patch, err := json.Marshal(map[string]any{
+			"spec": map[string]any{
+				"parentRefs": []gatewayv1.ParentReference{{Name: gatewayv1.ObjectName(gatewayName)}},
+			},
+		})
+		if err != nil {
+			return false, err
+		}
+		if err := r.Patch(ctx, route, client.RawPatch(types.MergePatchType, patch)); err != nil {
+			log.Error(err, "Failed to set parent reference to namespace gateway", "route", route.GetName())
+			return false, err
+		}
+		log.Info("Set parent reference to namespace gateway", "route", route.GetName(), "namespace", route.GetNamespace(), "gateway", gatewayName)
+		r.Recorder.Eventf(route, corev1.EventTypeNormal, "ParentRefDefaulted",
+			"Attached route to Gateway %s of namespace %s", gatewayName, route.GetNamespace())
+		// The patch triggers a new reconciliation with the updated parent references
+		return false, nil
+	}
+
+	for _, parentRef := range parentRefs {
+		if parentRef.Kind != nil && *parentRef.Kind != "Gateway" {
+			continue
+		}
+		refName, refNamespace := parentGateway(route.GetNamespace(), parentRef)
+		if refName != gatewayName || refNamespace != route.GetNamespace() {
+			log.Info("Rejecting route attaching to a gateway other than its namespace gateway",
+				"route", route.GetName(), "namespace", route.GetNamespace(), "gateway", refNamespace+"/"+refName, "namespaceGateway", gatewayName)
+			r.Recorder.Eventf(route, corev1.EventTypeWarning, "ParentRefRejected",
+				"Gateway %s/%s is not allowed, routes in namespace %s must attach to Gateway %s",
+				refNamespace, refName, route.GetNamespace(), gatewayName)
+			return false, nil
+		}
+	}
+	return true, nil
+}
@@ -0,0 +1,37 @@
+package controller
+
+import (
+	"context"
+	"slices"
+)
+
+// inNamespaceScope reports whether the operator manages the routes in a namespace: the namespace
+// must be one of the watched namespaces when there are any, must not be excluded, and its labels
+// must match the namespace selector when there is one. A namespace that can't be read is out of
+// scope when there is a selector.
+func (r *GatewayManager) inNamespaceScope(ctx context.Context, namespace string) bool {
+	if slices.Contains(r.ExcludeNamespaces, namespace) {
+		return false
+	}
+	if len(r.WatchNamespaces) > 0 && !slices.Contains(r.WatchNamespaces, namespace) {
+		return false
+	}
+	return !selectorSet(r.NamespaceSelector) || r.namespaceMatches(ctx, namespace, r.NamespaceSelector)
+}
+
+// inScopeRoutes returns the routes in the namespaces the operator manages
+func (r *GatewayManager) inScopeRoutes(ctx context.Context, routes []routeInfo) []routeInfo {
+	if len(r.WatchNamespaces) == 0 && len(r.ExcludeNamespaces) == 0 && !selectorSet(r.NamespaceSelector) {
+		return routes
+	}
+	inScope := make(map[string]bool)
+	return slices.DeleteFunc(routes, func(route routeInfo) bool {
+		namespace := route.GetNamespace()
+		scoped, checked := inScope[namespace]
+		if !checked {
+			scoped = r.inNamespaceScope(ctx, namespace)
+			inScope[namespace] = scoped
+		}
+		return !scoped
+	})
+}
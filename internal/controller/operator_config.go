@@ -0,0 +1,285 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// +kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch
+
+// Keys of the operator ConfigMap
+const (
+	configKeyGatewayClass               = "gatewayClassName"
+	configKeyClusterIssuer              = "clusterIssuer"
+	configKeyIPAMZone                   = "ipamZone"
+	configKeyTLSSecretTemplate          = "tlsSecretTemplate"
+	configKeyHTTPSPort                  = "httpsPort"
+	configKeyHTTPPort                   = "httpPort"
+	configKeyGatewayDeletionPolicy      = "gatewayDeletionPolicy"
+	configKeyGatewayDeletionGracePeriod = "gatewayDeletionGracePeriod"
+	configKeyNamespaceListenerQuota     = "namespaceListenerQuota"
+	configKeyLogFormat                  = "logFormat"
+	configKeyLogLevel                   = "logLevel"
+	configKeyControllerLogLevels        = "controllerLogLevels"
+	configKeyZoneEnvoyProxies           = "zoneEnvoyProxies"
+	configKeyAccessLogZones             = "accessLogZones"
+)
+
+// OperatorConfig holds the defaults of the operator that can be changed at runtime through the
+// operator ConfigMap, without restarting or rebuilding the operator
+type OperatorConfig struct {
+	// GatewayClass is the GatewayClass of created Gateways, unless the route asks for another
+	// class or its IPAM zone is mapped to one
+	GatewayClass string
+
+	// ClusterIssuer is the cert-manager cluster issuer of routes and namespaces without one
+	ClusterIssuer string
+
+	// IPAMZone is the IPAM zone of routes and namespaces without one
+	IPAMZone string
+
+	// TLSSecretTemplate names the certificate secrets of listeners, {hostname} is replaced by the
+	// listener hostname
+	TLSSecretTemplate string
+
+	// HTTPSPort and HTTPPort are the ports of HTTPS and TLS listeners, and of plain HTTP listeners
+	HTTPSPort gatewayv1.PortNumber
+	HTTPPort  gatewayv1.PortNumber
+
+	// GatewayDeletionPolicy and GatewayDeletionGracePeriod decide what happens to a Gateway when
+	// no routes reference it anymore
+	GatewayDeletionPolicy      GatewayDeletionPolicy
+	GatewayDeletionGracePeriod time.Duration
+
+	// NamespaceListenerQuota is how many hostnames the routes of a namespace may add to Gateways in
+	// other namespaces, unless the namespace has its own quota. Zero is unlimited
+	NamespaceListenerQuota int
+
+	// LogFormat, LogLevel and ControllerLogLevels configure the operator's logs, see DynamicLogger
+	LogFormat           LogFormat
+	LogLevel            string
+	ControllerLogLevels string
+
+	// ZoneEnvoyProxies maps IPAM zones to the spec of the EnvoyProxy of their Gateways, as YAML, see
+	// syncEnvoyProxy. Zones without one use the GatewayClass's Envoy deployment
+	ZoneEnvoyProxies string
+
+	// AccessLogZones are the comma separated IPAM zones whose Gateways get access logs, * for all
+	// zones, see accessLogSpec
+	AccessLogZones string
+}
+
+// config returns the operator configuration in effect: the configuration loaded from the operator
+// ConfigMap, or the flags and built-in defaults while there is none
+func (r *GatewayManager) config() OperatorConfig {
+	if loaded := r.loadedConfig.Load(); loaded != nil {
+		return *loaded
+	}
+	return r.defaultConfig()
+}
+
+// defaultConfig returns the operator configuration given by the flags and built-in defaults
+func (r *GatewayManager) defaultConfig() OperatorConfig {
+	config := OperatorConfig{
+		GatewayClass:               r.DefaultGatewayClass,
+		ClusterIssuer:              defaultClusterIssuer,
+		IPAMZone:                   defaultIPAMZone,
+		TLSSecretTemplate:          r.TLSSecretTemplate,
+		HTTPSPort:                  httpsPort,
+		HTTPPort:                   httpPort,
+		GatewayDeletionPolicy:      r.GatewayDeletionPolicy,
+		GatewayDeletionGracePeriod: r.GatewayDeletionGracePeriod,
+		NamespaceListenerQuota:     r.NamespaceListenerQuota,
+		LogFormat:                  r.LogFormat,
+		LogLevel:                   r.LogLevel,
+		ControllerLogLevels:        r.ControllerLogLevels,
+		AccessLogZones:             r.AccessLogZones,
+	}
+	if config.GatewayClass == "" {
+		config.GatewayClass = defaultGatewayClassName
+	}
+	if config.TLSSecretTemplate == "" {
+		config.TLSSecretTemplate = defaultTLSSecretTemplate
+	}
+	if config.GatewayDeletionPolicy == "" {
+		config.GatewayDeletionPolicy = GatewayDeletionPolicyDelete
+	}
+	if config.LogFormat == "" {
+		config.LogFormat = LogFormatConsole
+	}
+	if config.LogLevel == "" {
+		config.LogLevel = "info"
+	}
+	return config
+}
+
+// parseOperatorConfig overlays the keys set in the operator ConfigMap on the default configuration.
+// Unknown keys and invalid values are rejected, so a typo doesn't silently change nothing.
+func parseOperatorConfig(defaults OperatorConfig, data map[string]string) (OperatorConfig, error) {
+	config := defaults
+	parsePort := func(key, value string) (gatewayv1.PortNumber, error) {
+		port, err := strconv.ParseInt(value, 10, 32)
+		if err != nil || port < 1 || port > 65535 {
+			return 0, fmt.Errorf("%s: %q is not a port number", key, value)
+		}
+		return gatewayv1.PortNumber(port), nil
+	}
+
+	for key, value := range data {
+		value = strings.TrimSpace(value)
+		var err error
+		switch key {
+		case configKeyGatewayClass:
+			config.GatewayClass = value
+		case configKeyClusterIssuer:
+			config.ClusterIssuer = value
+		case configKeyIPAMZone:
+			config.IPAMZone = value
+		case configKeyTLSSecretTemplate:
+			if !strings.Contains(value, hostnameTemplatePlaceholder) {
+				return defaults, fmt.Errorf("%s: %q must contain %s", key, value, hostnameTemplatePlaceholder)
+			}
+			config.TLSSecretTemplate = value
+		case configKeyHTTPSPort:
+			config.HTTPSPort, err = parsePort(key, value)
+		case configKeyHTTPPort:
+			config.HTTPPort, err = parsePort(key, value)
+		case configKeyGatewayDeletionPolicy:
+			config.GatewayDeletionPolicy = GatewayDeletionPolicy(value)
+			if !config.GatewayDeletionPolicy.IsValid() {
+				err = fmt.Errorf("%s: %q must be one of %s, %s or %s", key, value,
+					GatewayDeletionPolicyDelete, GatewayDeletionPolicyOrphan, GatewayDeletionPolicyRetainEmpty)
+			}
+		case configKeyGatewayDeletionGracePeriod:
+			config.GatewayDeletionGracePeriod, err = time.ParseDuration(value)
+			if err == nil && config.GatewayDeletionGracePeriod < 0 {
+				err = fmt.Errorf("%s: %q must not be negative", key, value)
+			}
+		case configKeyNamespaceListenerQuota:
+			config.NamespaceListenerQuota, err = strconv.Atoi(value)
+			if err != nil || config.NamespaceListenerQuota < 0 {
+				err = fmt.Errorf("%s: %q is not a non-negative number", key, value)
+			}
+		case configKeyLogFormat:
+			config.LogFormat = LogFormat(value)
+			if !config.LogFormat.IsValid() {
+				err = fmt.Errorf("%s: %q must be %s or %s", key, value, LogFormatConsole, LogFormatJSON)
+			}
+		case configKeyLogLevel:
+			config.LogLevel = value
+			if _, parseErr := parseLogLevel(value); parseErr != nil {
+				err = fmt.Errorf("%s: %w", key, parseErr)
+			}
+		case configKeyControllerLogLevels:
+			config.ControllerLogLevels = value
+			if _, parseErr := parseControllerLogLevels(value); parseErr != nil {
+				err = fmt.Errorf("%s: %w", key, parseErr)
+			}
+		case configKeyAccessLogZones:
+			config.AccessLogZones = value
+		case configKeyZoneEnvoyProxies:
+			config.ZoneEnvoyProxies = value
+			if _, parseErr := parseZoneEnvoyProxies(value); parseErr != nil {
+				err = fmt.Errorf("%s: %w", key, parseErr)
+			}
+		default:
+			err = fmt.Errorf("unknown key %q", key)
+		}
+		if err != nil {
+			return defaults, err
+		}
+	}
+
+	for key, value := range map[string]string{
+		configKeyGatewayClass:  config.GatewayClass,
+		configKeyClusterIssuer: config.ClusterIssuer,
+		configKeyIPAMZone:      config.IPAMZone,
+	} {
+		if value == "" {
+			return defaults, fmt.Errorf("%s must not be empty", key)
+		}
+	}
+	if config.HTTPSPort == config.HTTPPort {
+		return defaults, fmt.Errorf("%s and %s must differ", configKeyHTTPSPort, configKeyHTTPPort)
+	}
+	return config, nil
+}
+
+// OperatorConfigReconciler loads the operator configuration from the operator ConfigMap whenever
+// it changes. An invalid configuration is reported with a warning event on the ConfigMap and the
+// configuration in effect is kept, a deleted ConfigMap restores the flags and built-in defaults.
+// Changes apply to the following reconciles of routes and Gateways.
+type OperatorConfigReconciler struct {
+	*GatewayManager
+
+	// ConfigMap is the namespace and name of the operator ConfigMap
+	ConfigMap client.ObjectKey
+}
+
+// Reconcile loads the operator ConfigMap
+func (r *OperatorConfigReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := logf.FromContext(ctx)
+
+	var configMap corev1.ConfigMap
+	if err := r.Get(ctx, req.NamespacedName, &configMap); err != nil {
+		if client.IgnoreNotFound(err) != nil {
+			return ctrl.Result{}, err
+		}
+		if r.loadedConfig.Swap(nil) != nil {
+			log.Info("Operator ConfigMap was deleted, using the flags and built-in defaults", "configMap", req.String())
+			r.configureLogging(r.defaultConfig())
+		}
+		return ctrl.Result{}, nil
+	}
+
+	config, err := parseOperatorConfig(r.defaultConfig(), configMap.Data)
+	if err != nil {
+		log.Error(err, "Invalid operator configuration, keeping the configuration in effect", "configMap", req.String())
+		r.Recorder.Eventf(&configMap, corev1.EventTypeWarning, "InvalidConfig",
+			"Invalid operator configuration, keeping the configuration in effect: %v", err)
+		return ctrl.Result{}, nil
+	}
+
+	if previous := r.loadedConfig.Swap(&config); previous == nil || *previous != config {
+		r.configureLogging(config)
+		log.Info("Loaded operator configuration", "configMap", req.String(), "config", config)
+		r.Recorder.Event(&configMap, corev1.EventTypeNormal, "ConfigLoaded", "Loaded operator configuration")
+	}
+	return ctrl.Result{}, nil
+}
+
+// configureLogging applies the log configuration to the operator's logger. The configuration was
+// validated when it was parsed.
+func (r *OperatorConfigReconciler) configureLogging(config OperatorConfig) {
+	if r.Logging == nil {
+		return
+	}
+	_ = r.Logging.Configure(config.LogFormat, config.LogLevel, config.ControllerLogLevels)
+}
+
+// SetupWithManager sets up the controller with the Manager. Only the operator ConfigMap is reconciled.
+func (r *OperatorConfigReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	// Every replica loads the configuration, standbys serve the webhooks with it as well
+	needLeaderElection := false
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&corev1.ConfigMap{}, builder.WithPredicates(predicate.NewPredicateFuncs(func(obj client.Object) bool {
+			return client.ObjectKeyFromObject(obj) == r.ConfigMap
+		}))).
+		Named("operatorconfig").
+		WithOptions(controller.Options{
+			NeedLeaderElection: &needLeaderElection,
+		}).
+		Complete(r)
+}
@@ -0,0 +1,133 @@
+package controller
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/api/errors"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// metricsCollectTimeout bounds how long collecting the domain metrics may read from the cache
+const metricsCollectTimeout = 10 * time.Second
+
+// routeReconcileErrors counts failed route reconciles by route kind and the reason of the error
+var routeReconcileErrors = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "gatewayapi_operator_route_reconcile_errors_total",
+		Help: "Failed route reconciles by route kind and reason, BadRequest being routes the operator rejected",
+	},
+	[]string{"kind", "reason"},
+)
+
+// gatewayMismatches counts routes that couldn't use their Gateway, by what didn't match
+var gatewayMismatches = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "gatewayapi_operator_gateway_mismatches_total",
+		Help: "Routes rejected because their Gateway has another issuer, zone, class, IP family or address",
+	},
+	[]string{"mismatch"},
+)
+
+var (
+	managedGatewaysDesc = prometheus.NewDesc(
+		"gatewayapi_operator_managed_gateways",
+		"Number of Gateways managed by the operator",
+		nil, nil,
+	)
+	gatewayListenersDesc = prometheus.NewDesc(
+		"gatewayapi_operator_gateway_listeners",
+		"Number of listeners of a managed Gateway",
+		[]string{"gateway", "namespace"}, nil,
+	)
+	namespaceHostnamesDesc = prometheus.NewDesc(
+		"gatewayapi_operator_namespace_hostnames",
+		"Number of distinct hostnames of the routes in a namespace managed by the operator",
+		[]string{"namespace"}, nil,
+	)
+	gatewaysPendingDeletionDesc = prometheus.NewDesc(
+		"gatewayapi_operator_gateways_pending_deletion",
+		"Number of managed Gateways no routes reference anymore that wait for their deletion grace period",
+		nil, nil,
+	)
+)
+
+func init() {
+	metrics.Registry.MustRegister(routeReconcileErrors, gatewayMismatches)
+}
+
+// recordReconcileError counts a failed reconcile of a route
+func recordReconcileError(kind string, err error) {
+	reason := string(errors.ReasonForError(err))
+	if reason == "" {
+		reason = "Error"
+	}
+	routeReconcileErrors.WithLabelValues(kind, reason).Inc()
+}
+
+// DomainMetricsCollector exports the state of the Gateways and routes managed by the operator,
+// read from the cache on every scrape, so alerts can catch Gateways running out of listeners and
+// namespaces growing their hostnames. Nothing is exported until the cache is started.
+type DomainMetricsCollector struct {
+	*GatewayManager
+}
+
+// Describe sends the descriptors of the domain metrics
+func (c *DomainMetricsCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- managedGatewaysDesc
+	ch <- gatewayListenersDesc
+	ch <- namespaceHostnamesDesc
+	ch <- gatewaysPendingDeletionDesc
+}
+
+// Collect reads the managed Gateways and the routes from the cache and sends the domain metrics
+func (c *DomainMetricsCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), metricsCollectTimeout)
+	defer cancel()
+	log := logf.Log.WithName("metrics")
+
+	var gateways gatewayv1.GatewayList
+	if err := c.List(ctx, &gateways); err != nil {
+		log.V(1).Info("Failed to list Gateways for metrics", "error", err.Error())
+		return
+	}
+	managed, pendingDeletion := 0, 0
+	for i := range gateways.Items {
+		gateway := &gateways.Items[i]
+		if !isManagedGateway(gateway) || !c.gatewayInShard(gateway.Name, gateway.Namespace) {
+			continue
+		}
+		managed++
+		if _, exists := gateway.Annotations[emptySinceAnnotationKey]; exists {
+			pendingDeletion++
+		}
+		ch <- prometheus.MustNewConstMetric(gatewayListenersDesc, prometheus.GaugeValue,
+			float64(len(gateway.Spec.Listeners)), gateway.Name, gateway.Namespace)
+	}
+	ch <- prometheus.MustNewConstMetric(managedGatewaysDesc, prometheus.GaugeValue, float64(managed))
+	ch <- prometheus.MustNewConstMetric(gatewaysPendingDeletionDesc, prometheus.GaugeValue, float64(pendingDeletion))
+
+	routes, err := c.listRoutes(ctx)
+	if err != nil {
+		log.V(1).Info("Failed to list routes for metrics", "error", err.Error())
+		return
+	}
+	hostnames := make(map[string]map[gatewayv1.Hostname]bool)
+	for _, route := range routes {
+		if !route.Enabled || !route.GetDeletionTimestamp().IsZero() || !c.routeInShard(route.Object) {
+			continue
+		}
+		if hostnames[route.GetNamespace()] == nil {
+			hostnames[route.GetNamespace()] = make(map[gatewayv1.Hostname]bool)
+		}
+		for _, hostname := range route.Hostnames {
+			hostnames[route.GetNamespace()][hostname] = true
+		}
+	}
+	for namespace, names := range hostnames {
+		ch <- prometheus.MustNewConstMetric(namespaceHostnamesDesc, prometheus.GaugeValue, float64(len(names)), namespace)
+	}
+}
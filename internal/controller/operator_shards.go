@@ -0,0 +1,33 @@
+package controller
+
+import (
+	"hash/fnv"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// gatewayInShard reports whether this operator instance handles the Gateway. With OperatorShards
+// instances, every Gateway is handled by the instance whose OperatorShard its namespace/name hashes
+// to, so all changes to a Gateway and its listeners are made by one instance.
+func (r *GatewayManager) gatewayInShard(gatewayName, gatewayNamespace string) bool {
+	if r.OperatorShards <= 1 {
+		return true
+	}
+	hash := fnv.New32a()
+	_, _ = hash.Write([]byte(gatewayNamespace + "/" + gatewayName))
+	return int(hash.Sum32()%uint32(r.OperatorShards)) == r.OperatorShard
+}
+
+// routeInShard reports whether this operator instance handles the route: routes are handled by
+// the instance of the Gateway their first parent reference points to. Routes without parent
+// references, which may get their namespace's Gateway, are spread by namespace.
+func (r *GatewayManager) routeInShard(obj client.Object) bool {
+	route, ok := newRouteInfo(obj)
+	if !ok {
+		return false
+	}
+	if len(route.ParentRefs) == 0 {
+		return r.gatewayInShard("", route.GetNamespace())
+	}
+	return r.gatewayInShard(parentGateway(route.GetNamespace(), route.ParentRefs[0]))
+}
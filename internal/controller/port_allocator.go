@@ -0,0 +1,174 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+)
+
+// tcpPortForRoute returns the listener port recorded on a TCPRoute, if any
+func tcpPortForRoute(route client.Object) (gatewayv1.PortNumber, bool) {
+	value, exists := route.GetAnnotations()[AnnotationTCPPort]
+	if !exists {
+		return 0, false
+	}
+	port, err := strconv.ParseInt(value, 10, 32)
+	if err != nil || port < 1 || port > 65535 {
+		return 0, false
+	}
+	return gatewayv1.PortNumber(port), true
+}
+
+// tcpPortOwners maps each port claimed by TCPRoutes on a gateway to the route owning it.
+// When several routes claim the same port, the oldest route wins.
+func tcpPortOwners(routes []routeInfo, gatewayName, gatewayNamespace string) map[gatewayv1.PortNumber]client.ObjectKey {
+	claims := make([]routeInfo, 0)
+	for _, route := range routes {
+		if route.Kind != "TCPRoute" || !route.GetDeletionTimestamp().IsZero() {
+			continue
+		}
+		if !route.Enabled {
+			continue
+		}
+		if _, ok := tcpPortForRoute(route); ok && route.referencesGateway(gatewayName, gatewayNamespace) {
+			claims = append(claims, route)
+		}
+	}
+
+	// Oldest first, falling back to namespace/name so ties are resolved deterministically
+	sort.SliceStable(claims, func(i, j int) bool {
+		ti, tj := claims[i].GetCreationTimestamp(), claims[j].GetCreationTimestamp()
+		if !ti.Equal(&tj) {
+			return ti.Before(&tj)
+		}
+		return client.ObjectKeyFromObject(claims[i]).String() < client.ObjectKeyFromObject(claims[j]).String()
+	})
+
+	owners := make(map[gatewayv1.PortNumber]client.ObjectKey)
+	for _, route := range claims {
+		port, _ := tcpPortForRoute(route)
+		if _, taken := owners[port]; !taken {
+			owners[port] = client.ObjectKeyFromObject(route)
+		}
+	}
+	return owners
+}
+
+// tcpPortConflictRequeueInterval is how often a TCPRoute whose port is used by another route or
+// listener is checked again, in case the port was freed
+const tcpPortConflictRequeueInterval = 5 * time.Minute
+
+// ensureTCPPort makes sure a TCPRoute owns a listener port on its gateway.
+// A port is allocated from the configured range and recorded on the route if it has none,
+// and a port that collides with another route or listener on the gateway is rejected, see
+// rejectTCPPort. It returns false when the route was rejected.
+func (r *GatewayManager) ensureTCPPort(
+	ctx context.Context,
+	route *gatewayv1alpha2.TCPRoute,
+	gatewayName, gatewayNamespace string,
+) (bool, error) {
+	log := logf.FromContext(ctx)
+
+	routes, err := r.listGatewayRoutes(ctx, gatewayName, gatewayNamespace)
+	if err != nil {
+		return false, err
+	}
+	owners := tcpPortOwners(routes, gatewayName, gatewayNamespace)
+
+	// Ports used by listeners that don't belong to TCPRoutes, e.g. HTTPS or manually managed listeners
+	usedPorts := make(map[gatewayv1.PortNumber]bool)
+	var gateway gatewayv1.Gateway
+	if err := r.Get(ctx, client.ObjectKey{Name: gatewayName, Namespace: gatewayNamespace}, &gateway); err != nil {
+		if client.IgnoreNotFound(err) != nil {
+			return false, err
+		}
+	}
+	current, err := r.currentListeners(ctx, &gateway)
+	if err != nil {
+		return false, err
+	}
+	for _, listener := range current {
+		if !strings.HasPrefix(string(listener.Name), tcpListenerPrefix) {
+			usedPorts[listener.Port] = true
+		}
+	}
+	for port := range owners {
+		usedPorts[port] = true
+	}
+
+	// The HTTP and HTTPS ports are reserved for the operator's listeners, e.g. the HTTP listeners of
+	// HTTPS redirects and the parent listener in ListenerSet mode, which may not be applied yet
+	config := r.config()
+	usedPorts[config.HTTPPort] = true
+	usedPorts[config.HTTPSPort] = true
+
+	routeKey := client.ObjectKeyFromObject(route)
+	if port, ok := tcpPortForRoute(route); ok {
+		if port == config.HTTPPort || port == config.HTTPSPort {
+			return false, r.rejectTCPPort(ctx, route, gatewayName, gatewayNamespace,
+				fmt.Sprintf("port %d on Gateway %s/%s is reserved for HTTP and HTTPS listeners", port, gatewayNamespace, gatewayName))
+		}
+		if owner, taken := owners[port]; taken && owner != routeKey {
+			return false, r.rejectTCPPort(ctx, route, gatewayName, gatewayNamespace,
+				fmt.Sprintf("port %d on Gateway %s/%s is already used by TCPRoute %s", port, gatewayNamespace, gatewayName, owner))
+		}
+		for _, listener := range current {
+			if listener.Port == port && !strings.HasPrefix(string(listener.Name), tcpListenerPrefix) {
+				return false, r.rejectTCPPort(ctx, route, gatewayName, gatewayNamespace,
+					fmt.Sprintf("port %d on Gateway %s/%s is already used by listener %s", port, gatewayNamespace, gatewayName, listener.Name))
+			}
+		}
+		return true, nil
+	}
+
+	// Allocate the lowest free port in the configured range
+	for port := r.TCPPortRangeStart; port <= r.TCPPortRangeEnd; port++ {
+		if usedPorts[port] {
+			continue
+		}
+
+		patch := client.MergeFrom(route.DeepCopy())
+		if route.Annotations == nil {
+			route.Annotations = make(map[string]string)
+		}
+		route.Annotations[AnnotationTCPPort] = strconv.Itoa(int(port))
+		if err := r.Patch(ctx, route, patch); err != nil {
+			log.Error(err, "Failed to record allocated TCP port", "route", route.Name, "port", port)
+			return false, err
+		}
+		log.Info("Allocated TCP port for TCPRoute", "route", route.Name, "namespace", route.Namespace, "port", port, "gateway", gatewayName)
+		return true, nil
+	}
+
+	err = errors.NewBadRequest(fmt.Sprintf("no free TCP port in range %d-%d on Gateway %s/%s", r.TCPPortRangeStart, r.TCPPortRangeEnd, gatewayNamespace, gatewayName))
+	log.Error(err, "TCP port range exhausted", "route", route.Name, "gateway", gatewayName)
+	return false, err
+}
+
+// rejectTCPPort rejects a TCPRoute losing a port collision: the route gets a TCPPortConflict warning
+// event, the reason in its rejected annotation and GatewayEnsured=False with reason Rejected. The
+// route isn't retried with backoff, the port stays taken until its owner goes away.
+func (r *GatewayManager) rejectTCPPort(
+	ctx context.Context,
+	route *gatewayv1alpha2.TCPRoute,
+	gatewayName, gatewayNamespace, message string,
+) error {
+	err := errors.NewBadRequest("TCPRoute port collision: " + message)
+	logf.FromContext(ctx).Info("Rejecting TCPRoute with a port collision", "route", route.Name, "namespace", route.Namespace,
+		"reason", message)
+	r.Recorder.Eventf(route, corev1.EventTypeWarning, "TCPPortConflict", "Rejected: %s", message)
+	if err := r.setRejectedAnnotation(ctx, route, message); err != nil {
+		return err
+	}
+	return r.syncRouteStatus(ctx, route, gatewayName, gatewayNamespace, err)
+}
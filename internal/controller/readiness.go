@@ -0,0 +1,36 @@
+package controller
+
+import (
+	"fmt"
+	"net/http"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/healthz"
+)
+
+// ConvergedCheck returns a readiness check that passes once the caches are synced and, on the
+// leader, the startup pass has reconciled every enabled route and managed Gateway, so rollouts can
+// wait for the operator to have converged rather than for the process to be up. Replicas that
+// aren't the leader don't reconcile and are ready once their caches are synced. Once passed the
+// check keeps passing, so a Gateway failing later doesn't take the webhooks out of service.
+func (r *GatewayManager) ConvergedCheck(mgr ctrl.Manager) healthz.Checker {
+	return func(req *http.Request) error {
+		if r.converged.Load() {
+			return nil
+		}
+		if !mgr.GetCache().WaitForCacheSync(req.Context()) {
+			return fmt.Errorf("caches are not synced")
+		}
+		select {
+		case <-mgr.Elected():
+		default:
+			return nil
+		}
+
+		if err := r.startupProgress(); err != nil {
+			return err
+		}
+		r.converged.Store(true)
+		return nil
+	}
+}
@@ -0,0 +1,268 @@
+package controller
+
+import (
+	"context"
+	"slices"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+)
+
+// +kubebuilder:rbac:groups=gateway.networking.k8s.io,resources=referencegrants,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+
+// ensureRouteReferenceGrant checks that a ReferenceGrant in the gateway's namespace allows
+// routes of the given kind from the route's namespace to attach to the gateway.
+// A missing grant is created when CreateReferenceGrants is enabled, otherwise a warning
+// event is emitted on the route since the attachment may be rejected.
+func (r *GatewayManager) ensureRouteReferenceGrant(
+	ctx context.Context,
+	route client.Object,
+	kind string,
+	gatewayName, gatewayNamespace string,
+) error {
+	log := logf.FromContext(ctx)
+
+	var grants gatewayv1beta1.ReferenceGrantList
+	if err := r.List(ctx, &grants, client.InNamespace(gatewayNamespace)); err != nil {
+		return err
+	}
+	for _, grant := range grants.Items {
+		if referenceGrantAllows(grant, kind, route.GetNamespace(), gatewayName) {
+			return nil
+		}
+	}
+
+	if !r.CreateReferenceGrants {
+		log.Info("No ReferenceGrant allows the route to attach to the gateway",
+			"route", route.GetName(), "namespace", route.GetNamespace(), "gateway", gatewayName, "gatewayNamespace", gatewayNamespace)
+		r.Recorder.Eventf(route, corev1.EventTypeWarning, "ReferenceGrantMissing",
+			"No ReferenceGrant in namespace %s allows %s from namespace %s to attach to Gateway %s, the attachment may be rejected",
+			gatewayNamespace, kind, route.GetNamespace(), gatewayName)
+		return nil
+	}
+
+	// Add the route kind to the operator's grant for the route namespace, creating it if needed
+	grant := &gatewayv1beta1.ReferenceGrant{}
+	grantKey := client.ObjectKey{Name: referenceGrantPrefix + route.GetNamespace(), Namespace: gatewayNamespace}
+	if err := r.Get(ctx, grantKey, grant); err != nil {
+		if client.IgnoreNotFound(err) != nil {
+			return err
+		}
+		grant = &gatewayv1beta1.ReferenceGrant{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      grantKey.Name,
+				Namespace: grantKey.Namespace,
+				Labels: map[string]string{
+					managedByLabel: managedByValue,
+				},
+			},
+			Spec: gatewayv1beta1.ReferenceGrantSpec{
+				To: []gatewayv1beta1.ReferenceGrantTo{
+					{Group: gatewayv1.GroupName, Kind: "Gateway"},
+				},
+			},
+		}
+	}
+
+	grant.Spec.From = append(grant.Spec.From, gatewayv1beta1.ReferenceGrantFrom{
+		Group:     gatewayv1.GroupName,
+		Kind:      gatewayv1.Kind(kind),
+		Namespace: gatewayv1.Namespace(route.GetNamespace()),
+	})
+
+	if grant.ResourceVersion == "" {
+		if err := r.Create(ctx, grant); err != nil {
+			return err
+		}
+	} else if err := r.Update(ctx, grant); err != nil {
+		return err
+	}
+
+	log.Info("Created ReferenceGrant for cross-namespace route", "referenceGrant", grantKey.String(), "kind", kind, "routeNamespace", route.GetNamespace())
+	r.Recorder.Eventf(route, corev1.EventTypeNormal, "ReferenceGrantCreated",
+		"Created ReferenceGrant %s allowing %s from namespace %s to attach to Gateways in namespace %s",
+		grantKey.String(), kind, route.GetNamespace(), gatewayNamespace)
+	return nil
+}
+
+// referenceGrantAllows reports whether a grant allows routes of a kind from a namespace to reference the gateway
+func referenceGrantAllows(grant gatewayv1beta1.ReferenceGrant, kind, routeNamespace, gatewayName string) bool {
+	fromAllowed := false
+	for _, from := range grant.Spec.From {
+		if from.Group == gatewayv1.GroupName && string(from.Kind) == kind && string(from.Namespace) == routeNamespace {
+			fromAllowed = true
+			break
+		}
+	}
+	if !fromAllowed {
+		return false
+	}
+
+	for _, to := range grant.Spec.To {
+		if to.Group == gatewayv1.GroupName && to.Kind == "Gateway" && (to.Name == nil || string(*to.Name) == gatewayName) {
+			return true
+		}
+	}
+	return false
+}
+
+// pruneReferenceGrants deletes the operator's ReferenceGrants in a gateway namespace
+// that no longer have routes from the granted namespace attaching to gateways there
+func (r *GatewayManager) pruneReferenceGrants(ctx context.Context, gatewayNamespace string) error {
+	log := logf.FromContext(ctx)
+
+	var grants gatewayv1beta1.ReferenceGrantList
+	if err := r.List(ctx, &grants, client.InNamespace(gatewayNamespace), client.MatchingLabels{managedByLabel: managedByValue}); err != nil {
+		return err
+	}
+	if len(grants.Items) == 0 {
+		return nil
+	}
+
+	routes, err := r.listRoutes(ctx, client.MatchingFields{gatewayNamespaceIndexKey: gatewayNamespace})
+	if err != nil {
+		return err
+	}
+
+	// Namespaces with active routes attaching to gateways in this namespace
+	inUse := make(map[string]bool)
+	for _, route := range routes {
+		if !route.GetDeletionTimestamp().IsZero() || !route.Enabled {
+			continue
+		}
+		for _, parentRef := range route.ParentRefs {
+			if _, refNamespace := parentGateway(route.GetNamespace(), parentRef); refNamespace == gatewayNamespace {
+				inUse[route.GetNamespace()] = true
+			}
+		}
+	}
+
+	for i := range grants.Items {
+		grant := &grants.Items[i]
+		// Only grants allowing routes to attach to gateways, not grants for certificate secrets
+		if !slices.ContainsFunc(grant.Spec.To, func(to gatewayv1beta1.ReferenceGrantTo) bool { return to.Kind == "Gateway" }) {
+			continue
+		}
+		stale := true
+		for _, from := range grant.Spec.From {
+			if inUse[string(from.Namespace)] {
+				stale = false
+				break
+			}
+		}
+		if !stale {
+			continue
+		}
+		if err := r.Delete(ctx, grant); client.IgnoreNotFound(err) != nil {
+			return err
+		}
+		log.Info("Deleted unused ReferenceGrant", "referenceGrant", client.ObjectKeyFromObject(grant).String())
+	}
+	return nil
+}
+
+// syncSecretReferenceGrants makes sure the gateway may reference the certificate secrets its
+// listeners use in other namespaces. The operator keeps one ReferenceGrant per gateway in each
+// of those namespaces, and deletes the gateway's grants in namespaces no longer referenced.
+func (r *GatewayManager) syncSecretReferenceGrants(
+	ctx context.Context,
+	gatewayName, gatewayNamespace string,
+	listeners []gatewayv1.Listener,
+) error {
+	log := logf.FromContext(ctx)
+	grantName := secretReferenceGrantPrefix + gatewayNamespace + "-" + gatewayName
+
+	// Secrets referenced in other namespaces, by namespace
+	secrets := make(map[string][]string)
+	for _, listener := range listeners {
+		if listener.TLS == nil {
+			continue
+		}
+		for _, ref := range listener.TLS.CertificateRefs {
+			if ref.Namespace == nil || string(*ref.Namespace) == gatewayNamespace {
+				continue
+			}
+			namespace := string(*ref.Namespace)
+			if !slices.Contains(secrets[namespace], string(ref.Name)) {
+				secrets[namespace] = append(secrets[namespace], string(ref.Name))
+			}
+		}
+	}
+
+	// Remove the gateway's grants in namespaces that are no longer referenced
+	var grants gatewayv1beta1.ReferenceGrantList
+	if err := r.List(ctx, &grants, client.MatchingLabels{managedByLabel: managedByValue}); err != nil {
+		return err
+	}
+	for i := range grants.Items {
+		grant := &grants.Items[i]
+		if grant.Name != grantName {
+			continue
+		}
+		if _, needed := secrets[grant.Namespace]; needed {
+			continue
+		}
+		if err := r.Delete(ctx, grant); client.IgnoreNotFound(err) != nil {
+			return err
+		}
+		log.Info("Deleted unused ReferenceGrant for certificates", "referenceGrant", client.ObjectKeyFromObject(grant).String())
+	}
+
+	for namespace, names := range secrets {
+		slices.Sort(names)
+		to := make([]gatewayv1beta1.ReferenceGrantTo, 0, len(names))
+		for _, name := range names {
+			to = append(to, gatewayv1beta1.ReferenceGrantTo{
+				Group: "",
+				Kind:  "Secret",
+				Name:  (*gatewayv1.ObjectName)(ptr(name)),
+			})
+		}
+		from := []gatewayv1beta1.ReferenceGrantFrom{
+			{
+				Group:     gatewayv1.GroupName,
+				Kind:      "Gateway",
+				Namespace: gatewayv1.Namespace(gatewayNamespace),
+			},
+		}
+
+		grant := &gatewayv1beta1.ReferenceGrant{}
+		if err := r.Get(ctx, client.ObjectKey{Name: grantName, Namespace: namespace}, grant); err != nil {
+			if client.IgnoreNotFound(err) != nil {
+				return err
+			}
+			grant = &gatewayv1beta1.ReferenceGrant{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      grantName,
+					Namespace: namespace,
+					Labels: map[string]string{
+						managedByLabel: managedByValue,
+					},
+				},
+				Spec: gatewayv1beta1.ReferenceGrantSpec{From: from, To: to},
+			}
+			if err := r.Create(ctx, grant); err != nil {
+				return err
+			}
+			log.Info("Created ReferenceGrant for certificates", "referenceGrant", client.ObjectKeyFromObject(grant).String(), "secrets", len(names))
+			continue
+		}
+
+		if equality.Semantic.DeepEqual(grant.Spec.To, to) && equality.Semantic.DeepEqual(grant.Spec.From, from) {
+			continue
+		}
+		grant.Spec.From = from
+		grant.Spec.To = to
+		if err := r.Update(ctx, grant); err != nil {
+			return err
+		}
+		log.Info("Updated ReferenceGrant for certificates", "referenceGrant", client.ObjectKeyFromObject(grant).String(), "secrets", len(names))
+	}
+	return nil
+}
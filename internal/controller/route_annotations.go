@@ -0,0 +1,81 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// routeAnnotationsPatch returns the merge patch marking a route as reconciled, or nil when there
+// is nothing to change. The patch only touches the operator's annotations, so it never takes over
+// fields of the route's owner, e.g. a GitOps tool applying the route.
+//
+// Older versions applied the route's annotations with Server-Side Apply, which left the operator
+// owning all of them and conflicting with the owner's applies. The patch removes that managed
+// fields entry, guarded by the route's resource version, and the annotation of the previous
+// gateway those versions recorded. The cache only keeps that entry of a route's managed fields, see
+// RouteCacheTransform, so the full list the patch is computed from is read from the API server.
+func (r *GatewayManager) routeAnnotationsPatch(ctx context.Context, route client.Object) ([]byte, error) {
+	annotations := make(map[string]any)
+	if _, exists := route.GetAnnotations()[reconcileAnnotationKey]; !exists {
+		annotations[reconcileAnnotationKey] = "true"
+	}
+	if _, exists := route.GetAnnotations()[legacyPreviousGatewayAnnotationKey]; exists {
+		annotations[legacyPreviousGatewayAnnotationKey] = nil
+	}
+
+	metadata := make(map[string]any)
+	if len(legacyManagedFields(route.GetManagedFields())) > 0 {
+		current, ok := route.DeepCopyObject().(client.Object)
+		if !ok {
+			return nil, nil
+		}
+		if err := r.APIReader.Get(ctx, client.ObjectKeyFromObject(route), current); err != nil {
+			return nil, err
+		}
+		route = current
+	}
+	managedFields := make([]metav1.ManagedFieldsEntry, 0, len(route.GetManagedFields()))
+	for _, entry := range route.GetManagedFields() {
+		if isLegacyManagedFieldsEntry(entry) {
+			continue
+		}
+		managedFields = append(managedFields, entry)
+	}
+	if len(managedFields) < len(route.GetManagedFields()) {
+		// An empty list leaves the managed fields as they are, a single empty entry clears them
+		if len(managedFields) == 0 {
+			metadata["managedFields"] = []map[string]any{{}}
+		} else {
+			metadata["managedFields"] = managedFields
+		}
+		metadata["resourceVersion"] = route.GetResourceVersion()
+	}
+
+	if len(annotations) == 0 && len(metadata) == 0 {
+		return nil, nil
+	}
+	if len(annotations) > 0 {
+		metadata["annotations"] = annotations
+	}
+	return json.Marshal(map[string]any{"metadata": metadata})
+}
+
+// isLegacyManagedFieldsEntry reports whether a managed fields entry is the one older versions of the
+// operator left on routes by applying their annotations
+func isLegacyManagedFieldsEntry(entry metav1.ManagedFieldsEntry) bool {
+	return entry.Manager == fieldManager && entry.Operation == metav1.ManagedFieldsOperationApply && entry.Subresource == ""
+}
+
+// legacyManagedFields returns the managed fields entries older versions of the operator left on a route
+func legacyManagedFields(entries []metav1.ManagedFieldsEntry) []metav1.ManagedFieldsEntry {
+	var legacy []metav1.ManagedFieldsEntry
+	for _, entry := range entries {
+		if isLegacyManagedFieldsEntry(entry) {
+			legacy = append(legacy, entry)
+		}
+	}
+	return legacy
+}
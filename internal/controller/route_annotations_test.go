@@ -0,0 +1,114 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// routeReader serves a single route, as the API server holds it
+type routeReader struct {
+	client.Reader
+	route *gatewayv1.HTTPRoute
+}
+
+func (r routeReader) Get(_ context.Context, _ client.ObjectKey, obj client.Object, _ ...client.GetOption) error {
+	r.route.DeepCopyInto(obj.(*gatewayv1.HTTPRoute))
+	return nil
+}
+
+// TestRouteAnnotationsPatchGitOpsRoute checks that the managed fields entry of older operator
+// versions is removed from a route applied by a GitOps tool, even though the cache drops the
+// managed fields of routes, and that the GitOps tool's entry is kept
+func TestRouteAnnotationsPatchGitOpsRoute(t *testing.T) {
+	gitOps := metav1.ManagedFieldsEntry{
+		Manager:   "argocd-controller",
+		Operation: metav1.ManagedFieldsOperationApply,
+	}
+	legacy := metav1.ManagedFieldsEntry{
+		Manager:   fieldManager,
+		Operation: metav1.ManagedFieldsOperationApply,
+	}
+	status := metav1.ManagedFieldsEntry{
+		Manager:     fieldManager,
+		Operation:   metav1.ManagedFieldsOperationUpdate,
+		Subresource: "status",
+	}
+	stored := &gatewayv1.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "app",
+			Namespace:       "team",
+			ResourceVersion: "42",
+			Annotations: map[string]string{
+				AnnotationUseHttprouteOperator: "true",
+				reconcileAnnotationKey:         "true",
+			},
+			ManagedFields: []metav1.ManagedFieldsEntry{gitOps, legacy, status},
+		},
+	}
+
+	cached, err := RouteCacheTransform(labels.Nothing(), false)(stored.DeepCopy())
+	if err != nil {
+		t.Fatal(err)
+	}
+	route := cached.(*gatewayv1.HTTPRoute)
+	if len(route.ManagedFields) != 1 || route.ManagedFields[0].Manager != fieldManager {
+		t.Fatalf("cached managed fields = %v, want only the legacy entry", route.ManagedFields)
+	}
+
+	r := &GatewayManager{APIReader: routeReader{route: stored}}
+	patch, err := r.routeAnnotationsPatch(context.Background(), route)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if patch == nil {
+		t.Fatal("patch = nil, want the legacy managed fields entry removed")
+	}
+	var got struct {
+		Metadata struct {
+			ManagedFields   []metav1.ManagedFieldsEntry `json:"managedFields"`
+			ResourceVersion string                      `json:"resourceVersion"`
+			Annotations     map[string]any              `json:"annotations"`
+		} `json:"metadata"`
+	}
+	if err := json.Unmarshal(patch, &got); err != nil {
+		t.Fatal(err)
+	}
+	if len(got.Metadata.ManagedFields) != 2 ||
+		got.Metadata.ManagedFields[0].Manager != gitOps.Manager ||
+		got.Metadata.ManagedFields[1].Subresource != status.Subresource {
+		t.Errorf("patched managed fields = %v, want the GitOps and status entries", got.Metadata.ManagedFields)
+	}
+	if got.Metadata.ResourceVersion != stored.ResourceVersion {
+		t.Errorf("patch resource version = %q, want %q", got.Metadata.ResourceVersion, stored.ResourceVersion)
+	}
+	if len(got.Metadata.Annotations) != 0 {
+		t.Errorf("patched annotations = %v, want none", got.Metadata.Annotations)
+	}
+}
+
+// TestRouteAnnotationsPatchMigratedRoute checks that a route without the legacy entry is neither
+// read from the API server nor patched
+func TestRouteAnnotationsPatchMigratedRoute(t *testing.T) {
+	route := &gatewayv1.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "app",
+			Namespace:   "team",
+			Annotations: map[string]string{reconcileAnnotationKey: "true"},
+		},
+	}
+
+	r := &GatewayManager{}
+	patch, err := r.routeAnnotationsPatch(context.Background(), route)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if patch != nil {
+		t.Errorf("patch = %s, want nil", patch)
+	}
+}
@@ -0,0 +1,126 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+)
+
+// +kubebuilder:webhook:path=/mutate-gateway-networking-k8s-io-v1-httproute,mutating=true,failurePolicy=ignore,sideEffects=None,groups=gateway.networking.k8s.io,resources=httproutes,verbs=create;update,versions=v1,name=mhttproute-v1.gatewayapi-operator.vitistack.io,admissionReviewVersions=v1
+// +kubebuilder:webhook:path=/mutate-gateway-networking-k8s-io-v1-grpcroute,mutating=true,failurePolicy=ignore,sideEffects=None,groups=gateway.networking.k8s.io,resources=grpcroutes,verbs=create;update,versions=v1,name=mgrpcroute-v1.gatewayapi-operator.vitistack.io,admissionReviewVersions=v1
+// +kubebuilder:webhook:path=/mutate-gateway-networking-k8s-io-v1alpha2-tlsroute,mutating=true,failurePolicy=ignore,sideEffects=None,groups=gateway.networking.k8s.io,resources=tlsroutes,verbs=create;update,versions=v1alpha2,name=mtlsroute-v1alpha2.gatewayapi-operator.vitistack.io,admissionReviewVersions=v1
+// +kubebuilder:webhook:path=/mutate-gateway-networking-k8s-io-v1alpha2-tcproute,mutating=true,failurePolicy=ignore,sideEffects=None,groups=gateway.networking.k8s.io,resources=tcproutes,verbs=create;update,versions=v1alpha2,name=mtcproute-v1alpha2.gatewayapi-operator.vitistack.io,admissionReviewVersions=v1
+
+// RouteDefaulter is a mutating admission webhook filling in the defaults of the routes the
+// operator manages when they are created or updated, so application teams don't have to repeat
+// them on every route. Routes get the IPAM zone and issuer annotations they would be reconciled
+// with, unless they reference a GatewayProfile, and routes without parent references are attached
+// to their namespace's standard Gateway. Annotations set on the route are never overwritten.
+type RouteDefaulter struct {
+	*GatewayManager
+}
+
+// defaultGateway returns the name and namespace of the standard Gateway of a namespace: its
+// Gateway in gateway-per-namespace mode, or the Gateway named by the namespace's default-gateway
+// annotation. It reports false when the namespace has none.
+func (r *GatewayManager) defaultGateway(ctx context.Context, namespace string) (string, string, bool) {
+	if r.NamespaceGatewayTemplate != "" {
+		return r.namespaceGatewayName(namespace), namespace, true
+	}
+	value := r.namespaceDefaults(ctx, namespace)[AnnotationDefaultGateway]
+	if value == "" {
+		return "", "", false
+	}
+	if gatewayNamespace, gatewayName, found := strings.Cut(value, "/"); found {
+		return gatewayName, gatewayNamespace, true
+	}
+	return value, namespace, true
+}
+
+// Default fills in the defaults of a route
+func (d *RouteDefaulter) Default(ctx context.Context, obj runtime.Object) error {
+	route, ok := obj.(client.Object)
+	if !ok {
+		return fmt.Errorf("expected a route, got %T", obj)
+	}
+	info, ok := newRouteInfo(route)
+	if !ok {
+		return fmt.Errorf("expected a route, got %T", obj)
+	}
+	// The namespace of a created object may only be given by the request
+	if route.GetNamespace() == "" {
+		if req, err := admission.RequestFromContext(ctx); err == nil {
+			route.SetNamespace(req.Namespace)
+		}
+	}
+	if !route.GetDeletionTimestamp().IsZero() || !d.inNamespaceScope(ctx, route.GetNamespace()) || !d.routeEnabled(ctx, route) {
+		return nil
+	}
+	log := logf.FromContext(ctx).WithValues("kind", info.Kind, "route", route.GetName(), "namespace", route.GetNamespace())
+
+	annotations := route.GetAnnotations()
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+	if annotations[AnnotationProfile] == "" {
+		if annotations[AnnotationIPAMZone] == "" {
+			annotations[AnnotationIPAMZone] = d.routeIPAMZone(ctx, route)
+			log.V(1).Info("Defaulted IPAM zone", "ipamZone", annotations[AnnotationIPAMZone])
+		}
+		if annotations[AnnotationClusterIssuer] == "" {
+			issuer := d.routeIssuer(ctx, route)
+			annotations[AnnotationClusterIssuer] = issuer.Name
+			annotations[AnnotationIssuerKind] = issuer.Kind
+			log.V(1).Info("Defaulted issuer", "issuer", issuer.String())
+		}
+		route.SetAnnotations(annotations)
+	}
+
+	if len(info.ParentRefs) == 0 {
+		gatewayName, gatewayNamespace, exists := d.defaultGateway(ctx, route.GetNamespace())
+		if !exists {
+			return nil
+		}
+		parentRef := gatewayv1.ParentReference{Name: gatewayv1.ObjectName(gatewayName)}
+		if gatewayNamespace != route.GetNamespace() {
+			parentRef.Namespace = (*gatewayv1.Namespace)(&gatewayNamespace)
+		}
+		switch route := route.(type) {
+		case *gatewayv1.HTTPRoute:
+			route.Spec.ParentRefs = []gatewayv1.ParentReference{parentRef}
+		case *gatewayv1.GRPCRoute:
+			route.Spec.ParentRefs = []gatewayv1.ParentReference{parentRef}
+		case *gatewayv1alpha2.TLSRoute:
+			route.Spec.ParentRefs = []gatewayv1.ParentReference{parentRef}
+		case *gatewayv1alpha2.TCPRoute:
+			route.Spec.ParentRefs = []gatewayv1.ParentReference{parentRef}
+		}
+		log.Info("Defaulted parent reference to the namespace's standard Gateway", "gateway", gatewayNamespace+"/"+gatewayName)
+	}
+	return nil
+}
+
+// SetupWithManager registers the webhook for the route kinds the operator manages
+func (d *RouteDefaulter) SetupWithManager(mgr ctrl.Manager) error {
+	routes := []runtime.Object{&gatewayv1.HTTPRoute{}, &gatewayv1.GRPCRoute{}}
+	if d.EnableTLSRoutes {
+		routes = append(routes, &gatewayv1alpha2.TLSRoute{})
+	}
+	if d.EnableTCPRoutes {
+		routes = append(routes, &gatewayv1alpha2.TCPRoute{})
+	}
+	for _, route := range routes {
+		if err := ctrl.NewWebhookManagedBy(mgr).For(route).WithDefaulter(d).Complete(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+package controller
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	toolscache "k8s.io/client-go/tools/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+)
+
+// selectorSet reports whether a label selector given at startup selects anything
+func selectorSet(selector labels.Selector) bool {
+	return selector != nil && !selector.Empty()
+}
+
+// namespaceMatches reports whether the labels of a namespace match a selector. A namespace that
+// can't be read doesn't match.
+func (r *GatewayManager) namespaceMatches(ctx context.Context, namespace string, selector labels.Selector) bool {
+	var ns corev1.Namespace
+	if err := r.Get(ctx, client.ObjectKey{Name: namespace}, &ns); err != nil {
+		logf.FromContext(ctx).Error(err, "Failed to get namespace labels", "namespace", namespace)
+		return false
+	}
+	return selector.Matches(labels.Set(ns.Labels))
+}
+
+// routeEnabled reports whether the operator manages a route: routes opt in with the enabled
+// annotation set to true, or are enrolled by the route selector or by the selector of enabled
+// namespaces. Setting the annotation to false opts an enrolled route out.
+func (r *GatewayManager) routeEnabled(ctx context.Context, route client.Object) bool {
+	switch route.GetAnnotations()[AnnotationUseHttprouteOperator] {
+	case "true":
+		return true
+	case "false":
+		return false
+	}
+	if selectorSet(r.RouteSelector) && r.RouteSelector.Matches(labels.Set(route.GetLabels())) {
+		return true
+	}
+	return selectorSet(r.EnabledNamespaceSelector) && r.namespaceMatches(ctx, route.GetNamespace(), r.EnabledNamespaceSelector)
+}
+
+// RouteCacheTransform returns a cache transform that drops the managed fields of routes, except the
+// entry older versions of the operator left, which routeAnnotationsPatch removes, and keeps only
+// the metadata of routes the operator doesn't manage, so clusters with many unrelated routes
+// don't fill the operator's memory. Routes are enabled by their annotation or the route selector,
+// which the transform can tell from the route alone. When namespaces are enrolled by their labels
+// every route may be managed, and only the managed fields are dropped. A route that gets enabled
+// is stored in full again with the update enabling it.
+func RouteCacheTransform(routeSelector labels.Selector, namespacesEnrolled bool) toolscache.TransformFunc {
+	return func(obj any) (any, error) {
+		route, ok := obj.(client.Object)
+		if !ok {
+			return obj, nil
+		}
+		route.SetManagedFields(legacyManagedFields(route.GetManagedFields()))
+
+		switch route.GetAnnotations()[AnnotationUseHttprouteOperator] {
+		case "true":
+			return route, nil
+		case "false":
+		default:
+			if namespacesEnrolled || (selectorSet(routeSelector) && routeSelector.Matches(labels.Set(route.GetLabels()))) {
+				return route, nil
+			}
+		}
+
+		switch route := route.(type) {
+		case *gatewayv1.HTTPRoute:
+			route.Spec, route.Status = gatewayv1.HTTPRouteSpec{}, gatewayv1.HTTPRouteStatus{}
+		case *gatewayv1.GRPCRoute:
+			route.Spec, route.Status = gatewayv1.GRPCRouteSpec{}, gatewayv1.GRPCRouteStatus{}
+		case *gatewayv1alpha2.TLSRoute:
+			route.Spec, route.Status = gatewayv1alpha2.TLSRouteSpec{}, gatewayv1alpha2.TLSRouteStatus{}
+		case *gatewayv1alpha2.TCPRoute:
+			route.Spec, route.Status = gatewayv1alpha2.TCPRouteSpec{}, gatewayv1alpha2.TCPRouteStatus{}
+		}
+		return route, nil
+	}
+}
@@ -0,0 +1,401 @@
+package controller
+
+import (
+	"context"
+	"errors"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+)
+
+// routeInfo is a kind-agnostic view of a route that can contribute listeners to a Gateway
+type routeInfo struct {
+	client.Object
+	Kind       string
+	ParentRefs []gatewayv1.ParentReference
+	Hostnames  []gatewayv1.Hostname
+
+	// InvalidHostnames are the hostnames of the route that aren't valid DNS names. They are left
+	// out of Hostnames, which holds the valid hostnames normalized by normalizeHostname
+	InvalidHostnames []string
+
+	// Enabled tells whether the operator manages the route, see routeEnabled. It is only set for
+	// routes returned by listRoutes
+	Enabled bool
+}
+
+// parentGateway returns the name and namespace of the Gateway a parent reference points to.
+// A parent reference without a namespace refers to the route's own namespace.
+func parentGateway(routeNamespace string, parentRef gatewayv1.ParentReference) (string, string) {
+	gatewayNamespace := routeNamespace
+	if parentRef.Namespace != nil {
+		gatewayNamespace = string(*parentRef.Namespace)
+	}
+	return string(parentRef.Name), gatewayNamespace
+}
+
+// parentRefsFor returns the route's parent references that point to the gateway
+func (ri routeInfo) parentRefsFor(gatewayName, gatewayNamespace string) []gatewayv1.ParentReference {
+	var parentRefs []gatewayv1.ParentReference
+	for _, parentRef := range ri.ParentRefs {
+		refName, refNamespace := parentGateway(ri.GetNamespace(), parentRef)
+		if refName == gatewayName && refNamespace == gatewayNamespace {
+			parentRefs = append(parentRefs, parentRef)
+		}
+	}
+	return parentRefs
+}
+
+// referencesGateway reports whether any of the route's parent references point to the gateway
+func (ri routeInfo) referencesGateway(gatewayName, gatewayNamespace string) bool {
+	return len(ri.parentRefsFor(gatewayName, gatewayNamespace)) > 0
+}
+
+// newRouteInfo returns the kind-agnostic view of a route object of one of the supported kinds
+func newRouteInfo(obj client.Object) (routeInfo, bool) {
+	var info routeInfo
+	switch route := obj.(type) {
+	case *gatewayv1.HTTPRoute:
+		info = routeInfo{Object: route, Kind: "HTTPRoute", ParentRefs: route.Spec.ParentRefs, Hostnames: httpRouteHostnames(route)}
+	case *gatewayv1.GRPCRoute:
+		info = routeInfo{Object: route, Kind: "GRPCRoute", ParentRefs: route.Spec.ParentRefs, Hostnames: grpcRouteHostnames(route)}
+	case *gatewayv1alpha2.TLSRoute:
+		info = routeInfo{Object: route, Kind: "TLSRoute", ParentRefs: route.Spec.ParentRefs, Hostnames: route.Spec.Hostnames}
+	case *gatewayv1alpha2.TCPRoute:
+		info = routeInfo{Object: route, Kind: "TCPRoute", ParentRefs: route.Spec.ParentRefs}
+	default:
+		return routeInfo{}, false
+	}
+	info.Hostnames, info.InvalidHostnames = normalizeHostnames(info.Hostnames)
+	return info, true
+}
+
+// listRoutes lists all routes of the supported kinds in the namespaces the operator manages
+func (r *GatewayManager) listRoutes(ctx context.Context, opts ...client.ListOption) ([]routeInfo, error) {
+	lists := []client.ObjectList{&gatewayv1.HTTPRouteList{}, &gatewayv1.GRPCRouteList{}}
+	if r.EnableTLSRoutes {
+		lists = append(lists, &gatewayv1alpha2.TLSRouteList{})
+	}
+	if r.EnableTCPRoutes {
+		lists = append(lists, &gatewayv1alpha2.TCPRouteList{})
+	}
+
+	var routes []routeInfo
+	for _, list := range lists {
+		if err := r.List(ctx, list, opts...); err != nil {
+			return nil, err
+		}
+		if err := meta.EachListItem(list, func(obj runtime.Object) error {
+			if route, ok := newRouteInfo(obj.(client.Object)); ok {
+				route.Enabled = r.routeEnabled(ctx, route)
+				routes = append(routes, route)
+			}
+			return nil
+		}); err != nil {
+			return nil, err
+		}
+	}
+	return r.inScopeRoutes(ctx, routes), nil
+}
+
+// reconcileRoute runs the shared reconciliation for a route of any supported kind:
+// finalizer handling, gateway change tracking and ensuring the referenced Gateway exists.
+// The listeners are computed by the Gateway reconciler, which route changes enqueue.
+func (r *GatewayManager) reconcileRoute(
+	ctx context.Context,
+	route client.Object,
+	parentRefs []gatewayv1.ParentReference,
+) (_ ctrl.Result, err error) {
+	log := logf.FromContext(ctx)
+	gvk, err := apiutil.GVKForObject(route, r.Scheme)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	defer func() {
+		if err != nil {
+			recordReconcileError(gvk.Kind, err)
+		}
+		r.markStartupReconciled(gvk.Kind, client.ObjectKeyFromObject(route))
+	}()
+
+	if shuttingDown(ctx) {
+		return ctrl.Result{}, nil
+	}
+	ctx, cancel := r.drainContext(ctx)
+	defer cancel()
+
+	// Routes of Gateways handled by another operator instance are left to it
+	if !r.routeInShard(route) {
+		log.V(1).Info("Skipping route - handled by another operator shard", "name", route.GetName(), "namespace", route.GetNamespace())
+		return ctrl.Result{}, nil
+	}
+
+	// Skip if operator is not enabled for this route
+	if !r.routeEnabled(ctx, route) {
+		log.Info("Skipping route - operator not enabled", "name", route.GetName(), "namespace", route.GetNamespace())
+		return ctrl.Result{}, nil
+	}
+
+	// Routes outside the namespaces the operator manages are ignored. Routes being deleted still
+	// get their finalizer removed, in case their namespace left the scope after they got it
+	if route.GetDeletionTimestamp().IsZero() && !r.inNamespaceScope(ctx, route.GetNamespace()) {
+		log.Info("Skipping route - namespace not managed by the operator", "name", route.GetName(), "namespace", route.GetNamespace())
+		return ctrl.Result{}, nil
+	}
+
+	// Paused routes are left as they are, including their finalizer
+	if isPaused(route) {
+		log.Info("Skipping route - reconciliation paused", "name", route.GetName(), "namespace", route.GetNamespace())
+		return ctrl.Result{}, nil
+	}
+
+	// In gateway-per-namespace mode routes are attached to their namespace's Gateway
+	if r.NamespaceGatewayTemplate != "" && route.GetDeletionTimestamp().IsZero() && !r.dryRunFor(route) {
+		ok, err := r.ensureNamespaceParentRefs(ctx, route, parentRefs)
+		if err != nil || !ok {
+			return ctrl.Result{}, err
+		}
+	}
+
+	// With a shared gateway namespace routes are attached to the Gateways in it
+	if r.SharedGatewayNamespace != "" && r.NamespaceGatewayTemplate == "" && route.GetDeletionTimestamp().IsZero() && !r.dryRunFor(route) {
+		ok, err := r.ensureSharedParentRefs(ctx, route, parentRefs)
+		if err != nil || !ok {
+			return ctrl.Result{}, err
+		}
+	}
+
+	// Section names of renamed listeners follow their listener
+	if route.GetDeletionTimestamp().IsZero() && !r.dryRunFor(route) {
+		ok, err := r.ensureSectionNames(ctx, route, parentRefs)
+		if err != nil || !ok {
+			return ctrl.Result{}, err
+		}
+	}
+
+	// Validate that we have parent refs
+	if len(parentRefs) == 0 {
+		log.Error(nil, "Route has no parent references", "name", route.GetName())
+		return ctrl.Result{}, nil
+	}
+
+	log.Info("Reconciling route", "name", route.GetName(), "namespace", route.GetNamespace())
+
+	// Extract gateway information from first parent ref
+	// TODO: Support multiple parent refs in the future
+	gatewayName, gatewayNamespace := parentGateway(route.GetNamespace(), parentRefs[0])
+
+	// In dry-run mode the changes are only planned and reported. A route in dry-run mode on its own
+	// still gets its finalizer released when it is deleted, the operator writes nothing in dry-run mode
+	if r.dryRunFor(route) {
+		if route.GetDeletionTimestamp().IsZero() {
+			if err := r.planRoute(ctx, route, gatewayName, gatewayNamespace); err != nil {
+				log.Error(err, "Failed to plan changes for route")
+				return ctrl.Result{}, err
+			}
+			return ctrl.Result{}, nil
+		}
+		if r.DryRun {
+			return ctrl.Result{}, nil
+		}
+	}
+
+	// Routes of the same Gateway are reconciled one at a time
+	defer r.lockGateway(gatewayName, gatewayNamespace)()
+
+	// Handle deletion - the Gateway reconciler removes the route's listeners, since routes
+	// being deleted no longer contribute listeners
+	if !route.GetDeletionTimestamp().IsZero() {
+		log.Info("Route is being deleted", "name", route.GetName())
+		r.Recorder.Eventf(route, corev1.EventTypeNormal, "RouteDeleting",
+			"Route is being deleted, its listeners are removed from Gateway %s/%s", gatewayNamespace, gatewayName)
+
+		// Free the hostnames no other route in the namespace uses anymore
+		if r.EnableHostnameClaims {
+			if err := r.releaseHostnameClaims(ctx, route.GetNamespace()); err != nil {
+				log.Error(err, "Failed to release hostname claims")
+				return ctrl.Result{}, err
+			}
+		}
+
+		// Remove the finalizer, a route that is already gone has nothing left to remove
+		if controllerutil.ContainsFinalizer(route, httprouteFinalizerName) {
+			if err := r.removeFinalizer(ctx, route, httprouteFinalizerName); err != nil {
+				if client.IgnoreNotFound(err) != nil {
+					log.Error(err, "Failed to remove finalizer")
+					return ctrl.Result{}, err
+				}
+				log.V(1).Info("Route already deleted", "name", route.GetName())
+			} else {
+				log.Info("Removed finalizer from route", "name", route.GetName())
+			}
+		}
+
+		return ctrl.Result{}, nil
+	}
+
+	// When the gateway reference changes, the Gateway reconciler updates the old gateway as it is
+	// enqueued for the route both before and after the change. Which routes reference a gateway is
+	// read from the cache's index, see listGatewayRoutes, so nothing is recorded on the route
+	currentGatewayRef := gatewayNamespace + "/" + gatewayName
+
+	// Add the finalizer if not present
+	if !controllerutil.ContainsFinalizer(route, httprouteFinalizerName) {
+		if err := r.addFinalizer(ctx, route, httprouteFinalizerName); err != nil {
+			log.Error(err, "Failed to add finalizer")
+			return ctrl.Result{}, err
+		}
+		log.Info("Added finalizer to route", "name", route.GetName())
+		// Return and let Kubernetes re-trigger reconciliation with the updated object
+		return ctrl.Result{}, nil
+	}
+
+	// Mark the route as reconciled
+	annotationsPatch, err := r.routeAnnotationsPatch(ctx, route)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if annotationsPatch != nil {
+		if err := r.Patch(ctx, route, client.RawPatch(types.MergePatchType, annotationsPatch)); err != nil {
+			log.Error(err, "Failed to update route annotations")
+			return ctrl.Result{}, err
+		}
+		log.Info("Updated route annotations", "name", route.GetName())
+	}
+
+	// Report the outcome on the route, unless it was handed on to another reconciliation
+	ensured := false
+	defer func() {
+		if err == nil && !ensured {
+			return
+		}
+		if statusErr := r.syncRouteStatus(ctx, route, gatewayName, gatewayNamespace, err); statusErr != nil {
+			log.Error(statusErr, "Failed to update route status")
+		}
+	}()
+
+	// The GatewayProfile the route references must exist
+	if err := r.ensureProfile(ctx, route); err != nil {
+		log.Error(err, "Failed to resolve GatewayProfile")
+		return ctrl.Result{}, err
+	}
+
+	// The route's hostnames must be in the allowed domains, and its IPAM zone allowed in its namespace
+	if err := r.ensureRouteAllowed(ctx, route); err != nil {
+		log.Error(err, "Route rejected")
+		return ctrl.Result{}, err
+	}
+
+	// The route's hostnames must not be claimed by another namespace
+	if r.EnableHostnameClaims {
+		if err := r.ensureHostnameClaims(ctx, route); err != nil {
+			log.Error(err, "Failed to claim hostnames")
+			return ctrl.Result{}, err
+		}
+		if err := r.releaseHostnameClaims(ctx, route.GetNamespace()); err != nil {
+			log.Error(err, "Failed to release hostname claims")
+			return ctrl.Result{}, err
+		}
+	}
+
+	// Get IPAM zone, cluster issuer and GatewayClass from annotations, the profile or use defaults
+	settings := r.gatewaySettingsFor(ctx, route)
+
+	// Move the route to another shard if the gateway has no room for its listeners
+	if r.EnableGatewaySharding {
+		if info, ok := newRouteInfo(route); ok {
+			shardName, err := r.selectGatewayShard(ctx, info, gatewayName, gatewayNamespace)
+			if err != nil {
+				log.Error(err, "Failed to select gateway shard", "gateway", currentGatewayRef)
+				return ctrl.Result{}, err
+			}
+			if shardName != gatewayName {
+				if err := r.moveRouteToShard(ctx, info, gatewayName, gatewayNamespace, shardName); err != nil {
+					log.Error(err, "Failed to move route to gateway shard", "shard", shardName)
+					return ctrl.Result{}, err
+				}
+				// The parent reference change triggers a new reconciliation against the shard
+				return ctrl.Result{}, nil
+			}
+		}
+	}
+
+	// Cross-namespace attachments need a ReferenceGrant in the gateway's namespace
+	if gatewayNamespace != route.GetNamespace() {
+		if err := r.ensureRouteReferenceGrant(ctx, route, gvk.Kind, gatewayName, gatewayNamespace); err != nil {
+			log.Error(err, "Failed to ensure ReferenceGrant", "gateway", currentGatewayRef)
+			return ctrl.Result{}, err
+		}
+	}
+
+	// TLS to the route's backends, for routes asking for it
+	if r.EnableBackendTLSPolicies {
+		if err := r.syncBackendTLSPolicy(ctx, route, gvk); err != nil {
+			log.Error(err, "Failed to sync BackendTLSPolicy")
+			return ctrl.Result{}, err
+		}
+	}
+
+	// OIDC authentication and allowed client addresses, for routes asking for them
+	if r.EnableSecurityPolicies {
+		if err := r.syncSecurityPolicy(ctx, route, gvk); err != nil {
+			log.Error(err, "Failed to sync SecurityPolicy")
+			return ctrl.Result{}, err
+		}
+	}
+
+	// Rate limits, for routes asking for them
+	if r.EnableBackendTrafficPolicies {
+		if err := r.syncBackendTrafficPolicy(ctx, route, gvk); err != nil {
+			log.Error(err, "Failed to sync BackendTrafficPolicy")
+			return ctrl.Result{}, err
+		}
+	}
+
+	// Routes on shared gateways must fit in their namespace's listener quota
+	if err := r.ensureListenerQuota(ctx, route, gatewayNamespace); err != nil {
+		log.Error(err, "Route rejected", "gateway", currentGatewayRef)
+		return ctrl.Result{}, err
+	}
+
+	// A listener port the route asks for must be allowed and free on the gateway
+	if err := r.ensureListenerPort(ctx, route, gatewayName, gatewayNamespace); err != nil {
+		log.Error(err, "Invalid listener port", "gateway", currentGatewayRef)
+		return ctrl.Result{}, err
+	}
+
+	// TLSRoutes can't take the hostname and port of an HTTPS listener
+	if ok, err := r.ensureTLSListeners(ctx, route, gatewayName, gatewayNamespace); err != nil || !ok {
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{RequeueAfter: listenerConflictRequeueInterval}, nil
+	}
+
+	// Ensure the Gateway exists, the Gateway reconciler keeps its listeners up to date
+	if err := r.ensureGateway(ctx, route, gatewayName, gatewayNamespace, settings); errors.Is(err, errAddressPending) {
+		// The Gateway is created once the IPAM provider allocated its address
+		return ctrl.Result{RequeueAfter: addressRequeueInterval}, nil
+	} else if err != nil {
+		log.Error(err, "Failed to ensure Gateway")
+		return ctrl.Result{}, err
+	}
+	ensured = true
+
+	// The route is only served once the Gateway implementation programmed the Gateway
+	requeueAfter, err := r.ensureGatewayProgrammed(ctx, route, gatewayName, gatewayNamespace)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{RequeueAfter: requeueAfter}, nil
+}
@@ -0,0 +1,157 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// Condition types the operator reports on routes in the status annotation
+const (
+	// RouteConditionGatewayEnsured tells whether the route's Gateway was created or updated for it
+	RouteConditionGatewayEnsured = "GatewayEnsured"
+
+	// RouteConditionIssuerMismatch tells whether the route asks for another issuer than its Gateway has
+	RouteConditionIssuerMismatch = "IssuerMismatch"
+
+	// RouteConditionCertificatePending tells whether listeners of the route wait for their certificates
+	RouteConditionCertificatePending = "CertificatePending"
+
+	// RouteConditionGatewayProgrammed tells whether the Gateway implementation programmed the route's Gateway
+	RouteConditionGatewayProgrammed = "GatewayProgrammed"
+)
+
+// routeConditions returns the conditions in the status annotation of a route
+func routeConditions(route client.Object) []metav1.Condition {
+	var conditions []metav1.Condition
+	if value := route.GetAnnotations()[statusAnnotationKey]; value != "" {
+		_ = json.Unmarshal([]byte(value), &conditions)
+	}
+	return conditions
+}
+
+// syncRouteStatus records the outcome of reconciling a route in its status annotation, as the
+// GatewayEnsured, GatewayProgrammed, IssuerMismatch and CertificatePending conditions, and the addresses of its
+// Gateway in the gateway-address annotation, so route owners can see what happened with kubectl
+// describe. Route status is owned by the Gateway implementation, so the operator keeps its own
+// feedback in annotations.
+func (r *GatewayManager) syncRouteStatus(
+	ctx context.Context,
+	route client.Object,
+	gatewayName, gatewayNamespace string,
+	reconcileErr error,
+) error {
+	conditions := routeConditions(route)
+	generation := route.GetGeneration()
+	gatewayRef := gatewayNamespace + "/" + gatewayName
+
+	ensured := metav1.Condition{
+		Type:               RouteConditionGatewayEnsured,
+		Status:             metav1.ConditionTrue,
+		Reason:             "Ensured",
+		Message:            fmt.Sprintf("Gateway %s serves the route", gatewayRef),
+		ObservedGeneration: generation,
+	}
+	if reconcileErr != nil {
+		ensured.Status, ensured.Reason, ensured.Message = metav1.ConditionFalse, "Error", reconcileErr.Error()
+		if errors.IsBadRequest(reconcileErr) {
+			ensured.Reason = "Rejected"
+		}
+	}
+	meta.SetStatusCondition(&conditions, ensured)
+
+	var gateway gatewayv1.Gateway
+	if err := r.Get(ctx, client.ObjectKey{Name: gatewayName, Namespace: gatewayNamespace}, &gateway); client.IgnoreNotFound(err) != nil {
+		return err
+	}
+	exists := gateway.Name != ""
+
+	issuer := r.routeIssuer(ctx, route)
+	mismatch := metav1.Condition{
+		Type:               RouteConditionIssuerMismatch,
+		Status:             metav1.ConditionFalse,
+		Reason:             "IssuerMatches",
+		Message:            fmt.Sprintf("The route's issuer %s can be used on Gateway %s", issuer, gatewayRef),
+		ObservedGeneration: generation,
+	}
+	if exists && r.CertificateMode != CertificateModeCertificate && !servesPlainHTTP(route) {
+		if gatewayIssuer := gatewayIssuer(&gateway); gatewayIssuer != issuer {
+			mismatch.Status, mismatch.Reason = metav1.ConditionTrue, "IssuerMismatch"
+			mismatch.Message = fmt.Sprintf("Gateway %s has issuer %s but the route requires %s", gatewayRef, gatewayIssuer, issuer)
+		}
+	}
+	meta.SetStatusCondition(&conditions, mismatch)
+
+	pending := metav1.Condition{
+		Type:               RouteConditionCertificatePending,
+		Status:             metav1.ConditionFalse,
+		Reason:             "CertificatesReady",
+		Message:            "The listeners of the route have their certificates",
+		ObservedGeneration: generation,
+	}
+	if info, ok := newRouteInfo(route); ok && r.CertificateMode == CertificateModeCertificate {
+		current, err := r.currentListeners(ctx, &gateway)
+		if err != nil {
+			return err
+		}
+		_, waiting, err := r.withReadyCertificates(ctx, gatewayName, gatewayNamespace, current,
+			r.listenersForRoute(ctx, info, gatewayNamespace))
+		if err != nil {
+			return err
+		}
+		if len(waiting) > 0 {
+			names := make([]string, 0, len(waiting))
+			for _, name := range waiting {
+				names = append(names, string(name))
+			}
+			pending.Status, pending.Reason = metav1.ConditionTrue, "CertificatePending"
+			pending.Message = fmt.Sprintf("Listeners %s wait for their certificates to be Ready", strings.Join(names, ", "))
+		}
+	}
+	meta.SetStatusCondition(&conditions, pending)
+
+	programmed := metav1.Condition{
+		Type:               RouteConditionGatewayProgrammed,
+		Status:             metav1.ConditionUnknown,
+		Reason:             "Pending",
+		Message:            fmt.Sprintf("Gateway %s hasn't been programmed yet", gatewayRef),
+		ObservedGeneration: generation,
+	}
+	if condition := gatewayProgrammed(&gateway); exists && condition != nil {
+		programmed.Status, programmed.Reason = condition.Status, condition.Reason
+		programmed.Message = fmt.Sprintf("Gateway %s: %s", gatewayRef, condition.Message)
+	}
+	meta.SetStatusCondition(&conditions, programmed)
+
+	slices.SortFunc(conditions, func(a, b metav1.Condition) int { return strings.Compare(a.Type, b.Type) })
+	status, err := json.Marshal(conditions)
+	if err != nil {
+		return err
+	}
+	address := assignedAddresses(&gateway)
+
+	annotations := route.GetAnnotations()
+	if annotations[statusAnnotationKey] != string(status) || annotations[gatewayAddressAnnotationKey] != address {
+		patch := client.MergeFrom(route.DeepCopyObject().(client.Object))
+		if annotations == nil {
+			annotations = make(map[string]string)
+		}
+		annotations[statusAnnotationKey] = string(status)
+		if address == "" {
+			delete(annotations, gatewayAddressAnnotationKey)
+		} else {
+			annotations[gatewayAddressAnnotationKey] = address
+		}
+		route.SetAnnotations(annotations)
+		return r.Patch(ctx, route, patch)
+	}
+	return nil
+}
@@ -0,0 +1,301 @@
+package controller
+
+import (
+	"context"
+	"net/netip"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// +kubebuilder:rbac:groups=gateway.envoyproxy.io,resources=securitypolicies,verbs=get;list;watch;create;update;patch;delete
+
+// securityPolicySuffix is the suffix of the name of the SecurityPolicy created for a route
+const securityPolicySuffix = "-security"
+
+// securityPolicyGVK is the Envoy Gateway SecurityPolicy
+var securityPolicyGVK = schema.GroupVersionKind{
+	Group:   "gateway.envoyproxy.io",
+	Version: "v1alpha1",
+	Kind:    "SecurityPolicy",
+}
+
+// syncSecurityPolicy applies the Envoy Gateway SecurityPolicy combining a route's authentication,
+// CORS and allowed client addresses, and deletes it when the route asks for none of them
+func (r *GatewayManager) syncSecurityPolicy(ctx context.Context, route client.Object, gvk schema.GroupVersionKind) error {
+	log := logf.FromContext(ctx)
+	name := route.GetName() + securityPolicySuffix
+
+	spec := map[string]any{}
+	oidc, err := oidcSpec(route)
+	if err != nil {
+		return err
+	}
+	if oidc != nil {
+		spec["oidc"] = oidc
+	}
+	jwt, err := jwtSpec(route)
+	if err != nil {
+		return err
+	}
+	if jwt != nil {
+		spec["jwt"] = jwt
+	}
+	basicAuth, err := basicAuthSpec(route)
+	if err != nil {
+		return err
+	}
+	if basicAuth != nil {
+		spec["basicAuth"] = basicAuth
+	}
+	cors, err := corsSpec(route)
+	if err != nil {
+		return err
+	}
+	if cors != nil {
+		spec["cors"] = cors
+	}
+	authorization, err := allowedCIDRsSpec(route)
+	if err != nil {
+		return err
+	}
+	if authorization != nil {
+		spec["authorization"] = authorization
+	}
+
+	if len(spec) == 0 {
+		policy := &unstructured.Unstructured{}
+		policy.SetGroupVersionKind(securityPolicyGVK)
+		if err := r.Get(ctx, client.ObjectKey{Name: name, Namespace: route.GetNamespace()}, policy); err != nil {
+			return client.IgnoreNotFound(err)
+		}
+		if !metav1.IsControlledBy(policy, route) {
+			return nil
+		}
+		if err := r.Delete(ctx, policy); client.IgnoreNotFound(err) != nil {
+			return err
+		}
+		log.Info("Deleted SecurityPolicy", "securityPolicy", name, "namespace", route.GetNamespace())
+		return nil
+	}
+
+	spec["targetRefs"] = []any{
+		map[string]any{
+			"group": gvk.Group,
+			"kind":  gvk.Kind,
+			"name":  route.GetName(),
+		},
+	}
+	policy := &unstructured.Unstructured{}
+	policy.SetGroupVersionKind(securityPolicyGVK)
+	policy.SetName(name)
+	policy.SetNamespace(route.GetNamespace())
+	policy.SetLabels(map[string]string{managedByLabel: managedByValue})
+	policy.SetOwnerReferences([]metav1.OwnerReference{*metav1.NewControllerRef(route, gvk)})
+	policy.Object["spec"] = spec
+	if err := r.Patch(ctx, policy, client.Apply, client.ForceOwnership, client.FieldOwner(fieldManager)); err != nil {
+		return err
+	}
+	log.Info("Applied SecurityPolicy", "securityPolicy", name, "namespace", route.GetNamespace(),
+		"oidc", oidc != nil, "jwt", jwt != nil, "basicAuth", basicAuth != nil, "cors", cors != nil, "allowedCIDRs", authorization != nil)
+	return nil
+}
+
+// oidcSpec returns the OIDC settings of the SecurityPolicy for an HTTPRoute with the oidc-issuer
+// annotation, or nil. The client ID and the Secret with the client secret must be given as well.
+func oidcSpec(route client.Object) (map[string]any, error) {
+	annotations := route.GetAnnotations()
+	issuer := annotations[AnnotationOIDCIssuer]
+	if issuer == "" {
+		return nil, nil
+	}
+	if _, ok := route.(*gatewayv1.HTTPRoute); !ok {
+		return nil, errors.NewBadRequest("OIDC authentication is only supported for HTTPRoutes")
+	}
+	clientID, clientSecret := annotations[AnnotationOIDCClientID], annotations[AnnotationOIDCClientSecret]
+	if clientID == "" || clientSecret == "" {
+		return nil, errors.NewBadRequest("OIDC authentication needs the " + AnnotationOIDCClientID + " and " +
+			AnnotationOIDCClientSecret + " annotations besides " + AnnotationOIDCIssuer)
+	}
+
+	oidc := map[string]any{
+		"provider": map[string]any{
+			"issuer": issuer,
+		},
+		"clientID": clientID,
+		"clientSecret": map[string]any{
+			"name": clientSecret,
+		},
+	}
+	if redirectURL := annotations[AnnotationOIDCRedirectURL]; redirectURL != "" {
+		oidc["redirectURL"] = redirectURL
+	}
+	if scopes := parseAnnotationList(annotations[AnnotationOIDCScopes]); len(scopes) > 0 {
+		oidc["scopes"] = scopes
+	}
+	return oidc, nil
+}
+
+// jwtSpec returns the JWT settings of the SecurityPolicy for an HTTPRoute or GRPCRoute with the
+// jwt-issuer annotation, or nil: requests need a valid JWT from the issuer, verified with the keys
+// fetched from the JWKS URI, which must be given as well
+func jwtSpec(route client.Object) (map[string]any, error) {
+	annotations := route.GetAnnotations()
+	issuer := annotations[AnnotationJWTIssuer]
+	if issuer == "" {
+		return nil, nil
+	}
+	switch route.(type) {
+	case *gatewayv1.HTTPRoute, *gatewayv1.GRPCRoute:
+	default:
+		return nil, errors.NewBadRequest("JWT validation is only supported for HTTPRoutes and GRPCRoutes")
+	}
+	jwksURI := annotations[AnnotationJWTJWKSURI]
+	if jwksURI == "" {
+		return nil, errors.NewBadRequest("JWT validation needs the " + AnnotationJWTJWKSURI + " annotation besides " +
+			AnnotationJWTIssuer)
+	}
+	if parsed, err := url.Parse(jwksURI); err != nil || (parsed.Scheme != "https" && parsed.Scheme != "http") || parsed.Host == "" {
+		return nil, errors.NewBadRequest(AnnotationJWTJWKSURI + ": " + jwksURI + " is not an http or https URL")
+	}
+
+	return map[string]any{
+		"providers": []any{
+			map[string]any{
+				"name":   "issuer",
+				"issuer": issuer,
+				"remoteJWKS": map[string]any{
+					"uri": jwksURI,
+				},
+			},
+		},
+	}, nil
+}
+
+// basicAuthSpec returns the basic authentication settings of the SecurityPolicy for an HTTPRoute or
+// GRPCRoute with the basic-auth-secret annotation, or nil: requests need the credentials of one of
+// the users in the htpasswd file in the named Secret
+func basicAuthSpec(route client.Object) (map[string]any, error) {
+	secret := route.GetAnnotations()[AnnotationBasicAuthSecret]
+	if secret == "" {
+		return nil, nil
+	}
+	switch route.(type) {
+	case *gatewayv1.HTTPRoute, *gatewayv1.GRPCRoute:
+	default:
+		return nil, errors.NewBadRequest("basic authentication is only supported for HTTPRoutes and GRPCRoutes")
+	}
+	return map[string]any{
+		"users": map[string]any{
+			"name": secret,
+		},
+	}, nil
+}
+
+// corsSpec returns the CORS settings of the SecurityPolicy for a route with the cors-allow-origins
+// annotation, or nil. Origins must be * or scheme://host, the host may start with a wildcard label.
+func corsSpec(route client.Object) (map[string]any, error) {
+	annotations := route.GetAnnotations()
+	value, exists := annotations[AnnotationCORSAllowOrigins]
+	if !exists {
+		return nil, nil
+	}
+	switch route.(type) {
+	case *gatewayv1.HTTPRoute, *gatewayv1.GRPCRoute:
+	default:
+		return nil, errors.NewBadRequest("CORS is only supported for HTTPRoutes and GRPCRoutes")
+	}
+	origins := parseAnnotationList(value)
+	if len(origins) == 0 {
+		return nil, errors.NewBadRequest(AnnotationCORSAllowOrigins + " must list at least one origin")
+	}
+	for _, origin := range origins {
+		if origin == "*" {
+			continue
+		}
+		scheme, host, found := strings.Cut(origin.(string), "://")
+		if !found || scheme == "" || host == "" || strings.ContainsAny(host, "/?#") {
+			return nil, errors.NewBadRequest(AnnotationCORSAllowOrigins + ": " + origin.(string) +
+				" is not * or an origin like https://example.com")
+		}
+	}
+
+	cors := map[string]any{
+		"allowOrigins": origins,
+	}
+	if methods := parseAnnotationList(strings.ToUpper(annotations[AnnotationCORSAllowMethods])); len(methods) > 0 {
+		cors["allowMethods"] = methods
+	}
+	if headers := parseAnnotationList(annotations[AnnotationCORSAllowHeaders]); len(headers) > 0 {
+		cors["allowHeaders"] = headers
+	}
+	if headers := parseAnnotationList(annotations[AnnotationCORSExposeHeaders]); len(headers) > 0 {
+		cors["exposeHeaders"] = headers
+	}
+	if value, exists := annotations[AnnotationCORSAllowCredentials]; exists {
+		allow, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, errors.NewBadRequest(AnnotationCORSAllowCredentials + ": " + strconv.Quote(value) + " is not true or false")
+		}
+		cors["allowCredentials"] = allow
+	}
+	return cors, nil
+}
+
+// allowedCIDRsSpec returns the authorization settings of the SecurityPolicy for a route with the
+// allowed-cidrs annotation, or nil: requests from the address ranges are allowed, all others are
+// denied. Every entry must be a valid CIDR, or an address standing for itself.
+func allowedCIDRsSpec(route client.Object) (map[string]any, error) {
+	value, exists := route.GetAnnotations()[AnnotationAllowedCIDRs]
+	if !exists {
+		return nil, nil
+	}
+	entries := parseAnnotationList(value)
+	if len(entries) == 0 {
+		return nil, errors.NewBadRequest(AnnotationAllowedCIDRs + " must list at least one CIDR")
+	}
+	cidrs := make([]any, 0, len(entries))
+	for _, entry := range entries {
+		prefix, err := netip.ParsePrefix(entry.(string))
+		if err != nil {
+			addr, addrErr := netip.ParseAddr(entry.(string))
+			if addrErr != nil {
+				return nil, errors.NewBadRequest(AnnotationAllowedCIDRs + ": " + err.Error())
+			}
+			prefix = netip.PrefixFrom(addr, addr.BitLen())
+		}
+		cidrs = append(cidrs, prefix.Masked().String())
+	}
+
+	return map[string]any{
+		"defaultAction": "Deny",
+		"rules": []any{
+			map[string]any{
+				"name":   "allowed-cidrs",
+				"action": "Allow",
+				"principal": map[string]any{
+					"clientCIDRs": cidrs,
+				},
+			},
+		},
+	}, nil
+}
+
+// parseAnnotationList parses a comma separated annotation value, ignoring empty entries
+func parseAnnotationList(value string) []any {
+	var items []any
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
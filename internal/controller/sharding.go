@@ -0,0 +1,220 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// maxGatewayShards bounds the number of shards searched for free listener capacity
+const maxGatewayShards = 100
+
+// maxGatewayListeners is the number of listeners the Gateway CRD allows
+const maxGatewayListeners = 64
+
+// gatewayListenerCount returns the number of listeners a gateway gets for the listeners of its
+// routes, including the HTTP listeners HTTPS redirects add
+func (r *GatewayManager) gatewayListenerCount(gatewayNamespace string, listeners []gatewayv1.Listener) int {
+	listeners, _ = r.withHTTPListeners(gatewayNamespace, listeners, nil)
+	return len(listeners)
+}
+
+// exceedsListenerLimit reports whether the listeners to apply to a gateway, HTTP listeners
+// included, are more than a Gateway can hold, with a warning event on obj. HTTP-01 listeners only
+// exist during issuance, so shard sizing leaves them out and they can still tip a gateway over.
+func (r *GatewayManager) exceedsListenerLimit(
+	ctx context.Context,
+	obj client.Object,
+	gatewayName, gatewayNamespace string,
+	listeners []gatewayv1.Listener,
+) bool {
+	if r.EnableListenerSets || len(listeners) <= maxGatewayListeners {
+		return false
+	}
+	logf.FromContext(ctx).Info("Gateway would get more listeners than it can hold, not applying them",
+		"gateway", gatewayName, "namespace", gatewayNamespace, "listeners", len(listeners), "max", maxGatewayListeners)
+	r.Recorder.Eventf(obj, corev1.EventTypeWarning, "ListenerLimitExceeded",
+		"Gateway %s/%s needs %d listeners including its HTTP listeners, a Gateway holds at most %d, enable gateway sharding or ListenerSets",
+		gatewayNamespace, gatewayName, len(listeners), maxGatewayListeners)
+	return true
+}
+
+// gatewayShardName returns the name of a gateway shard. The first shard is the gateway itself.
+func gatewayShardName(gatewayName string, shard int) string {
+	if shard <= 1 {
+		return gatewayName
+	}
+	return fmt.Sprintf("%s-%d", gatewayName, shard)
+}
+
+// splitGatewayShardName splits a gateway name into the name of the gateway it would be a shard of
+// and the shard number, e.g. web-3 into web and 3. Names without a shard suffix are shard 1.
+func splitGatewayShardName(name string) (string, int) {
+	i := strings.LastIndex(name, "-")
+	if i <= 0 {
+		return name, 1
+	}
+	shard, err := strconv.Atoi(name[i+1:])
+	if err != nil || shard < 2 || gatewayShardName(name[:i], shard) != name {
+		return name, 1
+	}
+	return name[:i], shard
+}
+
+// shardBaseGateway returns the gateway whose shards the gateway belongs to. A name with a shard
+// suffix only belongs to another gateway when that gateway exists, so a gateway named like web-2
+// on its own is sharded as web-2-2 and so on.
+func (r *GatewayManager) shardBaseGateway(ctx context.Context, gatewayName, gatewayNamespace string) (string, error) {
+	baseName, shard := splitGatewayShardName(gatewayName)
+	if shard == 1 {
+		return gatewayName, nil
+	}
+	var base gatewayv1.Gateway
+	if err := r.Get(ctx, client.ObjectKey{Name: baseName, Namespace: gatewayNamespace}, &base); err != nil {
+		if errors.IsNotFound(err) {
+			return gatewayName, nil
+		}
+		return "", err
+	}
+	return baseName, nil
+}
+
+// selectGatewayShard returns the gateway a route should attach to so that no gateway exceeds
+// MaxListenersPerGateway, counting the HTTP listeners of HTTPS redirects. A route stays on its
+// gateway when the gateway has room for it or already serves all of its listeners, otherwise it
+// goes to the first shard of the base gateway with room, also when it is on a shard already.
+func (r *GatewayManager) selectGatewayShard(
+	ctx context.Context,
+	route routeInfo,
+	gatewayName, gatewayNamespace string,
+) (string, error) {
+	listeners, _, err := r.collectListenersForGateway(ctx, gatewayName, gatewayNamespace)
+	if err != nil {
+		return "", err
+	}
+	if r.gatewayListenerCount(gatewayNamespace, listeners) <= r.MaxListenersPerGateway {
+		return gatewayName, nil
+	}
+
+	// A route whose listeners are all on the gateway already is a resident, only newcomers move
+	var gateway gatewayv1.Gateway
+	if err := r.Get(ctx, client.ObjectKey{Name: gatewayName, Namespace: gatewayNamespace}, &gateway); client.IgnoreNotFound(err) != nil {
+		return "", err
+	}
+	current, err := r.currentListeners(ctx, &gateway)
+	if err != nil {
+		return "", err
+	}
+	existing := make(map[gatewayv1.SectionName]bool)
+	for _, listener := range current {
+		existing[listener.Name] = true
+	}
+	routeListeners := r.listenersForRoute(ctx, route, gatewayNamespace)
+	resident := len(routeListeners) > 0
+	for _, listener := range routeListeners {
+		if !existing[listener.Name] {
+			resident = false
+			break
+		}
+	}
+	if resident {
+		return gatewayName, nil
+	}
+
+	baseName, err := r.shardBaseGateway(ctx, gatewayName, gatewayNamespace)
+	if err != nil {
+		return "", err
+	}
+	return r.firstShardWithRoom(baseName, gatewayName, gatewayNamespace, routeListeners,
+		func(shardName string) ([]gatewayv1.Listener, error) {
+			listeners, _, err := r.collectListenersForGateway(ctx, shardName, gatewayNamespace)
+			return listeners, err
+		})
+}
+
+// firstShardWithRoom returns the first shard of the base gateway, other than the route's current
+// gateway, that has room for the route's listeners next to the listeners shardListeners returns for
+// it. First-fit over the shards keeps the assignment stable and deterministic.
+func (r *GatewayManager) firstShardWithRoom(
+	baseName, gatewayName, gatewayNamespace string,
+	routeListeners []gatewayv1.Listener,
+	shardListeners func(shardName string) ([]gatewayv1.Listener, error),
+) (string, error) {
+	for shard := 1; shard <= maxGatewayShards; shard++ {
+		shardName := gatewayShardName(baseName, shard)
+		if shardName == gatewayName {
+			continue
+		}
+		listeners, err := shardListeners(shardName)
+		if err != nil {
+			return "", err
+		}
+		for _, listener := range routeListeners {
+			if !slices.ContainsFunc(listeners, func(existing gatewayv1.Listener) bool { return existing.Name == listener.Name }) {
+				listeners = append(listeners, listener)
+			}
+		}
+		if r.gatewayListenerCount(gatewayNamespace, listeners) <= r.MaxListenersPerGateway {
+			return shardName, nil
+		}
+	}
+	return "", fmt.Errorf("no shard of gateway %s/%s has room for %d more listeners", gatewayNamespace, baseName, len(routeListeners))
+}
+
+// moveRouteToShard rewrites the route's parent references to the gateway so they point to the shard,
+// and tells the route owner which shard the route landed on
+func (r *GatewayManager) moveRouteToShard(
+	ctx context.Context,
+	route routeInfo,
+	gatewayName, gatewayNamespace, shardName string,
+) error {
+	log := logf.FromContext(ctx)
+
+	if err := r.moveRouteToGateway(ctx, route, gatewayName, gatewayNamespace, shardName); err != nil {
+		return err
+	}
+
+	log.Info("Moved route to gateway shard", "route", route.GetName(), "namespace", route.GetNamespace(),
+		"gateway", gatewayName, "shard", shardName)
+	r.Recorder.Eventf(route.Object, corev1.EventTypeNormal, "GatewaySharded",
+		"Gateway %s/%s has reached %d listeners, route moved to Gateway %s/%s",
+		gatewayNamespace, gatewayName, r.MaxListenersPerGateway, gatewayNamespace, shardName)
+	return nil
+}
+
+// moveRouteToGateway rewrites the route's parent references to a gateway so they point to another
+// gateway in the same namespace
+func (r *GatewayManager) moveRouteToGateway(
+	ctx context.Context,
+	route routeInfo,
+	gatewayName, gatewayNamespace, targetName string,
+) error {
+	parentRefs := make([]gatewayv1.ParentReference, 0, len(route.ParentRefs))
+	for _, parentRef := range route.ParentRefs {
+		refName, refNamespace := parentGateway(route.GetNamespace(), parentRef)
+		if refName == gatewayName && refNamespace == gatewayNamespace {
+			parentRef.Name = gatewayv1.ObjectName(targetName)
+		}
+		parentRefs = append(parentRefs, parentRef)
+	}
+
+	patch, err := json.Marshal(map[string]any{
+		"spec": map[string]any{
+			"parentRefs": parentRefs,
+		},
+	})
+	if err != nil {
+		return err
+	}
+	return r.Patch(ctx, route.Object, client.RawPatch(types.MergePatchType, patch))
+}
@@ -0,0 +1,138 @@
+package controller
+
+import (
+	"fmt"
+	"testing"
+
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// httpsListeners returns HTTPS listeners named after the given hostnames
+func httpsListeners(hostnames ...string) []gatewayv1.Listener {
+	listeners := make([]gatewayv1.Listener, 0, len(hostnames))
+	for _, hostname := range hostnames {
+		h := gatewayv1.Hostname(hostname)
+		listeners = append(listeners, gatewayv1.Listener{
+			Name:     gatewayv1.SectionName(hostname),
+			Protocol: gatewayv1.HTTPSProtocolType,
+			Port:     httpsPort,
+			Hostname: &h,
+		})
+	}
+	return listeners
+}
+
+func TestSplitGatewayShardName(t *testing.T) {
+	tests := []struct {
+		name      string
+		wantBase  string
+		wantShard int
+	}{
+		{name: "web", wantBase: "web", wantShard: 1},
+		{name: "web-2", wantBase: "web", wantShard: 2},
+		{name: "web-12", wantBase: "web", wantShard: 12},
+		{name: "web-2-3", wantBase: "web-2", wantShard: 3},
+		{name: "web-1", wantBase: "web-1", wantShard: 1},
+		{name: "web-02", wantBase: "web-02", wantShard: 1},
+		{name: "web-api", wantBase: "web-api", wantShard: 1},
+		{name: "-2", wantBase: "-2", wantShard: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			base, shard := splitGatewayShardName(tt.name)
+			if base != tt.wantBase || shard != tt.wantShard {
+				t.Errorf("splitGatewayShardName(%q) = %q, %d, want %q, %d", tt.name, base, shard, tt.wantBase, tt.wantShard)
+			}
+		})
+	}
+}
+
+func TestFirstShardWithRoom(t *testing.T) {
+	tests := []struct {
+		name           string
+		redirect       bool
+		gatewayName    string
+		shards         map[string][]gatewayv1.Listener
+		routeListeners []gatewayv1.Listener
+		want           string
+		wantErr        bool
+	}{
+		{
+			name:        "first shard with room",
+			gatewayName: "web",
+			shards: map[string][]gatewayv1.Listener{
+				"web":   httpsListeners("a", "b", "c"),
+				"web-2": httpsListeners("d", "e", "f"),
+				"web-3": httpsListeners("g"),
+			},
+			routeListeners: httpsListeners("h"),
+			want:           "web-3",
+		},
+		{
+			name:        "full shard moves back to the base gateway",
+			gatewayName: "web-2",
+			shards: map[string][]gatewayv1.Listener{
+				"web":   httpsListeners("a"),
+				"web-2": httpsListeners("b", "c", "d"),
+			},
+			routeListeners: httpsListeners("e"),
+			want:           "web",
+		},
+		{
+			name:        "full shard moves to the next shard of the base gateway",
+			gatewayName: "web-2",
+			shards: map[string][]gatewayv1.Listener{
+				"web":   httpsListeners("a", "b", "c"),
+				"web-2": httpsListeners("d", "e", "f"),
+			},
+			routeListeners: httpsListeners("g"),
+			want:           "web-3",
+		},
+		{
+			name:        "listeners a shard serves already take no room",
+			gatewayName: "web",
+			shards: map[string][]gatewayv1.Listener{
+				"web":   httpsListeners("a", "b", "c"),
+				"web-2": httpsListeners("d", "e", "f"),
+			},
+			routeListeners: httpsListeners("e", "f"),
+			want:           "web-2",
+		},
+		{
+			name:        "redirect listeners count",
+			redirect:    true,
+			gatewayName: "web",
+			shards: map[string][]gatewayv1.Listener{
+				"web":   httpsListeners("a", "b"),
+				"web-2": httpsListeners("c"),
+			},
+			routeListeners: httpsListeners("d"),
+			want:           "web-3",
+		},
+		{
+			name:           "route needs more than a gateway holds",
+			gatewayName:    "web",
+			shards:         map[string][]gatewayv1.Listener{},
+			routeListeners: httpsListeners("a", "b", "c", "d"),
+			wantErr:        true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &GatewayManager{MaxListenersPerGateway: 3, EnableHTTPSRedirect: tt.redirect}
+			got, err := r.firstShardWithRoom("web", tt.gatewayName, "gateways", tt.routeListeners,
+				func(shardName string) ([]gatewayv1.Listener, error) {
+					if shardName == tt.gatewayName {
+						return nil, fmt.Errorf("read the route's current gateway %s", shardName)
+					}
+					return tt.shards[shardName], nil
+				})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("firstShardWithRoom() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("firstShardWithRoom() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
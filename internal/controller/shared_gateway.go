@@ -0,0 +1,61 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// ensureSharedParentRefs resolves the Gateways of a route to the shared gateway namespace: parent
+// references to a Gateway in the route's own namespace are pointed at the Gateway of the same name
+// in the shared namespace. It returns true when the route may be reconciled as is.
+func (r *GatewayManager) ensureSharedParentRefs(
+	ctx context.Context,
+	route client.Object,
+	parentRefs []gatewayv1.ParentReference,
+) (bool, error) {
+	log := logf.FromContext(ctx)
+	if r.SharedGatewayNamespace == "" || route.GetNamespace() == r.SharedGatewayNamespace {
+		return true, nil
+	}
+
+	resolved := make([]gatewayv1.ParentReference, 0, len(parentRefs))
+	changed := false
+	for _, parentRef := range parentRefs {
+		if parentRef.Kind == nil || *parentRef.Kind == "Gateway" {
+			if _, refNamespace := parentGateway(route.GetNamespace(), parentRef); refNamespace == route.GetNamespace() {
+				namespace := gatewayv1.Namespace(r.SharedGatewayNamespace)
+				parentRef.Namespace = &namespace
+				changed = true
+			}
+		}
+		resolved = append(resolved, parentRef)
+	}
+	if !changed {
+		return true, nil
+	}
+
+	patch, err := json.Marshal(map[string]any{
+		"spec": map[string]any{
+			"parentRefs": resolved,
+		},
+	})
+	if err != nil {
+		return false, err
+	}
+	if err := r.Patch(ctx, route, client.RawPatch(types.MergePatchType, patch)); err != nil {
+		log.Error(err, "Failed to point parent references to the shared gateway namespace", "route", route.GetName())
+		return false, err
+	}
+	log.Info("Pointed parent references to the shared gateway namespace", "route", route.GetName(),
+		"namespace", route.GetNamespace(), "sharedNamespace", r.SharedGatewayNamespace)
+	r.Recorder.Eventf(route, corev1.EventTypeNormal, "ParentRefShared",
+		"Attached route to the shared Gateways in namespace %s", r.SharedGatewayNamespace)
+	// The patch triggers a new reconciliation with the updated parent references
+	return false, nil
+}
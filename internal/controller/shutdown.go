@@ -0,0 +1,27 @@
+package controller
+
+import (
+	"context"
+	"time"
+)
+
+// drainContext returns the context for the work of a reconcile. When the operator shuts down, ctx
+// is cancelled, but the returned context keeps going for ShutdownDrainTimeout, so Gateway applies
+// and other writes in flight finish rather than leaving a Gateway half updated. The returned
+// function releases the context once the reconcile is done.
+func (r *GatewayManager) drainContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	drain, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	stop := context.AfterFunc(ctx, func() {
+		time.AfterFunc(r.ShutdownDrainTimeout, cancel)
+	})
+	return drain, func() {
+		stop()
+		cancel()
+	}
+}
+
+// shuttingDown reports whether the operator is shutting down, in which case reconciles don't
+// start new work. Objects left unreconciled are reconciled when the operator starts again.
+func shuttingDown(ctx context.Context) bool {
+	return ctx.Err() != nil
+}
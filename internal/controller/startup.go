@@ -0,0 +1,206 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// startupProgressInterval is how often the startup pass reports its progress
+const startupProgressInterval = 10 * time.Second
+
+// startupObjects and startupPending report the progress of the startup pass by kind
+var (
+	startupObjects = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "gatewayapi_operator_startup_objects",
+			Help: "Routes and Gateways reconciled by the startup pass, by kind",
+		},
+		[]string{"kind"},
+	)
+	startupPending = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "gatewayapi_operator_startup_pending",
+			Help: "Routes and Gateways the startup pass is still waiting to be reconciled, by kind",
+		},
+		[]string{"kind"},
+	)
+)
+
+func init() {
+	metrics.Registry.MustRegister(startupObjects, startupPending)
+}
+
+// startupKey identifies an object of the startup pass
+type startupKey struct {
+	kind string
+	client.ObjectKey
+}
+
+// startupSource returns the source through which the startup pass enqueues the objects of a kind
+// to its controller. It must be called while the controllers are set up.
+func (r *GatewayManager) startupSource(kind string) source.Source {
+	if r.startupEvents == nil {
+		r.startupEvents = make(map[string]chan event.GenericEvent)
+	}
+	events := make(chan event.GenericEvent)
+	r.startupEvents[kind] = events
+	return source.Channel(events, &handler.EnqueueRequestForObject{})
+}
+
+// markStartupReconciled records that the startup pass's object of a kind was reconciled
+func (r *GatewayManager) markStartupReconciled(kind string, key client.ObjectKey) {
+	if _, pending := r.startupPending.LoadAndDelete(startupKey{kind: kind, ObjectKey: key}); pending {
+		startupPending.WithLabelValues(kind).Dec()
+	}
+}
+
+// StartupPass reconciles every enabled route and managed Gateway once the operator is the leader,
+// rather than relying on the events of the initial listing alone, and reports its progress in the
+// log, in the startup metrics and through the converged readiness check, so convergence after
+// downtime can be followed and waited for. Routes count as reconciled after their first reconcile,
+// rejected ones included, Gateways after their first successful one.
+type StartupPass struct {
+	*GatewayManager
+}
+
+// NeedLeaderElection runs the startup pass on the leader, the only replica reconciling
+func (p *StartupPass) NeedLeaderElection() bool {
+	return true
+}
+
+// Start enqueues the routes and Gateways and reports the progress until all were reconciled
+func (p *StartupPass) Start(ctx context.Context) error {
+	log := logf.FromContext(ctx).WithName("startup")
+
+	var objects map[string][]client.Object
+	for {
+		var err error
+		if objects, err = p.startupObjects(ctx); err == nil {
+			break
+		}
+		log.Error(err, "Failed to list the routes and Gateways to reconcile at startup")
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(startupProgressInterval):
+		}
+	}
+
+	total := 0
+	for kind, objs := range objects {
+		for _, obj := range objs {
+			p.startupPending.Store(startupKey{kind: kind, ObjectKey: client.ObjectKeyFromObject(obj)}, obj)
+		}
+		startupObjects.WithLabelValues(kind).Set(float64(len(objs)))
+		startupPending.WithLabelValues(kind).Set(float64(len(objs)))
+		total += len(objs)
+	}
+	p.startupTotal.Store(int64(total))
+	p.startupStarted.Store(true)
+	log.Info("Reconciling all routes and Gateways", "total", total)
+
+	for kind, objs := range objects {
+		for _, obj := range objs {
+			select {
+			case p.startupEvents[kind] <- event.GenericEvent{Object: obj}:
+			case <-ctx.Done():
+				return nil
+			}
+		}
+	}
+
+	ticker := time.NewTicker(startupProgressInterval)
+	defer ticker.Stop()
+	for {
+		pending := p.forgetDeletedStartupObjects(ctx)
+		if pending == 0 {
+			log.Info("Reconciled all routes and Gateways", "total", total)
+			return nil
+		}
+		log.Info("Reconciling all routes and Gateways", "reconciled", total-pending, "total", total)
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// startupObjects returns the enabled routes and the managed Gateways this instance handles, by
+// kind. Only kinds with a controller are included.
+func (p *StartupPass) startupObjects(ctx context.Context) (map[string][]client.Object, error) {
+	objects := make(map[string][]client.Object)
+
+	var gateways gatewayv1.GatewayList
+	if err := p.List(ctx, &gateways); err != nil {
+		return nil, err
+	}
+	for i := range gateways.Items {
+		gateway := &gateways.Items[i]
+		if isManagedGateway(gateway) && gateway.DeletionTimestamp.IsZero() && p.gatewayInShard(gateway.Name, gateway.Namespace) {
+			objects["Gateway"] = append(objects["Gateway"], gateway)
+		}
+	}
+
+	routes, err := p.listRoutes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, route := range routes {
+		if route.Enabled && p.routeInShard(route.Object) {
+			objects[route.Kind] = append(objects[route.Kind], route.Object)
+		}
+	}
+
+	for kind := range objects {
+		if _, exists := p.startupEvents[kind]; !exists {
+			delete(objects, kind)
+		}
+	}
+	return objects, nil
+}
+
+// forgetDeletedStartupObjects stops waiting for objects deleted before they were reconciled, and
+// returns the number of objects still pending
+func (p *StartupPass) forgetDeletedStartupObjects(ctx context.Context) int {
+	pending := 0
+	p.startupPending.Range(func(key, value any) bool {
+		obj := value.(client.Object).DeepCopyObject().(client.Object)
+		if err := p.Get(ctx, key.(startupKey).ObjectKey, obj); client.IgnoreNotFound(err) == nil && err != nil {
+			p.markStartupReconciled(key.(startupKey).kind, key.(startupKey).ObjectKey)
+			return true
+		}
+		pending++
+		return true
+	})
+	return pending
+}
+
+// startupProgress describes the objects the startup pass still waits for, for the readiness check
+func (r *GatewayManager) startupProgress() error {
+	if !r.startupStarted.Load() {
+		return fmt.Errorf("the startup pass hasn't listed the routes and Gateways yet")
+	}
+	var pending []string
+	r.startupPending.Range(func(key, _ any) bool {
+		pending = append(pending, key.(startupKey).kind+" "+key.(startupKey).String())
+		return len(pending) <= 5
+	})
+	if len(pending) == 0 {
+		return nil
+	}
+	if len(pending) > 5 {
+		pending = append(pending[:5], "...")
+	}
+	return fmt.Errorf("startup pass hasn't reconciled all %d routes and Gateways yet: %v", r.startupTotal.Load(), pending)
+}
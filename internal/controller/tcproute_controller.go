@@ -0,0 +1,59 @@
+package controller
+
+import (
+	"context"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+)
+
+// TCPRouteReconciler reconciles a TCPRoute object
+type TCPRouteReconciler struct {
+	*GatewayManager
+}
+
+// +kubebuilder:rbac:groups=gateway.networking.k8s.io,resources=tcproutes,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=gateway.networking.k8s.io,resources=tcproutes/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=gateway.networking.k8s.io,resources=tcproutes/finalizers,verbs=update
+
+// Reconcile allocates a listener port for a TCPRoute and ensures the Gateway it references
+// has a TCP listener on that port.
+func (r *TCPRouteReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	// Fetch the TCPRoute
+	var tcpRoute gatewayv1alpha2.TCPRoute
+	if err := r.Get(ctx, req.NamespacedName, &tcpRoute); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	// Allocate a port before the gateway is ensured, so the listener can be created
+	enabled := r.routeEnabled(ctx, &tcpRoute)
+	if enabled && tcpRoute.DeletionTimestamp.IsZero() && len(tcpRoute.Spec.ParentRefs) > 0 &&
+		!r.dryRunFor(&tcpRoute) && !isPaused(&tcpRoute) {
+		gatewayName, gatewayNamespace := parentGateway(tcpRoute.Namespace, tcpRoute.Spec.ParentRefs[0])
+		ok, err := r.ensureTCPPort(ctx, &tcpRoute, gatewayName, gatewayNamespace)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if !ok {
+			return ctrl.Result{RequeueAfter: tcpPortConflictRequeueInterval}, nil
+		}
+	}
+
+	return r.reconcileRoute(ctx, &tcpRoute, tcpRoute.Spec.ParentRefs)
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *TCPRouteReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&gatewayv1alpha2.TCPRoute{}, builder.WithPredicates(routeChanged)).
+		Watches(&gatewayv1.Gateway{}, handler.EnqueueRequestsFromMapFunc(r.routesForDeletedGateway(&gatewayv1alpha2.TCPRouteList{})),
+			builder.WithPredicates(gatewayDeleted)).
+		WatchesRawSource(r.startupSource("TCPRoute")).
+		Named("tcproute").
+		WithOptions(r.controllerOptions()).
+		Complete(r)
+}
@@ -0,0 +1,59 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// parseTLSOptions parses a comma separated list of key=value TLS options
+func parseTLSOptions(value string) (map[gatewayv1.AnnotationKey]gatewayv1.AnnotationValue, error) {
+	options := make(map[gatewayv1.AnnotationKey]gatewayv1.AnnotationValue)
+	for _, pair := range strings.Split(value, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		key, val, ok := strings.Cut(pair, "=")
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid key=value pair %q", pair)
+		}
+		options[gatewayv1.AnnotationKey(key)] = gatewayv1.AnnotationValue(val)
+	}
+	return options, nil
+}
+
+// tlsOptionsFor returns the listener TLS options for a route: the operator defaults, overridden
+// by the options of its GatewayProfile and then by the route's tls-options annotation. An invalid
+// annotation is logged and ignored.
+func (r *GatewayManager) tlsOptionsFor(ctx context.Context, route client.Object) map[gatewayv1.AnnotationKey]gatewayv1.AnnotationValue {
+	options := make(map[gatewayv1.AnnotationKey]gatewayv1.AnnotationValue, len(r.TLSOptions))
+	for key, value := range r.TLSOptions {
+		options[gatewayv1.AnnotationKey(key)] = gatewayv1.AnnotationValue(value)
+	}
+	if profile := r.routeProfile(ctx, route); profile != nil {
+		for key, value := range profile.TLSOptions {
+			options[gatewayv1.AnnotationKey(key)] = gatewayv1.AnnotationValue(value)
+		}
+	}
+
+	if value, exists := route.GetAnnotations()[AnnotationTLSOptions]; exists {
+		routeOptions, err := parseTLSOptions(value)
+		if err != nil {
+			logf.FromContext(ctx).Error(err, "Invalid TLS options annotation, ignoring it", "route", route.GetName(),
+				"namespace", route.GetNamespace(), "value", value)
+		}
+		for key, val := range routeOptions {
+			options[key] = val
+		}
+	}
+
+	if len(options) == 0 {
+		return nil
+	}
+	return options
+}
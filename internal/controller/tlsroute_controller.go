@@ -0,0 +1,45 @@
+package controller
+
+import (
+	"context"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+)
+
+// TLSRouteReconciler reconciles a TLSRoute object
+type TLSRouteReconciler struct {
+	*GatewayManager
+}
+
+// +kubebuilder:rbac:groups=gateway.networking.k8s.io,resources=tlsroutes,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=gateway.networking.k8s.io,resources=tlsroutes/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=gateway.networking.k8s.io,resources=tlsroutes/finalizers,verbs=update
+
+// Reconcile ensures the Gateway referenced by a TLSRoute has TLS listeners for its hostnames.
+// The listeners terminate TLS unless the route selects passthrough with the tls-mode annotation.
+func (r *TLSRouteReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	// Fetch the TLSRoute
+	var tlsRoute gatewayv1alpha2.TLSRoute
+	if err := r.Get(ctx, req.NamespacedName, &tlsRoute); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	return r.reconcileRoute(ctx, &tlsRoute, tlsRoute.Spec.ParentRefs)
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *TLSRouteReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&gatewayv1alpha2.TLSRoute{}, builder.WithPredicates(routeChanged)).
+		Watches(&gatewayv1.Gateway{}, handler.EnqueueRequestsFromMapFunc(r.routesForDeletedGateway(&gatewayv1alpha2.TLSRouteList{})),
+			builder.WithPredicates(gatewayDeleted)).
+		WatchesRawSource(r.startupSource("TLSRoute")).
+		Named("tlsroute").
+		WithOptions(r.controllerOptions()).
+		Complete(r)
+}
@@ -0,0 +1,313 @@
+package controller
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"time"
+
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// +kubebuilder:rbac:groups=admissionregistration.k8s.io,resources=mutatingwebhookconfigurations;validatingwebhookconfigurations,verbs=get;list;update
+
+const (
+	// webhookCertCheckInterval is how often the webhook certificates are checked for renewal
+	webhookCertCheckInterval = time.Hour
+
+	// webhookCAValidity and webhookCertValidity are how long the generated CA and serving
+	// certificate are valid
+	webhookCAValidity   = 10 * 365 * 24 * time.Hour
+	webhookCertValidity = 365 * 24 * time.Hour
+
+	// webhookCertRenewBefore is how long before expiry the serving certificate, or the CA, is renewed
+	webhookCertRenewBefore = 30 * 24 * time.Hour
+
+	// webhookCAKey and webhookCAKeyKey hold the generated CA in the certificate secret
+	webhookCAKey    = "ca.crt"
+	webhookCAKeyKey = "ca.key"
+)
+
+// WebhookCertManager generates the serving certificate of the webhook server and renews it before
+// it expires, so installing the webhooks doesn't need cert-manager. The certificate and the CA
+// signing it are kept in a secret shared by all replicas, each replica writes the certificate to
+// the directory the webhook server reads it from, and the CA is patched into the webhook
+// configurations pointing to the webhook service.
+type WebhookCertManager struct {
+	*GatewayManager
+
+	// Secret is the namespace and name of the secret holding the certificates
+	Secret client.ObjectKey
+
+	// Service is the namespace and name of the webhook service the certificate is issued for
+	Service client.ObjectKey
+
+	// CertDir, CertName and KeyName locate the certificate files of the webhook server
+	CertDir  string
+	CertName string
+	KeyName  string
+}
+
+// Start renews the certificates when needed until the context is cancelled
+func (m *WebhookCertManager) Start(ctx context.Context) error {
+	log := logf.FromContext(ctx).WithName("webhook-certs")
+
+	wait.UntilWithContext(ctx, func(ctx context.Context) {
+		if err := m.EnsureCertificates(logf.IntoContext(ctx, log)); err != nil {
+			log.Error(err, "Failed to ensure webhook certificates")
+		}
+	}, webhookCertCheckInterval)
+	return nil
+}
+
+// NeedLeaderElection makes every replica keep its certificate files current
+func (m *WebhookCertManager) NeedLeaderElection() bool {
+	return false
+}
+
+// EnsureCertificates makes sure the certificate secret holds a valid serving certificate, writes
+// it to the certificate directory and patches the CA into the webhook configurations. It is run
+// once before the manager starts, since the webhook server needs the certificate to start.
+// The secret is read without the cache, since only the metadata of secrets is cached.
+func (m *WebhookCertManager) EnsureCertificates(ctx context.Context) error {
+	log := logf.FromContext(ctx)
+
+	var secret corev1.Secret
+	exists := true
+	if err := m.APIReader.Get(ctx, m.Secret, &secret); err != nil {
+		if !errors.IsNotFound(err) {
+			return err
+		}
+		exists = false
+		secret = corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      m.Secret.Name,
+				Namespace: m.Secret.Namespace,
+				Labels:    map[string]string{managedByLabel: managedByValue},
+			},
+			Type: corev1.SecretTypeTLS,
+		}
+	}
+
+	data, renewed, err := m.renewCertificates(secret.Data)
+	if err != nil {
+		return err
+	}
+	if renewed {
+		secret.Data = data
+		if exists {
+			err = m.Update(ctx, &secret)
+		} else {
+			err = m.Create(ctx, &secret)
+		}
+		if err != nil {
+			// Another replica renewed the certificates at the same time, they are picked up on
+			// the next check
+			if errors.IsAlreadyExists(err) || errors.IsConflict(err) {
+				log.Info("Webhook certificates were renewed by another replica, retrying", "secret", m.Secret.String())
+				return m.EnsureCertificates(ctx)
+			}
+			return err
+		}
+		log.Info("Renewed webhook certificates", "secret", m.Secret.String())
+	}
+
+	if err := m.writeCertificates(secret.Data); err != nil {
+		return err
+	}
+	return m.injectCABundle(ctx, secret.Data[webhookCAKey])
+}
+
+// renewCertificates returns the certificate data with a new serving certificate when the current
+// one is missing, invalid or close to expiry, and with a new CA as well when the CA is. It
+// reports whether anything was renewed.
+func (m *WebhookCertManager) renewCertificates(data map[string][]byte) (map[string][]byte, bool, error) {
+	now := time.Now()
+	caCert, caKey, err := parseKeyPair(data[webhookCAKey], data[webhookCAKeyKey])
+	renewCA := err != nil || now.Add(webhookCertRenewBefore).After(caCert.NotAfter)
+	if !renewCA {
+		cert, _, err := parseKeyPair(data[corev1.TLSCertKey], data[corev1.TLSPrivateKeyKey])
+		if err == nil && now.Add(webhookCertRenewBefore).Before(cert.NotAfter) && cert.CheckSignatureFrom(caCert) == nil {
+			return data, false, nil
+		}
+	}
+
+	renewed := make(map[string][]byte, 4)
+	if renewCA {
+		caKey, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		if err != nil {
+			return nil, false, err
+		}
+		template := &x509.Certificate{
+			Subject:               pkix.Name{CommonName: "gatewayapi-operator-webhook-ca"},
+			NotBefore:             now.Add(-time.Hour),
+			NotAfter:              now.Add(webhookCAValidity),
+			KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+			BasicConstraintsValid: true,
+			IsCA:                  true,
+		}
+		if caCert, renewed[webhookCAKey], renewed[webhookCAKeyKey], err = signCertificate(template, nil, caKey, caKey); err != nil {
+			return nil, false, err
+		}
+	} else {
+		renewed[webhookCAKey], renewed[webhookCAKeyKey] = data[webhookCAKey], data[webhookCAKeyKey]
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, false, err
+	}
+	template := &x509.Certificate{
+		Subject: pkix.Name{CommonName: m.Service.Name + "." + m.Service.Namespace + ".svc"},
+		DNSNames: []string{
+			m.Service.Name,
+			m.Service.Name + "." + m.Service.Namespace,
+			m.Service.Name + "." + m.Service.Namespace + ".svc",
+			m.Service.Name + "." + m.Service.Namespace + ".svc.cluster.local",
+		},
+		NotBefore:   now.Add(-time.Hour),
+		NotAfter:    now.Add(webhookCertValidity),
+		KeyUsage:    x509.KeyUsageDigitalSignature,
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	if _, renewed[corev1.TLSCertKey], renewed[corev1.TLSPrivateKeyKey], err = signCertificate(template, caCert, key, caKey); err != nil {
+		return nil, false, err
+	}
+	return renewed, true, nil
+}
+
+// writeCertificates writes the serving certificate to the certificate directory when it changed.
+// The webhook server watches the files and reloads the certificate.
+func (m *WebhookCertManager) writeCertificates(data map[string][]byte) error {
+	if err := os.MkdirAll(m.CertDir, 0o700); err != nil {
+		return err
+	}
+	for name, key := range map[string]string{m.CertName: corev1.TLSCertKey, m.KeyName: corev1.TLSPrivateKeyKey} {
+		path := filepath.Join(m.CertDir, name)
+		if current, err := os.ReadFile(path); err == nil && bytes.Equal(current, data[key]) {
+			continue
+		}
+		if err := os.WriteFile(path, data[key], 0o600); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// injectCABundle sets the CA bundle of every webhook calling the webhook service
+func (m *WebhookCertManager) injectCABundle(ctx context.Context, caBundle []byte) error {
+	log := logf.FromContext(ctx)
+	forService := func(config admissionregistrationv1.WebhookClientConfig) bool {
+		return config.Service != nil && config.Service.Name == m.Service.Name && config.Service.Namespace == m.Service.Namespace &&
+			!bytes.Equal(config.CABundle, caBundle)
+	}
+
+	var mutating admissionregistrationv1.MutatingWebhookConfigurationList
+	if err := m.APIReader.List(ctx, &mutating); err != nil {
+		return err
+	}
+	for i := range mutating.Items {
+		configuration := &mutating.Items[i]
+		changed := false
+		for j := range configuration.Webhooks {
+			if forService(configuration.Webhooks[j].ClientConfig) {
+				configuration.Webhooks[j].ClientConfig.CABundle = caBundle
+				changed = true
+			}
+		}
+		if !changed {
+			continue
+		}
+		if err := m.Update(ctx, configuration); err != nil {
+			return err
+		}
+		log.Info("Injected CA bundle into webhook configuration", "mutatingWebhookConfiguration", configuration.Name)
+	}
+
+	var validating admissionregistrationv1.ValidatingWebhookConfigurationList
+	if err := m.APIReader.List(ctx, &validating); err != nil {
+		return err
+	}
+	for i := range validating.Items {
+		configuration := &validating.Items[i]
+		changed := false
+		for j := range configuration.Webhooks {
+			if forService(configuration.Webhooks[j].ClientConfig) {
+				configuration.Webhooks[j].ClientConfig.CABundle = caBundle
+				changed = true
+			}
+		}
+		if !changed {
+			continue
+		}
+		if err := m.Update(ctx, configuration); err != nil {
+			return err
+		}
+		log.Info("Injected CA bundle into webhook configuration", "validatingWebhookConfiguration", configuration.Name)
+	}
+	return nil
+}
+
+// parseKeyPair parses a PEM encoded certificate and its ECDSA private key
+func parseKeyPair(certPEM, keyPEM []byte) (*x509.Certificate, *ecdsa.PrivateKey, error) {
+	cert, err := parseCertificate(certPEM)
+	if err != nil {
+		return nil, nil, err
+	}
+	block, _ := pem.Decode(keyPEM)
+	if block == nil || block.Type != "EC PRIVATE KEY" {
+		return nil, nil, fmt.Errorf("no PEM encoded EC private key")
+	}
+	key, err := x509.ParseECPrivateKey(block.Bytes)
+	if err != nil {
+		return nil, nil, err
+	}
+	return cert, key, nil
+}
+
+// signCertificate creates the certificate of the template signed by the parent, or self-signed
+// when parent is nil, and returns it with the PEM encoded certificate and private key
+func signCertificate(
+	template, parent *x509.Certificate,
+	key, signer *ecdsa.PrivateKey,
+) (*x509.Certificate, []byte, []byte, error) {
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	template.SerialNumber = serial
+	if parent == nil {
+		parent = template
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, signer)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return cert,
+		pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}),
+		nil
+}
@@ -0,0 +1,71 @@
+package controller
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// zoneMigrationAgreed reports whether every route owning the gateway asks for the zone, so the
+// gateway can move there without leaving a route behind in the zone it is in
+func (r *GatewayManager) zoneMigrationAgreed(ctx context.Context, gateway *gatewayv1.Gateway, zone string) (bool, error) {
+	routes, err := r.gatewayOwners(ctx, gateway.Name, gateway.Namespace)
+	if err != nil {
+		return false, err
+	}
+	for _, route := range routes {
+		if r.routeIPAMZone(ctx, route.Object) != zone {
+			return false, nil
+		}
+	}
+	return len(routes) > 0, nil
+}
+
+// migrateGatewayZone moves a gateway to another IPAM zone by updating the zone of its
+// infrastructure in place. The load balancer stack provisions the gateway's Service in the new zone,
+// so the gateway gets an address there. An IPAddressClaim from the previous zone's pool is deleted,
+// so the next listener update claims an address from the new zone's pool.
+func (r *GatewayManager) migrateGatewayZone(ctx context.Context, route client.Object, gateway *gatewayv1.Gateway, zone string) error {
+	log := logf.FromContext(ctx)
+	previousZone := gatewayZone(gateway)
+
+	patch := client.MergeFrom(gateway.DeepCopy())
+	if gateway.Spec.Infrastructure == nil {
+		gateway.Spec.Infrastructure = &gatewayv1.GatewayInfrastructure{}
+	}
+	if gateway.Spec.Infrastructure.Annotations == nil {
+		gateway.Spec.Infrastructure.Annotations = make(map[gatewayv1.AnnotationKey]gatewayv1.AnnotationValue)
+	}
+	gateway.Spec.Infrastructure.Annotations[AnnotationIPAMZone] = gatewayv1.AnnotationValue(zone)
+	if err := r.Patch(ctx, gateway, patch, client.FieldOwner(fieldManager)); err != nil {
+		return err
+	}
+	r.expectGateway(gateway)
+	r.audit(ctx, auditActionUpdate, "Gateway", gateway, "previousZone", previousZone, "zone", zone)
+
+	claim := &unstructured.Unstructured{}
+	claim.SetGroupVersionKind(ipAddressClaimGVK)
+	if err := r.Get(ctx, client.ObjectKey{Name: gateway.Name + ipAddressClaimSuffix, Namespace: gateway.Namespace}, claim); err == nil {
+		if metav1.IsControlledBy(claim, gateway) {
+			if err := r.Delete(ctx, claim); client.IgnoreNotFound(err) != nil {
+				return err
+			}
+		}
+	} else if len(r.ZoneIPPools) > 0 && client.IgnoreNotFound(err) != nil {
+		return err
+	}
+
+	log.Info("Migrated Gateway to another IPAM zone", "gateway", gateway.Name, "namespace", gateway.Namespace,
+		"previousZone", previousZone, "zone", zone)
+	r.Recorder.Eventf(gateway, corev1.EventTypeNormal, "ZoneMigrated",
+		"Moved the Gateway from IPAM zone %s to %s, all its routes ask for it", previousZone, zone)
+	r.Recorder.Eventf(route, corev1.EventTypeNormal, "ZoneMigrated",
+		"Moved Gateway %s/%s from IPAM zone %s to %s, its address changes, update the DNS records of the route's hostnames",
+		gateway.Namespace, gateway.Name, previousZone, zone)
+	return nil
+}